@@ -0,0 +1,233 @@
+package jsonschema
+
+import (
+	"slices"
+	"strconv"
+)
+
+// RemoveAdditional returns copy of instance v, with the properties
+// not allowed by `additionalProperties: false` or
+// `unevaluatedProperties: false` removed. It also returns
+// json-pointers of the removed properties, in instance.
+//
+// This can be used to sanitize payloads, rather than rejecting them.
+// Use [Schema.Validate] to validate the result.
+//
+// The schemas applied to a value are found by following `$ref`,
+// `allOf`, `dependentSchemas` and the valid subschemas of `anyOf`,
+// `oneOf`, `if`-`then`-`else`. The validity of subschemas is decided
+// ignoring the properties, which are not allowed.
+//
+// The v is not modified.
+func (sch *Schema) RemoveAdditional(v any) (any, []string) {
+	ra := &removeAdditional{}
+	v = ra.value([]*Schema{sch}, v, "")
+	return v, ra.removed
+}
+
+type removeAdditional struct {
+	removed []string
+}
+
+func (ra *removeAdditional) value(schemas []*Schema, v any, vloc string) any {
+	var applied []*Schema
+	for _, sch := range schemas {
+		applied = appliedSchemas(sch, v, applied)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		pnames := sortedKeys(v)
+		notAllowed := notAllowedProps(applied, v, pnames)
+
+		obj := make(map[string]any, len(v))
+		for _, pname := range pnames {
+			ploc := vloc + "/" + escape(pname)
+			if notAllowed[pname] {
+				ra.removed = append(ra.removed, ploc)
+				continue
+			}
+			obj[pname] = ra.value(propSchemas(applied, pname), v[pname], ploc)
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i] = ra.value(itemSchemas(applied, i), item, vloc+"/"+strconv.Itoa(i))
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+// appliedSchemas appends to result, sch and the schemas
+// applied in-place to v, if not already present.
+func appliedSchemas(sch *Schema, v any, result []*Schema) []*Schema {
+	if sch == nil || slices.Contains(result, sch) {
+		return result
+	}
+	result = append(result, sch)
+	for _, ref := range sch.refs() {
+		result = appliedSchemas(ref, v, result)
+	}
+	for _, s := range sch.AllOf {
+		result = appliedSchemas(s, v, result)
+	}
+	for _, subschemas := range [][]*Schema{sch.AnyOf, sch.OneOf} {
+		for _, s := range branches(subschemas, v) {
+			result = appliedSchemas(s, v, result)
+		}
+	}
+	if sch.If != nil {
+		if validRelaxed(sch.If, v) {
+			result = appliedSchemas(sch.If, v, result)
+			result = appliedSchemas(sch.Then, v, result)
+		} else {
+			result = appliedSchemas(sch.Else, v, result)
+		}
+	}
+	if obj, ok := v.(map[string]any); ok {
+		for pname, s := range sch.DependentSchemas {
+			if _, ok := obj[pname]; ok {
+				result = appliedSchemas(s, v, result)
+			}
+		}
+	}
+	return result
+}
+
+// branches returns subschemas of `anyOf` or `oneOf`, to be applied
+// to v. These are the subschemas, v is valid against. If there are
+// none, the subschemas v is valid against, ignoring properties not
+// allowed, are used. Among them, those which remove fewest properties
+// are preferred, for example the matching variant of tagged union,
+// whose subschemas have `additionalProperties: false`.
+func branches(subschemas []*Schema, v any) []*Schema {
+	var valid, relaxed []*Schema
+	for _, s := range subschemas {
+		if s.Validate(v) == nil {
+			valid = append(valid, s)
+		} else if validRelaxed(s, v) {
+			relaxed = append(relaxed, s)
+		}
+	}
+	obj, ok := v.(map[string]any)
+	if len(valid) > 0 || !ok {
+		return valid
+	}
+
+	pnames := sortedKeys(obj)
+	var result []*Schema
+	least := -1
+	for _, s := range relaxed {
+		n := len(notAllowedProps(appliedSchemas(s, v, nil), obj, pnames))
+		switch {
+		case least == -1 || n < least:
+			least, result = n, []*Schema{s}
+		case n == least:
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// validRelaxed tells whether v is valid against sch, ignoring
+// `additionalProperties` and `unevaluatedProperties` being false.
+func validRelaxed(sch *Schema, v any) bool {
+	_, err := sch.validateWith(v, runOpts{relaxAdditional: true})
+	return err == nil
+}
+
+// notAllowedProps returns properties of obj, not allowed by
+// `additionalProperties: false` or `unevaluatedProperties: false`
+// of applied schemas. pnames are sorted property names of obj.
+func notAllowedProps(applied []*Schema, obj map[string]any, pnames []string) map[string]bool {
+	notAllowed := map[string]bool{}
+	for _, sch := range applied {
+		var evaluated map[string]bool
+		if isFalse(sch.UnevaluatedProperties) {
+			evaluated = map[string]bool{}
+			for _, s := range appliedSchemas(sch, obj, nil) {
+				evaluatedProps(s, s != sch, pnames, evaluated)
+			}
+		}
+		for _, pname := range pnames {
+			if isFalse(sch.AdditionalProperties) && !matchesProps(sch, pname) {
+				notAllowed[pname] = true
+			}
+			if evaluated != nil && !evaluated[pname] {
+				notAllowed[pname] = true
+			}
+		}
+	}
+	return notAllowed
+}
+
+// propSchemas returns schemas applicable to property pname,
+// from the given schemas.
+func propSchemas(schemas []*Schema, pname string) []*Schema {
+	var result []*Schema
+	for _, sch := range schemas {
+		if s, ok := sch.Properties[pname]; ok {
+			result = append(result, s)
+		}
+		for re, s := range sch.PatternProperties {
+			if re.MatchString(pname) {
+				result = append(result, s)
+			}
+		}
+		if s, ok := sch.AdditionalProperties.(*Schema); ok && !matchesProps(sch, pname) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// itemSchemas returns schemas applicable to i-th item,
+// from the given schemas.
+func itemSchemas(schemas []*Schema, i int) []*Schema {
+	var result []*Schema
+	for _, sch := range schemas {
+		if s := itemSchema(sch, i); s != nil {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// evaluatedProps adds to evaluated, the properties evaluated
+// by sch, not including its applicators. uneval tells whether
+// to consider unevaluatedProperties of sch.
+func evaluatedProps(sch *Schema, uneval bool, pnames []string, evaluated map[string]bool) {
+	for _, pname := range pnames {
+		if sch.AdditionalProperties != nil || (uneval && sch.UnevaluatedProperties != nil) || matchesProps(sch, pname) {
+			evaluated[pname] = true
+		}
+	}
+}
+
+// matchesProps tells whether pname matches
+// properties or patternProperties of sch.
+func matchesProps(sch *Schema, pname string) bool {
+	if _, ok := sch.Properties[pname]; ok {
+		return true
+	}
+	for re := range sch.PatternProperties {
+		if re.MatchString(pname) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFalse tells whether v is false or false schema.
+func isFalse(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return !v
+	case *Schema:
+		return v != nil && v.Bool != nil && !*v.Bool
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestRemoveAdditional(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		inst    string
+		want    string
+		removed []string
+	}{
+		{
+			"additionalProperties",
+			`{"properties": {"a": {"additionalProperties": false, "patternProperties": {"^x": true}}}, "additionalProperties": false}`,
+			`{"a": {"xy": 1, "y": 2}, "b": 3}`,
+			`{"a": {"xy": 1}}`,
+			[]string{"/a/y", "/b"},
+		},
+		{
+			"unevaluatedProperties",
+			`{"allOf": [{"properties": {"a": true}}], "properties": {"b": true}, "unevaluatedProperties": false}`,
+			`{"a": 1, "b": 2, "c": 3}`,
+			`{"a": 1, "b": 2}`,
+			[]string{"/c"},
+		},
+		{
+			"conditional",
+			`{
+				"if": {"properties": {"kind": {"const": "x"}}},
+				"then": {"properties": {"x": true}},
+				"else": {"properties": {"y": true}},
+				"properties": {"kind": true},
+				"unevaluatedProperties": false
+			}`,
+			`{"kind": "x", "x": 1, "y": 2}`,
+			`{"kind": "x", "x": 1}`,
+			[]string{"/y"},
+		},
+		{
+			"anyOfValidBranch",
+			`{"anyOf": [{"required": ["a"], "properties": {"a": true}}, {"required": ["b"], "properties": {"b": true}}], "unevaluatedProperties": false}`,
+			`{"b": 1, "c": 2}`,
+			`{"b": 1}`,
+			[]string{"/c"},
+		},
+		{
+			"taggedUnion",
+			`{"oneOf": [{"properties": {"a": true}, "additionalProperties": false}, {"properties": {"b": true}, "additionalProperties": false}]}`,
+			`{"a": 1, "x": 2}`,
+			`{"a": 1}`,
+			[]string{"/x"},
+		},
+		{
+			"taggedUnionUnevaluated",
+			`{
+				"oneOf": [
+					{"properties": {"kind": {"const": "a"}, "a": true}},
+					{"properties": {"kind": {"const": "b"}, "b": {"properties": {"c": true}, "additionalProperties": false}}}
+				],
+				"unevaluatedProperties": false
+			}`,
+			`{"kind": "b", "b": {"c": 1, "y": 3}, "x": 2}`,
+			`{"kind": "b", "b": {"c": 1}}`,
+			[]string{"/b/y", "/x"},
+		},
+		{
+			"items",
+			`{"$defs": {"item": {"properties": {"id": true}, "additionalProperties": false}}, "items": {"$ref": "#/$defs/item"}}`,
+			`[{"id": 1, "x": 2}, {"id": 2}]`,
+			`[{"id": 1}, {"id": 2}]`,
+			[]string{"/0/x"},
+		},
+		{
+			"allowed",
+			`{"properties": {"a": true}, "additionalProperties": {"type": "object", "additionalProperties": false}}`,
+			`{"a": 1, "b": {"c": 1}}`,
+			`{"a": 1, "b": {}}`,
+			[]string{"/b/c"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := jsonschema.NewCompiler()
+			if err := c.AddResource("schema.json", schema); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.inst))
+			if err != nil {
+				t.Fatal(err)
+			}
+			orig, _ := jsonschema.UnmarshalJSON(strings.NewReader(test.inst))
+			want, err := jsonschema.UnmarshalJSON(strings.NewReader(test.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, removed := sch.RemoveAdditional(inst)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(removed, test.removed) {
+				t.Fatalf("removed got %v, want %v", removed, test.removed)
+			}
+			if !reflect.DeepEqual(inst, orig) {
+				t.Fatal("instance is modified")
+			}
+			if err := sch.Validate(got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
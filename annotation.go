@@ -0,0 +1,28 @@
+package jsonschema
+
+// Annotation is the value attached by a keyword
+// to an instance location.
+type Annotation struct {
+	// location of the JSON value within the instance.
+	InstanceLocation []string
+
+	// absolute, dereferenced location of schema,
+	// which contains the keyword.
+	SchemaURL string
+
+	Keyword string
+	Value   any
+}
+
+// Annotations validates v, and returns annotations added by
+// vocabularies using [ValidatorContext.AddAnnotation], in the
+// order they are added.
+//
+// If v is not valid, nil and the validation error are returned.
+func (sch *Schema) Annotations(v any) ([]*Annotation, error) {
+	ue, err := sch.validateWith(v, runOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return ue.annots, nil
+}
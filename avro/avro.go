@@ -0,0 +1,411 @@
+// Package avro converts between compiled json schemas and
+// [Avro] schemas, for a useful subset of both.
+//
+// Following are converted in both directions:
+//   - null, boolean, string types as is
+//   - integer to long, or int if bounded by int32 range
+//   - number to double
+//   - array to array, object with only `additionalProperties` to map
+//   - object with `properties` to record, with optional properties
+//     as union with null and default null
+//   - `enum` of strings to enum
+//   - multiple types, `oneOf` and `anyOf` to union
+//   - formats date-time, date, time and uuid to logical types
+//
+// Note that [jsonschema.Schema.Format] is available only if format
+// assertions are enabled, see [jsonschema.Compiler.AssertFormat].
+//
+// Records and enums referenced multiple times are converted to a single
+// named type, which allows recursive records. Recursion, which does not
+// go through a record or enum, is not supported.
+//
+// [Avro]: https://avro.apache.org/docs/1.11.1/specification/
+package avro
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Options configures [FromJSONSchema].
+type Options struct {
+	// Name of the root named type, used if root schema has no
+	// `title`, which is a valid avro name. Defaults to "Root".
+	Name string
+
+	// Namespace of the named types.
+	Namespace string
+}
+
+// FromJSONSchema returns avro schema for sch, as json value.
+// It returns [*UnsupportedError] if sch uses constructs which
+// cannot be represented in avro.
+func FromJSONSchema(sch *jsonschema.Schema, opts *Options) (any, error) {
+	e := &encoder{
+		names: map[*jsonschema.Schema]string{},
+		used:  map[string]bool{},
+		path:  map[*jsonschema.Schema]bool{},
+	}
+	if opts != nil {
+		e.opts = *opts
+	}
+	name := e.opts.Name
+	if name == "" {
+		name = "Root"
+	}
+	return e.convert(sch, name)
+}
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type encoder struct {
+	opts  Options
+	names map[*jsonschema.Schema]string // schemas converted to named types
+	used  map[string]bool               // names used so far
+	path  map[*jsonschema.Schema]bool   // schemas being converted
+}
+
+func (e *encoder) convert(sch *jsonschema.Schema, name string) (any, error) {
+	if sch.Bool != nil {
+		return nil, e.unsupported(sch, "boolean schema")
+	}
+	if e.path[sch] {
+		if fullname, ok := e.names[sch]; ok {
+			return fullname, nil
+		}
+		return nil, e.unsupported(sch, "recursion without record or enum")
+	}
+	e.path[sch] = true
+	defer delete(e.path, sch)
+
+	if sch.Ref != nil {
+		if hasSiblings(sch) {
+			return nil, e.unsupported(sch, "$ref with sibling keywords")
+		}
+		return e.convert(sch.Ref, refName(sch.Ref, name))
+	}
+
+	if branches := append(slices.Clip(sch.OneOf), sch.AnyOf...); len(branches) > 0 {
+		if sch.Types != nil || len(sch.Properties) > 0 {
+			return nil, e.unsupported(sch, "oneOf/anyOf with sibling type")
+		}
+		var union []any
+		for i, b := range branches {
+			t, err := e.convert(b, name+strconv.Itoa(i+1))
+			if err != nil {
+				return nil, err
+			}
+			union = append(union, t)
+		}
+		return e.union(sch, union)
+	}
+
+	types := e.types(sch)
+	if len(types) == 0 {
+		return nil, e.unsupported(sch, "cannot infer type")
+	}
+	if len(types) == 1 {
+		return e.convertType(sch, types[0], name)
+	}
+	var union []any
+	for _, t := range types {
+		at, err := e.convertType(sch, t, name)
+		if err != nil {
+			return nil, err
+		}
+		union = append(union, at)
+	}
+	return e.union(sch, union)
+}
+
+// hasSiblings tells whether sch has keywords other than `$ref`,
+// which are used in conversion.
+func hasSiblings(sch *jsonschema.Schema) bool {
+	return sch.Types != nil || sch.Enum != nil || sch.Format != nil ||
+		len(sch.Properties) > 0 || sch.AdditionalProperties != nil ||
+		sch.Items != nil || sch.Items2020 != nil ||
+		len(sch.OneOf) > 0 || len(sch.AnyOf) > 0
+}
+
+// types returns json types of sch. If `type` is missing,
+// it is inferred from other keywords.
+func (e *encoder) types(sch *jsonschema.Schema) []string {
+	if sch.Types != nil {
+		types := sch.Types.ToStrings()
+		// integer is subset of number
+		if slices.Contains(types, "integer") && slices.Contains(types, "number") {
+			types = slices.DeleteFunc(types, func(t string) bool { return t == "integer" })
+		}
+		return types
+	}
+	switch {
+	case len(sch.Properties) > 0 || sch.AdditionalProperties != nil:
+		return []string{"object"}
+	case sch.Items != nil || sch.Items2020 != nil:
+		return []string{"array"}
+	case sch.Enum != nil:
+		var types []string
+		for _, v := range sch.Enum.Values {
+			switch v.(type) {
+			case nil:
+				types = append(types, "null")
+			case string:
+				types = append(types, "string")
+			}
+		}
+		slices.Sort(types)
+		return slices.Compact(types)
+	}
+	return nil
+}
+
+func (e *encoder) convertType(sch *jsonschema.Schema, t, name string) (any, error) {
+	switch t {
+	case "null", "boolean":
+		return t, nil
+	case "integer":
+		if fitsInt32(sch.Minimum, sch.Maximum) {
+			return "int", nil
+		}
+		return "long", nil
+	case "number":
+		return "double", nil
+	case "string":
+		if symbols, ok := enumSymbols(sch); ok {
+			return e.named(sch, name, func(fullname string) (map[string]any, error) {
+				return map[string]any{"type": "enum", "name": fullname, "symbols": symbols}, nil
+			})
+		}
+		if sch.Enum != nil {
+			return nil, e.unsupported(sch, "enum values are not valid avro names")
+		}
+		if sch.Format != nil {
+			switch sch.Format.Name {
+			case "date-time":
+				return map[string]any{"type": "long", "logicalType": "timestamp-millis"}, nil
+			case "date":
+				return map[string]any{"type": "int", "logicalType": "date"}, nil
+			case "time":
+				return map[string]any{"type": "int", "logicalType": "time-millis"}, nil
+			case "uuid":
+				return map[string]any{"type": "string", "logicalType": "uuid"}, nil
+			}
+		}
+		return "string", nil
+	case "array":
+		items := sch.Items2020
+		if s, ok := sch.Items.(*jsonschema.Schema); ok {
+			items = s
+		}
+		if items == nil {
+			return nil, e.unsupported(sch, "array without items schema")
+		}
+		it, err := e.convert(items, name+"Item")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": it}, nil
+	case "object":
+		if len(sch.Properties) > 0 {
+			return e.named(sch, name, func(fullname string) (map[string]any, error) {
+				return e.record(sch, fullname)
+			})
+		}
+		if values, ok := sch.AdditionalProperties.(*jsonschema.Schema); ok {
+			vt, err := e.convert(values, name+"Value")
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"type": "map", "values": vt}, nil
+		}
+		return nil, e.unsupported(sch, "object without properties or additionalProperties schema")
+	}
+	return nil, e.unsupported(sch, "type "+t)
+}
+
+func (e *encoder) record(sch *jsonschema.Schema, fullname string) (map[string]any, error) {
+	var pnames []string
+	for pname := range sch.Properties {
+		pnames = append(pnames, pname)
+	}
+	slices.Sort(pnames)
+
+	var fields []any
+	for _, pname := range pnames {
+		if !nameRegexp.MatchString(pname) {
+			return nil, e.unsupported(sch, fmt.Sprintf("property %q is not valid avro name", pname))
+		}
+		psch := sch.Properties[pname]
+		ft, err := e.convert(psch, pascalCase(pname))
+		if err != nil {
+			return nil, err
+		}
+		field := map[string]any{"name": pname}
+		if !slices.Contains(sch.Required, pname) {
+			// default null requires null to be first in union
+			union, _ := ft.([]any)
+			if union == nil {
+				union = []any{ft}
+			}
+			union = slices.DeleteFunc(slices.Clone(union), func(t any) bool { return t == "null" })
+			ft = append([]any{"null"}, union...)
+			field["default"] = nil
+		}
+		field["type"] = ft
+		if psch.Description != "" {
+			field["doc"] = psch.Description
+		}
+		fields = append(fields, field)
+	}
+	rec := map[string]any{"type": "record", "name": fullname, "fields": fields}
+	if sch.Description != "" {
+		rec["doc"] = sch.Description
+	}
+	return rec, nil
+}
+
+// named returns avro named type for sch, created using fn.
+// The name is registered before calling fn, so that recursive
+// references to sch use the name.
+func (e *encoder) named(sch *jsonschema.Schema, name string, fn func(fullname string) (map[string]any, error)) (any, error) {
+	if fullname, ok := e.names[sch]; ok {
+		return fullname, nil
+	}
+	if nameRegexp.MatchString(sch.Title) {
+		name = sch.Title
+	}
+	base := name
+	for i := 2; e.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	e.used[name] = true
+	fullname := name
+	if e.opts.Namespace != "" {
+		fullname = e.opts.Namespace + "." + name
+	}
+	e.names[sch] = fullname
+	return fn(fullname)
+}
+
+// union flattens nested unions, and checks that
+// union has no duplicate types.
+func (e *encoder) union(sch *jsonschema.Schema, types []any) (any, error) {
+	var union []any
+	seen := map[string]bool{}
+	for _, t := range types {
+		members, ok := t.([]any)
+		if !ok {
+			members = []any{t}
+		}
+		for _, m := range members {
+			key := typeKey(m)
+			if seen[key] {
+				return nil, e.unsupported(sch, "union with multiple "+key)
+			}
+			seen[key] = true
+			union = append(union, m)
+		}
+	}
+	if len(union) == 1 {
+		return union[0], nil
+	}
+	return union, nil
+}
+
+// typeKey returns the key by which avro identifies
+// members of union: name for named types, otherwise type.
+func typeKey(t any) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case map[string]any:
+		if name, ok := t["name"].(string); ok {
+			return name
+		}
+		return fmt.Sprint(t["type"])
+	}
+	return fmt.Sprint(t)
+}
+
+func (e *encoder) unsupported(sch *jsonschema.Schema, reason string) error {
+	return &UnsupportedError{Location: sch.Location, Reason: reason}
+}
+
+// refName returns name for $ref target sch, which is
+// last token of its location, if it is valid avro name.
+func refName(sch *jsonschema.Schema, fallback string) string {
+	if i := strings.LastIndexAny(sch.Location, "/#"); i != -1 {
+		if name := pascalCase(sch.Location[i+1:]); nameRegexp.MatchString(name) {
+			return name
+		}
+	}
+	return fallback
+}
+
+func enumSymbols(sch *jsonschema.Schema) ([]any, bool) {
+	if sch.Enum == nil {
+		return nil, false
+	}
+	var symbols []any
+	for _, v := range sch.Enum.Values {
+		switch v := v.(type) {
+		case nil:
+			continue // handled by union with null
+		case string:
+			if !nameRegexp.MatchString(v) {
+				return nil, false
+			}
+			symbols = append(symbols, v)
+		default:
+			return nil, false
+		}
+	}
+	return symbols, len(symbols) > 0
+}
+
+func fitsInt32(min, max *big.Rat) bool {
+	if min == nil || max == nil {
+		return false
+	}
+	return min.Cmp(big.NewRat(math.MinInt32, 1)) >= 0 && max.Cmp(big.NewRat(math.MaxInt32, 1)) <= 0
+}
+
+func pascalCase(s string) string {
+	var sb strings.Builder
+	upper := true
+	for _, ch := range s {
+		switch {
+		case ch == '_' || ch == '-' || ch == ' ':
+			upper = true
+		case upper:
+			sb.WriteString(strings.ToUpper(string(ch)))
+			upper = false
+		default:
+			sb.WriteRune(ch)
+		}
+	}
+	return sb.String()
+}
+
+// --
+
+// UnsupportedError is returned by [FromJSONSchema], if
+// schema cannot be represented in avro.
+type UnsupportedError struct {
+	// Location is absolute url of the schema.
+	Location string
+
+	// Reason describes what is not supported.
+	Reason string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("avro: unsupported schema at %s: %s", e.Location, e.Reason)
+}
@@ -0,0 +1,217 @@
+package avro_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/avro"
+)
+
+func compile(t *testing.T, schema string) *jsonschema.Schema {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func mustJSON(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// normalize converts v to generic json value.
+func normalize(t *testing.T, v any) any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mustJSON(t, string(b))
+}
+
+const petSchema = `{
+	"title": "Pet",
+	"type": "object",
+	"properties": {
+		"id": { "type": "integer", "minimum": 0, "maximum": 2147483647 },
+		"name": { "type": "string", "description": "display name" },
+		"born": { "type": "string", "format": "date-time" },
+		"kind": { "enum": ["DOG", "CAT"] },
+		"tags": { "type": "array", "items": { "type": "string" } },
+		"attrs": { "type": "object", "additionalProperties": { "type": "number" } },
+		"owner": { "$ref": "#/$defs/person" }
+	},
+	"required": ["id", "name", "kind"],
+	"$defs": {
+		"person": {
+			"type": "object",
+			"properties": {
+				"name": { "type": "string" },
+				"friend": { "$ref": "#/$defs/person" }
+			},
+			"required": ["name"]
+		}
+	}
+}`
+
+func TestFromJSONSchema(t *testing.T) {
+	sch := compile(t, petSchema)
+	got, err := avro.FromJSONSchema(sch, &avro.Options{Namespace: "com.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mustJSON(t, `{
+		"type": "record",
+		"name": "com.example.Pet",
+		"fields": [
+			{ "name": "attrs", "type": ["null", { "type": "map", "values": "double" }], "default": null },
+			{ "name": "born", "type": ["null", { "type": "long", "logicalType": "timestamp-millis" }], "default": null },
+			{ "name": "id", "type": "int" },
+			{ "name": "kind", "type": { "type": "enum", "name": "com.example.Kind", "symbols": ["DOG", "CAT"] } },
+			{ "name": "name", "type": "string", "doc": "display name" },
+			{
+				"name": "owner",
+				"type": ["null", {
+					"type": "record",
+					"name": "com.example.Person",
+					"fields": [
+						{ "name": "friend", "type": ["null", "com.example.Person"], "default": null },
+						{ "name": "name", "type": "string" }
+					]
+				}],
+				"default": null
+			},
+			{ "name": "tags", "type": ["null", { "type": "array", "items": "string" }], "default": null }
+		]
+	}`)
+	if got := normalize(t, got); !reflect.DeepEqual(got, want) {
+		b, _ := json.MarshalIndent(got, "", "  ")
+		t.Fatalf("got:\n%s", b)
+	}
+}
+
+func TestFromJSONSchemaUnsupported(t *testing.T) {
+	tests := []string{
+		`true`,
+		`{"type": "array"}`,
+		`{"type": "object"}`,
+		`{"enum": ["a b"]}`,
+		`{"oneOf": [{"type": "string"}, {"type": "string"}]}`,
+		`{"type": "object", "properties": {"a-b": {"type": "string"}}}`,
+		`{"type": "array", "items": {"$ref": "#"}}`,
+		`{"type": "object", "additionalProperties": {"$ref": "#"}}`,
+		`{"$ref": "#/$defs/a", "items": {"type": "integer"}, "$defs": {"a": {"type": "array", "items": {"type": "string"}}}}`,
+	}
+	for _, test := range tests {
+		_, err := avro.FromJSONSchema(compile(t, test), nil)
+		var uerr *avro.UnsupportedError
+		if !errors.As(err, &uerr) {
+			t.Errorf("%s: want UnsupportedError, got %v", test, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	avsc, err := avro.FromJSONSchema(compile(t, petSchema), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := avro.ToJSONSchema(normalize(t, avsc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch := compile(t, string(mustMarshal(t, doc)))
+
+	valid := `{"id": 1, "name": "tom", "kind": "CAT", "born": "2020-01-01T00:00:00Z", "owner": {"name": "x", "friend": {"name": "y"}}}`
+	if err := sch.Validate(mustJSON(t, valid)); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []string{
+		`{"id": 1, "name": "tom", "kind": "COW"}`,
+		`{"id": 3000000000, "name": "tom", "kind": "CAT"}`,
+		`{"id": 1, "kind": "CAT"}`,
+		`{"id": 1, "name": "tom", "kind": "CAT", "owner": {"friend": {}}}`,
+	}
+	for _, inst := range invalid {
+		if err := sch.Validate(mustJSON(t, inst)); err == nil {
+			t.Errorf("%s: want validation error", inst)
+		}
+	}
+
+	// avro schema of round trip is same
+	avsc2, err := avro.FromJSONSchema(sch, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(normalize(t, avsc2), normalize(t, avsc)) {
+		t.Fatalf("got %v, want %v", avsc2, avsc)
+	}
+}
+
+func TestToJSONSchemaOverlappingUnion(t *testing.T) {
+	tests := []struct {
+		avsc  string
+		valid []string
+	}{
+		{`["int", "long"]`, []string{`5`}},
+		{`["float", "double"]`, []string{`1.5`}},
+		{`["string", "bytes"]`, []string{`"x"`}},
+		{`[{"type": "fixed", "name": "F", "size": 2}, "string"]`, []string{`"ab"`}},
+	}
+	for _, test := range tests {
+		doc, err := avro.ToJSONSchema(mustJSON(t, test.avsc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sch := compile(t, string(mustMarshal(t, doc)))
+		for _, inst := range test.valid {
+			if err := sch.Validate(mustJSON(t, inst)); err != nil {
+				t.Errorf("%s: %s: %v", test.avsc, inst, err)
+			}
+		}
+	}
+}
+
+func TestToJSONSchemaInvalid(t *testing.T) {
+	tests := []string{
+		`"Unknown"`,
+		`{"type": "record", "fields": []}`,
+		`{"type": "enum", "name": "E"}`,
+		`[{"type": "enum", "name": "E", "symbols": ["A"]}, {"type": "enum", "name": "E", "symbols": ["B"]}]`,
+	}
+	for _, test := range tests {
+		_, err := avro.ToJSONSchema(mustJSON(t, test))
+		var ierr *avro.InvalidSchemaError
+		if !errors.As(err, &ierr) {
+			t.Errorf("%s: want InvalidSchemaError, got %v", test, err)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,203 @@
+package avro
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ToJSONSchema returns json schema document for avro schema avsc,
+// which is json value. Named types are defined in `$defs`, using
+// their full names as keys, and are referenced using `$ref`.
+//
+// It returns [*InvalidSchemaError] if avsc is not valid.
+func ToJSONSchema(avsc any) (map[string]any, error) {
+	d := &decoder{defs: map[string]any{}}
+	sch, err := d.convert(avsc, "")
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]any{"$schema": "https://json-schema.org/draft/2020-12/schema"}
+	for k, v := range sch {
+		doc[k] = v
+	}
+	if len(d.defs) > 0 {
+		doc["$defs"] = d.defs
+	}
+	return doc, nil
+}
+
+type decoder struct {
+	defs map[string]any // fullname -> schema
+}
+
+func (d *decoder) convert(t any, namespace string) (map[string]any, error) {
+	switch t := t.(type) {
+	case string:
+		if sch, ok := primitiveSchema(t); ok {
+			return sch, nil
+		}
+		fullname := fullName(t, namespace)
+		if _, ok := d.defs[fullname]; !ok {
+			return nil, &InvalidSchemaError{fmt.Sprintf("unknown type %q", t)}
+		}
+		return ref(fullname), nil
+	case []any:
+		// union members may overlap in json, for example int and
+		// long, so anyOf is used rather than oneOf
+		var anyOf []any
+		for _, m := range t {
+			sch, err := d.convert(m, namespace)
+			if err != nil {
+				return nil, err
+			}
+			anyOf = append(anyOf, sch)
+		}
+		return map[string]any{"anyOf": anyOf}, nil
+	case map[string]any:
+		return d.convertComplex(t, namespace)
+	}
+	return nil, &InvalidSchemaError{fmt.Sprintf("invalid type %v", t)}
+}
+
+func (d *decoder) convertComplex(t map[string]any, namespace string) (map[string]any, error) {
+	typ, _ := t["type"].(string)
+	if lt, ok := t["logicalType"].(string); ok {
+		if format, ok := logicalFormats[lt]; ok {
+			return map[string]any{"type": "string", "format": format}, nil
+		}
+		if lt == "decimal" {
+			return map[string]any{"type": "number"}, nil
+		}
+		// unknown logical types must be ignored
+	}
+	switch typ {
+	case "array":
+		items, err := d.convert(t["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case "map":
+		values, err := d.convert(t["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case "record", "error", "enum", "fixed":
+		name, _ := t["name"].(string)
+		if name == "" {
+			return nil, &InvalidSchemaError{typ + " without name"}
+		}
+		if ns, ok := t["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		fullname := fullName(name, namespace)
+		if _, ok := d.defs[fullname]; ok {
+			return nil, &InvalidSchemaError{fmt.Sprintf("type %q redefined", fullname)}
+		}
+		if i := strings.LastIndexByte(fullname, '.'); i != -1 {
+			namespace = fullname[:i]
+		}
+		d.defs[fullname] = nil // for recursive references
+		sch, err := d.convertNamed(t, typ, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if doc, ok := t["doc"].(string); ok {
+			sch["description"] = doc
+		}
+		d.defs[fullname] = sch
+		return ref(fullname), nil
+	}
+	return d.convert(typ, namespace)
+}
+
+func (d *decoder) convertNamed(t map[string]any, typ, namespace string) (map[string]any, error) {
+	switch typ {
+	case "enum":
+		symbols, ok := t["symbols"].([]any)
+		if !ok {
+			return nil, &InvalidSchemaError{"enum without symbols"}
+		}
+		return map[string]any{"type": "string", "enum": symbols}, nil
+	case "fixed":
+		return map[string]any{"type": "string"}, nil
+	}
+
+	fields, _ := t["fields"].([]any)
+	props := map[string]any{}
+	required := []any{}
+	for _, f := range fields {
+		f, ok := f.(map[string]any)
+		if !ok {
+			return nil, &InvalidSchemaError{"invalid record field"}
+		}
+		fname, _ := f["name"].(string)
+		if fname == "" {
+			return nil, &InvalidSchemaError{"record field without name"}
+		}
+		sch, err := d.convert(f["type"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		if doc, ok := f["doc"].(string); ok {
+			sch["description"] = doc
+		}
+		props[fname] = sch
+		if _, ok := f["default"]; !ok {
+			required = append(required, fname)
+		}
+	}
+	return map[string]any{"type": "object", "properties": props, "required": required}, nil
+}
+
+var logicalFormats = map[string]string{
+	"timestamp-millis":       "date-time",
+	"timestamp-micros":       "date-time",
+	"local-timestamp-millis": "date-time",
+	"local-timestamp-micros": "date-time",
+	"date":                   "date",
+	"time-millis":            "time",
+	"time-micros":            "time",
+	"uuid":                   "uuid",
+}
+
+func primitiveSchema(t string) (map[string]any, bool) {
+	switch t {
+	case "null", "boolean", "string":
+		return map[string]any{"type": t}, true
+	case "int":
+		return map[string]any{"type": "integer", "minimum": math.MinInt32, "maximum": math.MaxInt32}, true
+	case "long":
+		return map[string]any{"type": "integer"}, true
+	case "float", "double":
+		return map[string]any{"type": "number"}, true
+	case "bytes":
+		return map[string]any{"type": "string"}, true
+	}
+	return nil, false
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func ref(fullname string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + fullname}
+}
+
+// --
+
+// InvalidSchemaError is returned by [ToJSONSchema],
+// if avro schema is not valid.
+type InvalidSchemaError struct {
+	Reason string
+}
+
+func (e *InvalidSchemaError) Error() string {
+	return "avro: invalid schema: " + e.Reason
+}
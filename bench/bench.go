@@ -0,0 +1,90 @@
+// Package bench provides realistic schemas and instances, to measure
+// performance of compiling schemas and validating instances.
+//
+// The benchmarks are run using go test:
+//
+//	go test -run=^$ -bench=. -count=10 ./bench > old.txt
+//	# make changes
+//	go test -run=^$ -bench=. -count=10 ./bench > new.txt
+//	benchstat old.txt new.txt
+//
+// To profile a benchmark, use profiling flags of go test:
+//
+//	go test -run=^$ -bench=Validate/openapi -cpuprofile cpu.out -memprofile mem.out ./bench
+//	go tool pprof cpu.out
+//
+// The cases are exported, so that the same schemas and instances
+// can be used to compare with other validators.
+package bench
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"path"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+//go:embed testdata
+var testdata embed.FS
+
+// Case is a schema along with an instance which is valid against it.
+type Case struct {
+	Name string
+
+	// URL is the url of schema.
+	URL string
+
+	// Schema and Instance are the raw json documents.
+	Schema   []byte
+	Instance []byte
+}
+
+// Cases returns all cases, in sorted order of name.
+func Cases() ([]*Case, error) {
+	entries, err := fs.ReadDir(testdata, "testdata")
+	if err != nil {
+		return nil, err
+	}
+	var cases []*Case
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := path.Join("testdata", entry.Name())
+		schema, err := testdata.ReadFile(path.Join(dir, "schema.json"))
+		if err != nil {
+			return nil, err
+		}
+		inst, err := testdata.ReadFile(path.Join(dir, "instance.json"))
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, &Case{
+			Name:     entry.Name(),
+			URL:      "https://bench.example.com/" + entry.Name() + ".json",
+			Schema:   schema,
+			Instance: inst,
+		})
+	}
+	return cases, nil
+}
+
+// Compile compiles the schema of this case.
+func (c *Case) Compile() (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(c.Schema))
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(c.URL, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(c.URL)
+}
+
+// UnmarshalInstance returns the instance of this case.
+func (c *Case) UnmarshalInstance() (any, error) {
+	return jsonschema.UnmarshalJSON(bytes.NewReader(c.Instance))
+}
@@ -0,0 +1,115 @@
+package bench_test
+
+import (
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/bench"
+)
+
+func cases(tb testing.TB) []*bench.Case {
+	tb.Helper()
+	cases, err := bench.Cases()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cases
+}
+
+func TestCases(t *testing.T) {
+	for _, c := range cases(t) {
+		t.Run(c.Name, func(t *testing.T) {
+			sch, err := c.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			inst, err := c.UnmarshalInstance()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sch.Validate(inst); err != nil {
+				t.Fatalf("%#v", err)
+			}
+		})
+	}
+}
+
+func BenchmarkCompile(b *testing.B) {
+	for _, c := range cases(b) {
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Compile(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	for _, c := range cases(b) {
+		b.Run(c.Name, func(b *testing.B) {
+			sch, err := c.Compile()
+			if err != nil {
+				b.Fatal(err)
+			}
+			inst, err := c.UnmarshalInstance()
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := sch.Validate(inst); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkIsValid(b *testing.B) {
+	for _, c := range cases(b) {
+		b.Run(c.Name, func(b *testing.B) {
+			sch, err := c.Compile()
+			if err != nil {
+				b.Fatal(err)
+			}
+			inst, err := c.UnmarshalInstance()
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !sch.IsValid(inst) {
+					b.Fatal("invalid")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidator(b *testing.B) {
+	for _, c := range cases(b) {
+		b.Run(c.Name, func(b *testing.B) {
+			sch, err := c.Compile()
+			if err != nil {
+				b.Fatal(err)
+			}
+			inst, err := c.UnmarshalInstance()
+			if err != nil {
+				b.Fatal(err)
+			}
+			v := jsonschema.NewValidator(sch)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := v.Validate(inst); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package jsonschema
+
+// SchemaBuilder constructs schema document programmatically,
+// for validation rules defined in go code rather than json files:
+//
+//	sch, err := jsonschema.NewObjectSchema().
+//		Property("name", jsonschema.StringSchema().MinLength(1)).
+//		Property("age", jsonschema.IntegerSchema().Minimum(0)).
+//		Required("name").
+//		Build(c)
+//
+// The document built uses draft 2020-12. Keywords without
+// dedicated method can be specified using [SchemaBuilder.Keyword].
+//
+// The methods modify and return the receiver, for chaining.
+// A builder added as subschema of other builder, is copied
+// only when document is built. So it must not be modified
+// after being added, unless the change is intended for
+// all the places it is added to.
+type SchemaBuilder struct {
+	kws map[string]any // values may have *SchemaBuilder
+}
+
+// NewSchema returns builder of schema, that allows
+// any value, to be constrained by further calls.
+func NewSchema() *SchemaBuilder {
+	return &SchemaBuilder{kws: map[string]any{}}
+}
+
+func typedSchema(t string) *SchemaBuilder {
+	return NewSchema().Keyword("type", t)
+}
+
+// NewObjectSchema returns builder of schema with type "object".
+func NewObjectSchema() *SchemaBuilder {
+	return typedSchema("object")
+}
+
+// NewArraySchema returns builder of schema with type "array",
+// whose items are validated against items, if not nil.
+func NewArraySchema(items *SchemaBuilder) *SchemaBuilder {
+	b := typedSchema("array")
+	if items != nil {
+		b.Keyword("items", items)
+	}
+	return b
+}
+
+// StringSchema returns builder of schema with type "string".
+func StringSchema() *SchemaBuilder {
+	return typedSchema("string")
+}
+
+// NumberSchema returns builder of schema with type "number".
+func NumberSchema() *SchemaBuilder {
+	return typedSchema("number")
+}
+
+// IntegerSchema returns builder of schema with type "integer".
+func IntegerSchema() *SchemaBuilder {
+	return typedSchema("integer")
+}
+
+// BooleanSchema returns builder of schema with type "boolean".
+func BooleanSchema() *SchemaBuilder {
+	return typedSchema("boolean")
+}
+
+// NullSchema returns builder of schema with type "null".
+func NullSchema() *SchemaBuilder {
+	return typedSchema("null")
+}
+
+// Keyword sets keyword kw to value. The value must be valid json
+// value, in form accepted by [Compiler.AddResource], and may have
+// *SchemaBuilder in place of subschemas. It replaces value set
+// earlier, including by other methods.
+func (b *SchemaBuilder) Keyword(kw string, value any) *SchemaBuilder {
+	b.kws[kw] = value
+	return b
+}
+
+// Nullable adds "null" to the type of schema.
+func (b *SchemaBuilder) Nullable() *SchemaBuilder {
+	switch t := b.kws["type"].(type) {
+	case string:
+		if t != "null" {
+			b.kws["type"] = []any{t, "null"}
+		}
+	case []any:
+		for _, item := range t {
+			if item == "null" {
+				return b
+			}
+		}
+		b.kws["type"] = append(t, "null")
+	}
+	return b
+}
+
+// Title sets `title`.
+func (b *SchemaBuilder) Title(title string) *SchemaBuilder {
+	return b.Keyword("title", title)
+}
+
+// Description sets `description`.
+func (b *SchemaBuilder) Description(desc string) *SchemaBuilder {
+	return b.Keyword("description", desc)
+}
+
+// Default sets `default`.
+func (b *SchemaBuilder) Default(v any) *SchemaBuilder {
+	return b.Keyword("default", v)
+}
+
+// Const sets `const`.
+func (b *SchemaBuilder) Const(v any) *SchemaBuilder {
+	return b.Keyword("const", v)
+}
+
+// Enum sets `enum`.
+func (b *SchemaBuilder) Enum(values ...any) *SchemaBuilder {
+	return b.Keyword("enum", values)
+}
+
+// Format sets `format`. Note that format is not asserted,
+// unless enabled by [Compiler.AssertFormat].
+func (b *SchemaBuilder) Format(format string) *SchemaBuilder {
+	return b.Keyword("format", format)
+}
+
+// Ref sets `$ref`. The ref is resolved same as in [Compiler.CompileValue]:
+// fragment-only ref like "#/$defs/name" resolves within the document
+// built, and other relative refs resolve against `$id` of the document,
+// which can be set using [SchemaBuilder.Keyword]. Without `$id`, the
+// ref must be either fragment-only or absolute url.
+func (b *SchemaBuilder) Ref(ref string) *SchemaBuilder {
+	return b.Keyword("$ref", ref)
+}
+
+// AllOf sets `allOf`.
+func (b *SchemaBuilder) AllOf(schemas ...*SchemaBuilder) *SchemaBuilder {
+	return b.Keyword("allOf", schemas)
+}
+
+// AnyOf sets `anyOf`.
+func (b *SchemaBuilder) AnyOf(schemas ...*SchemaBuilder) *SchemaBuilder {
+	return b.Keyword("anyOf", schemas)
+}
+
+// OneOf sets `oneOf`.
+func (b *SchemaBuilder) OneOf(schemas ...*SchemaBuilder) *SchemaBuilder {
+	return b.Keyword("oneOf", schemas)
+}
+
+// Not sets `not`.
+func (b *SchemaBuilder) Not(sch *SchemaBuilder) *SchemaBuilder {
+	return b.Keyword("not", sch)
+}
+
+// object --
+
+// Property adds property with given name, to `properties`.
+func (b *SchemaBuilder) Property(name string, sch *SchemaBuilder) *SchemaBuilder {
+	props, ok := b.kws["properties"].(map[string]any)
+	if !ok {
+		props = map[string]any{}
+		b.kws["properties"] = props
+	}
+	props[name] = sch
+	return b
+}
+
+// PatternProperty adds schema for properties matching
+// given regex pattern, to `patternProperties`.
+func (b *SchemaBuilder) PatternProperty(pattern string, sch *SchemaBuilder) *SchemaBuilder {
+	props, ok := b.kws["patternProperties"].(map[string]any)
+	if !ok {
+		props = map[string]any{}
+		b.kws["patternProperties"] = props
+	}
+	props[pattern] = sch
+	return b
+}
+
+// Required adds names to `required`.
+func (b *SchemaBuilder) Required(names ...string) *SchemaBuilder {
+	reqd, _ := b.kws["required"].([]any)
+	for _, name := range names {
+		reqd = append(reqd, name)
+	}
+	return b.Keyword("required", reqd)
+}
+
+// AdditionalProperties sets `additionalProperties`.
+// Use [SchemaBuilder.NoAdditionalProperties] to disallow them.
+func (b *SchemaBuilder) AdditionalProperties(sch *SchemaBuilder) *SchemaBuilder {
+	return b.Keyword("additionalProperties", sch)
+}
+
+// NoAdditionalProperties sets `additionalProperties` to false.
+func (b *SchemaBuilder) NoAdditionalProperties() *SchemaBuilder {
+	return b.Keyword("additionalProperties", false)
+}
+
+// MinProperties sets `minProperties`.
+func (b *SchemaBuilder) MinProperties(n int) *SchemaBuilder {
+	return b.Keyword("minProperties", n)
+}
+
+// MaxProperties sets `maxProperties`.
+func (b *SchemaBuilder) MaxProperties(n int) *SchemaBuilder {
+	return b.Keyword("maxProperties", n)
+}
+
+// array --
+
+// Items sets `items`.
+func (b *SchemaBuilder) Items(sch *SchemaBuilder) *SchemaBuilder {
+	return b.Keyword("items", sch)
+}
+
+// MinItems sets `minItems`.
+func (b *SchemaBuilder) MinItems(n int) *SchemaBuilder {
+	return b.Keyword("minItems", n)
+}
+
+// MaxItems sets `maxItems`.
+func (b *SchemaBuilder) MaxItems(n int) *SchemaBuilder {
+	return b.Keyword("maxItems", n)
+}
+
+// UniqueItems sets `uniqueItems` to true.
+func (b *SchemaBuilder) UniqueItems() *SchemaBuilder {
+	return b.Keyword("uniqueItems", true)
+}
+
+// string --
+
+// MinLength sets `minLength`.
+func (b *SchemaBuilder) MinLength(n int) *SchemaBuilder {
+	return b.Keyword("minLength", n)
+}
+
+// MaxLength sets `maxLength`.
+func (b *SchemaBuilder) MaxLength(n int) *SchemaBuilder {
+	return b.Keyword("maxLength", n)
+}
+
+// Pattern sets `pattern`.
+func (b *SchemaBuilder) Pattern(pattern string) *SchemaBuilder {
+	return b.Keyword("pattern", pattern)
+}
+
+// number --
+
+// Minimum sets `minimum`.
+func (b *SchemaBuilder) Minimum(n float64) *SchemaBuilder {
+	return b.Keyword("minimum", n)
+}
+
+// Maximum sets `maximum`.
+func (b *SchemaBuilder) Maximum(n float64) *SchemaBuilder {
+	return b.Keyword("maximum", n)
+}
+
+// ExclusiveMinimum sets `exclusiveMinimum`.
+func (b *SchemaBuilder) ExclusiveMinimum(n float64) *SchemaBuilder {
+	return b.Keyword("exclusiveMinimum", n)
+}
+
+// ExclusiveMaximum sets `exclusiveMaximum`.
+func (b *SchemaBuilder) ExclusiveMaximum(n float64) *SchemaBuilder {
+	return b.Keyword("exclusiveMaximum", n)
+}
+
+// MultipleOf sets `multipleOf`.
+func (b *SchemaBuilder) MultipleOf(n float64) *SchemaBuilder {
+	return b.Keyword("multipleOf", n)
+}
+
+// --
+
+// Doc returns the schema document built, with `$schema`
+// of draft 2020-12. The document returned is not shared
+// with b, and can be modified.
+func (b *SchemaBuilder) Doc() map[string]any {
+	doc := b.doc()
+	doc["$schema"] = Draft2020.url
+	return doc
+}
+
+func (b *SchemaBuilder) doc() map[string]any {
+	doc := make(map[string]any, len(b.kws))
+	for kw, v := range b.kws {
+		doc[kw] = buildValue(v)
+	}
+	return doc
+}
+
+// buildValue returns copy of v, with builders
+// replaced by their documents.
+func buildValue(v any) any {
+	switch v := v.(type) {
+	case *SchemaBuilder:
+		return v.doc()
+	case []*SchemaBuilder:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i] = item.doc()
+		}
+		return arr
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i] = buildValue(item)
+		}
+		return arr
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for k, item := range v {
+			obj[k] = buildValue(item)
+		}
+		return obj
+	default:
+		return v
+	}
+}
+
+// Build compiles the schema document built, using c.
+// see [Compiler.CompileValue].
+func (b *SchemaBuilder) Build(c *Compiler) (*Schema, error) {
+	return c.CompileValue(b.Doc())
+}
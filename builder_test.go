@@ -0,0 +1,95 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestSchemaBuilder(t *testing.T) {
+	address := jsonschema.NewObjectSchema().
+		Property("city", jsonschema.StringSchema().MinLength(1)).
+		Required("city")
+	b := jsonschema.NewObjectSchema().
+		Property("name", jsonschema.StringSchema().MinLength(1)).
+		Property("age", jsonschema.IntegerSchema().Minimum(0).Nullable()).
+		Property("tags", jsonschema.NewArraySchema(jsonschema.StringSchema()).UniqueItems()).
+		Property("home", address).
+		Property("work", address).
+		Required("name").
+		NoAdditionalProperties()
+	sch, err := b.Build(jsonschema.NewCompiler())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := []any{
+		map[string]any{"name": "john"},
+		map[string]any{"name": "john", "age": 30, "tags": []any{"a", "b"}},
+		map[string]any{"name": "john", "age": nil, "home": map[string]any{"city": "x"}},
+	}
+	for _, v := range valid {
+		if err := sch.Validate(v); err != nil {
+			t.Errorf("%v: %v", v, err)
+		}
+	}
+	invalid := []any{
+		map[string]any{},
+		map[string]any{"name": ""},
+		map[string]any{"name": "john", "age": -1},
+		map[string]any{"name": "john", "tags": []any{"a", "a"}},
+		map[string]any{"name": "john", "work": map[string]any{}},
+		map[string]any{"name": "john", "other": 1},
+	}
+	for _, v := range invalid {
+		if err := sch.Validate(v); err == nil {
+			t.Errorf("%v: want error", v)
+		}
+	}
+
+	// Doc is not shared with builder
+	doc := b.Doc()
+	doc["properties"].(map[string]any)["home"].(map[string]any)["type"] = "string"
+	want := address.Doc()
+	delete(want, "$schema")
+	if !reflect.DeepEqual(b.Doc()["properties"].(map[string]any)["home"], want) {
+		t.Error("builder modified via Doc")
+	}
+	if doc["$schema"] != jsonschema.Draft2020.String() {
+		t.Errorf("$schema: got %v", doc["$schema"])
+	}
+}
+
+func TestSchemaBuilderRef(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/name.json", map[string]any{"type": "string"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		b    *jsonschema.SchemaBuilder
+	}{
+		{"fragment", jsonschema.NewSchema().
+			Keyword("$defs", map[string]any{"name": jsonschema.StringSchema()}).
+			Ref("#/$defs/name")},
+		{"relative", jsonschema.NewSchema().
+			Keyword("$id", "http://example.com/root.json").
+			Ref("name.json")},
+		{"absolute", jsonschema.NewSchema().Ref("http://example.com/name.json")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sch, err := test.b.Build(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sch.Validate("x"); err != nil {
+				t.Fatal(err)
+			}
+			if err := sch.Validate(1); err == nil {
+				t.Fatal("want error")
+			}
+		})
+	}
+}
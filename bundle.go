@@ -0,0 +1,103 @@
+package jsonschema
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Bundle compiles schema at loc, and returns its document, with all
+// external schema resources referenced from it, directly or indirectly,
+// embedded in `$defs` (`definitions` before draft 2019). Each embedded
+// resource is keyed by its url. The document and embedded resources
+// are given `$id` with their url, if they do not have one, so that
+// references resolve as before. Embedded resources are given `$schema`,
+// if their dialect differs from that of the document. Standard
+// metaschemas are not embedded.
+//
+// Before draft 2019, siblings of `$ref` are ignored. So for such
+// document or embedded resource, `$ref` is moved into `allOf`, and
+// its ignored siblings other than `$schema` and `definitions` are dropped.
+//
+// The returned document can be compiled without loading any
+// other resources. Fragment of loc is ignored, and documents
+// given to [Compiler.AddResource] or loaded are not modified.
+func (c *Compiler) Bundle(loc string) (any, error) {
+	sch, err := c.Compile(loc)
+	if err != nil {
+		return nil, err
+	}
+	rootURL := sch.up.url
+
+	var urls []url
+	walkSchemas(sch, func(s *Schema) {
+		u := s.up.url
+		if u != rootURL && !slices.Contains(urls, u) && !isMeta(string(u)) {
+			urls = append(urls, u)
+		}
+	})
+	slices.Sort(urls)
+
+	r := c.roots.roots[rootURL]
+	doc := deepClone(r.doc)
+	obj, ok := doc.(map[string]any)
+	if !ok || len(urls) == 0 {
+		return doc, nil
+	}
+	dialect := r.rootResource().dialect
+	draft := dialect.draft
+	obj = wrapRef(obj, draft)
+	if _, ok := obj[draft.id]; !ok {
+		// so that relative references resolve as before
+		obj[draft.id] = string(rootURL)
+	}
+	defsKw := "$defs"
+	if draft.version < 2019 {
+		defsKw = "definitions"
+	}
+	defs, ok := obj[defsKw].(map[string]any)
+	if !ok {
+		defs = map[string]any{}
+		obj[defsKw] = defs
+	}
+	for _, u := range urls {
+		r := c.roots.roots[u]
+		d := r.rootResource().dialect
+		var embed map[string]any
+		switch v := deepClone(r.doc).(type) {
+		case map[string]any:
+			embed = wrapRef(v, d.draft)
+		default:
+			// boolean schema cannot have id
+			embed = map[string]any{"allOf": []any{v}}
+		}
+		if _, ok := embed[d.draft.id]; !ok {
+			embed[d.draft.id] = string(u)
+		}
+		if _, ok := embed["$schema"]; !ok && d.metaURL() != dialect.metaURL() {
+			embed["$schema"] = d.metaURL()
+		}
+		key := string(u)
+		for i := 2; defs[key] != nil; i++ {
+			key = fmt.Sprintf("%s~%d", u, i)
+		}
+		defs[key] = embed
+	}
+	return obj, nil
+}
+
+// wrapRef returns obj with its `$ref` moved into `allOf`, if draft
+// ignores siblings of `$ref`, so that keywords added to it are not
+// ignored. Siblings other than `$schema` and `definitions` are dropped.
+func wrapRef(obj map[string]any, draft *Draft) map[string]any {
+	ref, ok := obj["$ref"]
+	if !ok || draft.version >= 2019 {
+		return obj
+	}
+	wrapped := map[string]any{"allOf": []any{map[string]any{"$ref": ref}}}
+	for _, kw := range []string{"$schema", "definitions"} {
+		if v, ok := obj[kw]; ok {
+			wrapped[kw] = v
+		}
+	}
+	return wrapped
+}
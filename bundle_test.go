@@ -0,0 +1,132 @@
+package jsonschema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+type noLoader struct{}
+
+func (noLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("loading %q not allowed", url)
+}
+
+func TestBundle(t *testing.T) {
+	resources := map[string]string{
+		"http://example.com/pet.json": `{
+			"oneOf": [{"$ref": "dog.json"}, {"$ref": "cat.json"}]
+		}`,
+		"http://example.com/dog.json": `{
+			"properties": {"name": {"$ref": "common.json#/$defs/name"}, "barks": {"const": true}},
+			"required": ["barks"]
+		}`,
+		"http://example.com/cat.json": `{
+			"$id": "http://example.com/cat.json",
+			"properties": {"name": {"$ref": "common.json#/$defs/name"}, "meows": {"const": true}},
+			"required": ["meows"]
+		}`,
+		"http://example.com/common.json": `{
+			"$defs": {"name": {"type": "string"}}
+		}`,
+	}
+	c := jsonschema.NewCompiler()
+	for url, schema := range resources {
+		if err := c.AddResourceJSON(url, []byte(schema)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc, err := c.Bundle("http://example.com/pet.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs := doc.(map[string]any)["$defs"].(map[string]any)
+	if len(defs) != 3 {
+		t.Fatalf("got %d embedded resources, want 3", len(defs))
+	}
+	if id := defs["http://example.com/common.json"].(map[string]any)["$id"]; id != "http://example.com/common.json" {
+		t.Fatalf("got $id %v", id)
+	}
+
+	bc := jsonschema.NewCompiler()
+	bc.UseLoader(noLoader{})
+	if err := bc.AddResource("http://other.com/bundled.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := bc.Compile("http://other.com/bundled.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance any
+		valid    bool
+	}{
+		{map[string]any{"name": "rex", "barks": true}, true},
+		{map[string]any{"name": "tom", "meows": true}, true},
+		{map[string]any{"name": 1, "barks": true}, false},
+		{map[string]any{"name": "x"}, false},
+	}
+	for _, test := range tests {
+		if got := sch.Validate(test.instance) == nil; got != test.valid {
+			t.Errorf("%v: got valid %v, want %v", test.instance, got, test.valid)
+		}
+	}
+}
+
+func TestBundleDraft7Ref(t *testing.T) {
+	resources := map[string]string{
+		"http://example.com/root.json": `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"$ref": "http://other.com/other.json",
+			"type": "number"
+		}`,
+		"http://other.com/other.json": `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"$ref": "name.json"
+		}`,
+		"http://other.com/name.json": `{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"type": "string",
+			"maxLength": 3
+		}`,
+	}
+	c := jsonschema.NewCompiler()
+	for url, schema := range resources {
+		if err := c.AddResourceJSON(url, []byte(schema)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc, err := c.Bundle("http://example.com/root.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs := doc.(map[string]any)["definitions"].(map[string]any)
+	name := defs["http://other.com/name.json"].(map[string]any)
+	if name["id"] != "http://other.com/name.json" {
+		t.Fatalf("got id %v", name["id"])
+	}
+
+	bc := jsonschema.NewCompiler()
+	bc.UseLoader(noLoader{})
+	if err := bc.AddResource("http://bundled.com/bundled.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := bc.Compile("http://bundled.com/bundled.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance any
+		valid    bool
+	}{
+		{"abc", true},
+		{"abcd", false},
+		{1, false},
+	}
+	for _, test := range tests {
+		if got := sch.Validate(test.instance) == nil; got != test.valid {
+			t.Errorf("%v: got valid %v, want %v", test.instance, got, test.valid)
+		}
+	}
+}
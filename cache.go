@@ -0,0 +1,144 @@
+package jsonschema
+
+import (
+	"container/list"
+	"fmt"
+	"hash/maphash"
+	"reflect"
+	"sync"
+)
+
+// CachedSchema wraps a [Schema] and memoizes validation results
+// of recently validated instances, using LRU cache. This avoids
+// re-validating identical instances, which are validated repeatedly.
+//
+// Instances are looked up by content, not identity. Numbers match only
+// if they have same go type and text, so 1 and 1.0 are cached separately,
+// as they may validate differently. Cached instances are copied, so the
+// caller may modify instance after validation.
+//
+// It is safe for concurrent use.
+type CachedSchema struct {
+	sch     *Schema
+	size    int
+	mu      sync.Mutex
+	seed    maphash.Seed
+	lru     *list.List // front is most recently used
+	entries map[uint64][]*list.Element
+}
+
+type cacheEntry struct {
+	hash uint64
+	inst any
+	err  error
+}
+
+// NewCachedSchema returns CachedSchema, which caches
+// results of at most size recently validated instances.
+func NewCachedSchema(sch *Schema, size int) *CachedSchema {
+	return &CachedSchema{
+		sch:     sch,
+		size:    max(size, 1),
+		seed:    maphash.MakeSeed(),
+		lru:     list.New(),
+		entries: map[uint64][]*list.Element{},
+	}
+}
+
+// Schema returns the schema wrapped.
+func (cs *CachedSchema) Schema() *Schema {
+	return cs.sch
+}
+
+// Validate validates given instance against the schema,
+// returning cached result if the instance is validated recently.
+// see [Schema.Validate].
+func (cs *CachedSchema) Validate(v any) error {
+	var h maphash.Hash
+	h.SetSeed(cs.seed)
+	if k := writeHash(v, &h); k != nil {
+		// invalid json value, not cacheable
+		return cs.sch.Validate(v)
+	}
+	hash := h.Sum64()
+
+	cs.mu.Lock()
+	for _, elem := range cs.entries[hash] {
+		e := elem.Value.(*cacheEntry)
+		if identical(e.inst, v) {
+			cs.lru.MoveToFront(elem)
+			cs.mu.Unlock()
+			return e.err
+		}
+	}
+	cs.mu.Unlock()
+
+	err := cs.sch.Validate(v)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	e := &cacheEntry{hash: hash, inst: deepClone(v), err: err}
+	cs.entries[hash] = append(cs.entries[hash], cs.lru.PushFront(e))
+	for cs.lru.Len() > cs.size {
+		cs.remove(cs.lru.Back())
+	}
+	return err
+}
+
+func (cs *CachedSchema) remove(elem *list.Element) {
+	cs.lru.Remove(elem)
+	hash := elem.Value.(*cacheEntry).hash
+	elems := cs.entries[hash]
+	for i, e := range elems {
+		if e == elem {
+			elems = append(elems[:i], elems[i+1:]...)
+			break
+		}
+	}
+	if len(elems) == 0 {
+		delete(cs.entries, hash)
+	} else {
+		cs.entries[hash] = elems
+	}
+}
+
+// Len returns number of instances cached.
+func (cs *CachedSchema) Len() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.lru.Len()
+}
+
+// identical tells whether v1 and v2 are same json values.
+// Unlike equals, numbers must have same go type and text.
+func identical(v1, v2 any) bool {
+	switch v1 := v1.(type) {
+	case map[string]any:
+		v2, ok := v2.(map[string]any)
+		if !ok || len(v1) != len(v2) {
+			return false
+		}
+		for pname, e1 := range v1 {
+			e2, ok := v2[pname]
+			if !ok || !identical(e1, e2) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		v2, ok := v2.([]any)
+		if !ok || len(v1) != len(v2) {
+			return false
+		}
+		for i := range v1 {
+			if !identical(v1[i], v2[i]) {
+				return false
+			}
+		}
+		return true
+	case nil, bool, string:
+		return v1 == v2
+	default:
+		return reflect.TypeOf(v1) == reflect.TypeOf(v2) && fmt.Sprint(v1) == fmt.Sprint(v2)
+	}
+}
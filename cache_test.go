@@ -0,0 +1,83 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestCachedSchema(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{"properties": {"n": {"maximum": 10}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := jsonschema.NewCachedSchema(sch, 2)
+	valid := map[string]any{"n": 1}
+	if err := cs.Validate(valid); err != nil {
+		t.Fatal(err)
+	}
+	invalid := map[string]any{"n": 11}
+	err1 := cs.Validate(invalid)
+	if err1 == nil {
+		t.Fatal("want error")
+	}
+	if err2 := cs.Validate(map[string]any{"n": 11}); err2 != err1 {
+		t.Fatal("want cached error")
+	}
+	if cs.Len() != 2 {
+		t.Fatalf("len got %d, want 2", cs.Len())
+	}
+
+	// cached instance is copied
+	valid["n"] = 100
+	if err := cs.Validate(valid); err == nil {
+		t.Fatal("want error")
+	}
+
+	// least recently used is evicted
+	if cs.Len() != 2 {
+		t.Fatalf("len got %d, want 2", cs.Len())
+	}
+	if err := cs.Validate(map[string]any{"n": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err2 := cs.Validate(map[string]any{"n": 11}); err2 == err1 {
+		t.Fatal("want evicted")
+	}
+}
+
+func TestCachedSchemaStrictIntegers(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.StrictIntegers()
+	if err := c.AddResource("schema.json", map[string]any{"type": "integer"}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := jsonschema.NewCachedSchema(sch, 10)
+	if err := cs.Validate(json.Number("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Validate(json.Number("1.0")); err == nil {
+		t.Fatal("1.0: want error")
+	}
+	if err := cs.Validate(1.0); err == nil {
+		t.Fatal("float64: want error")
+	}
+	if cs.Len() != 3 {
+		t.Fatalf("len got %d, want 3", cs.Len())
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+	flag "github.com/spf13/pflag"
+)
+
+func bundleMain(args []string) {
+	fs := flag.NewFlagSet("bundle", flag.ExitOnError)
+	fs.Usage = func() {
+		eprintln("Usage: jv bundle [OPTIONS] SCHEMA")
+		eprintln("")
+		eprintln("Prints SCHEMA to stdout, with all external schemas")
+		eprintln("referenced from it embedded in '$defs'.")
+		eprintln("")
+		eprintln("Options:")
+		fs.PrintDefaults()
+	}
+	help := fs.BoolP("help", "h", false, "Print help information")
+	lf := addLoaderFlags(fs)
+	fs.SortFlags = false
+	_ = fs.Parse(args)
+
+	if *help {
+		fs.Usage()
+		os.Exit(0)
+	}
+	c := lf.newCompiler(fs)
+	if fs.NArg() != 1 {
+		eprintln("missing SCHEMA")
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	doc, err := c.Bundle(fs.Arg(0))
+	if err != nil {
+		eprintln("%v", err)
+		os.Exit(exitSchema)
+	}
+	printJSON(doc)
+}
+
+func resolveMain(args []string) {
+	fs := flag.NewFlagSet("resolve", flag.ExitOnError)
+	fs.Usage = func() {
+		eprintln("Usage: jv resolve [OPTIONS] SCHEMA")
+		eprintln("")
+		eprintln("Prints subschema at given json-pointer in SCHEMA to stdout,")
+		eprintln("after checking that it compiles.")
+		eprintln("")
+		eprintln("Options:")
+		fs.PrintDefaults()
+	}
+	help := fs.BoolP("help", "h", false, "Print help information")
+	ptr := fs.StringP("pointer", "p", "", "json-pointer of subschema, such as '/$defs/x'")
+	lf := addLoaderFlags(fs)
+	fs.SortFlags = false
+	_ = fs.Parse(args)
+
+	if *help {
+		fs.Usage()
+		os.Exit(0)
+	}
+	c := lf.newCompiler(fs)
+	if fs.NArg() != 1 {
+		eprintln("missing SCHEMA")
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	schema := fs.Arg(0)
+	if _, err := jsonpointer.Parse(*ptr); err != nil {
+		eprintln("invalid pointer: %v", err)
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	if _, err := c.Compile(schema + "#" + *ptr); err != nil {
+		eprintln("%v", err)
+		os.Exit(exitSchema)
+	}
+	doc, err := func() (any, error) {
+		if strings.Contains(schema, "://") {
+			return lf.loader.Load(schema)
+		}
+		return loadFile(schema)
+	}()
+	if err != nil {
+		eprintln("%v", err)
+		os.Exit(exitSchema)
+	}
+	v, err := jsonpointer.Eval(doc, *ptr)
+	if err != nil {
+		eprintln("%v", err)
+		os.Exit(exitSchema)
+	}
+	printJSON(v)
+}
+
+// --
+
+// loaderFlags are the flags, which configure
+// how schemas are loaded.
+type loaderFlags struct {
+	draftVersion *int
+	insecure     *bool
+	cacert       *string
+	maps         *[]string
+	loader       jsonschema.URLLoader
+}
+
+func addLoaderFlags(fs *flag.FlagSet) *loaderFlags {
+	return &loaderFlags{
+		draftVersion: fs.IntP("draft", "d", 2020, "Draft `version` used when '$schema' is missing. Valid values 4, 6, 7, 2019, 2020"),
+		insecure:     fs.BoolP("insecure", "k", false, "Use insecure TLS connection"),
+		cacert:       fs.String("cacert", "", "Use the specified `pem-file` to verify the peer. The file may contain multiple CA certificates"),
+		maps:         fs.StringArrayP("map", "m", nil, "load url with prefix from given directory. Syntax `url_prefix=/path/to/dir`"),
+	}
+}
+
+// newCompiler returns compiler configured with the flags.
+// It exits, if flags are not valid.
+func (lf *loaderFlags) newCompiler(fs *flag.FlagSet) *jsonschema.Compiler {
+	draft := draftFromVersion(*lf.draftVersion)
+	if draft == nil {
+		eprintln("invalid draft: %v", *lf.draftVersion)
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	mappings, err := parseMappings(*lf.maps)
+	if err != nil {
+		eprintln("%v", err)
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	lf.loader, err = newLoader(mappings, *lf.insecure, *lf.cacert)
+	if err != nil {
+		eprintln("%v", err)
+		os.Exit(exitUsage)
+	}
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(draft)
+	c.UseLoader(lf.loader)
+	return c
+}
@@ -13,12 +13,48 @@ import (
 	flag "github.com/spf13/pflag"
 )
 
+// exit codes
+const (
+	exitInvalid = 1 // some instance is not valid
+	exitUsage   = 2 // invalid command line
+	exitSchema  = 3 // schema failed to load or compile
+	exitIO      = 4 // some instance failed to load
+)
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			migrateMain(os.Args[2:])
+			return
+		case "bundle":
+			bundleMain(os.Args[2:])
+			return
+		case "resolve":
+			resolveMain(os.Args[2:])
+			return
+		case "openapi":
+			openapiMain(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Usage = func() {
 		eprintln("Usage: jv [OPTIONS] SCHEMA [INSTANCE...]")
+		eprintln("       jv migrate [OPTIONS] SCHEMA")
+		eprintln("       jv bundle [OPTIONS] SCHEMA")
+		eprintln("       jv resolve [OPTIONS] SCHEMA")
+		eprintln("       jv openapi [OPTIONS] SPEC")
 		eprintln("")
 		eprintln("Options:")
 		flag.PrintDefaults()
+		eprintln("")
+		eprintln("Exit codes:")
+		eprintln("  0  all instances are valid")
+		eprintln("  1  some instance is not valid")
+		eprintln("  2  invalid command line")
+		eprintln("  3  schema failed to load or compile")
+		eprintln("  4  some instance failed to load")
 	}
 	help := flag.BoolP("help", "h", false, "Print help information")
 	version := flag.BoolP("version", "v", false, "Print build information")
@@ -30,6 +66,10 @@ func main() {
 	insecure := flag.BoolP("insecure", "k", false, "Use insecure TLS connection")
 	cacert := flag.String("cacert", "", "Use the specified `pem-file` to verify the peer. The file may contain multiple CA certificates")
 	maps := flag.StringArrayP("map", "m", nil, "load url with prefix from given directory. Syntax `url_prefix=/path/to/dir`")
+	failFast := flag.Bool("fail-fast", false, "Stop at first invalid instance, without reporting error details")
+	maxErrors := flag.Int("max-errors", 0, "Print at most `n` errors per instance. 0 means no limit")
+	schemaInline := flag.String("schema-inline", "", "Use given `json` as SCHEMA. All arguments are then INSTANCEs")
+	dataInline := flag.StringArray("data-inline", nil, "Validate given `json` as INSTANCE. Can be repeated")
 	flag.CommandLine.SortFlags = false
 	flag.Parse()
 
@@ -53,23 +93,12 @@ func main() {
 	}
 
 	// draft --
-	var draft *jsonschema.Draft
-	switch *draftVersion {
-	case 4:
-		draft = jsonschema.Draft4
-	case 6:
-		draft = jsonschema.Draft6
-	case 7:
-		draft = jsonschema.Draft7
-	case 2019:
-		draft = jsonschema.Draft2019
-	case 2020:
-		draft = jsonschema.Draft2020
-	default:
+	draft := draftFromVersion(*draftVersion)
+	if draft == nil {
 		eprintln("invalid draft: %v", *draftVersion)
 		eprintln("")
 		flag.Usage()
-		os.Exit(2)
+		os.Exit(exitUsage)
 	}
 
 	// output --
@@ -77,50 +106,39 @@ func main() {
 		eprintln("invalid output: %v", *output)
 		eprintln("")
 		flag.Usage()
-		os.Exit(2)
+		os.Exit(exitUsage)
+	}
+
+	if *maxErrors < 0 {
+		eprintln("invalid max-errors: %v", *maxErrors)
+		eprintln("")
+		flag.Usage()
+		os.Exit(exitUsage)
 	}
 
 	// maps --
-	mappings, err := func() (map[string]string, error) {
-		mappings := map[string]string{}
-		for _, m := range *maps {
-			equal := strings.IndexByte(m, '=')
-			if equal == -1 {
-				return nil, fmt.Errorf("invalid map: %v", m)
-			}
-			u, dir := m[:equal], m[equal+1:]
-			if dir == "" {
-				return nil, fmt.Errorf("invalid map: %v", m)
-			}
-			_, err := url.Parse(u)
-			if err != nil {
-				return nil, fmt.Errorf("invalid map %v: %v", m, err)
-			}
-			if !strings.HasSuffix(u, "/") {
-				u += "/"
-			}
-			mappings[u] = dir
-		}
-		return mappings, nil
-	}()
+	mappings, err := parseMappings(*maps)
 	if err != nil {
 		eprintln("%v", err)
 		eprintln("")
 		flag.Usage()
-		os.Exit(2)
+		os.Exit(exitUsage)
 	}
 
 	stdinDecoder := json.NewDecoder(os.Stdin)
 	stdinDecoder.UseNumber()
 
 	// schema --
-	if len(flag.Args()) == 0 {
-		eprintln("missing SCHEMA")
-		eprintln("")
-		flag.Usage()
-		os.Exit(2)
+	schema, args := "inline", flag.Args()
+	if *schemaInline == "" {
+		if len(args) == 0 {
+			eprintln("missing SCHEMA")
+			eprintln("")
+			flag.Usage()
+			os.Exit(exitUsage)
+		}
+		schema, args = args[0], args[1:]
 	}
-	schema := flag.Args()[0]
 
 	// setup compiler
 	c := jsonschema.NewCompiler()
@@ -136,12 +154,19 @@ func main() {
 	loader, err := newLoader(mappings, *insecure, *cacert)
 	if err != nil {
 		eprintln("%v", err)
-		os.Exit(2)
+		os.Exit(exitUsage)
 	}
 	c.UseLoader(loader)
 
 	// compile
 	sch, err := func() (*jsonschema.Schema, error) {
+		if *schemaInline != "" {
+			// relative references resolve against current directory
+			if err := c.AddResourceJSON("inline.json", []byte(*schemaInline)); err != nil {
+				return nil, err
+			}
+			return c.Compile("inline.json")
+		}
 		if schema == "-" {
 			var v any
 			if err := stdinDecoder.Decode(&v); err != nil {
@@ -159,38 +184,58 @@ func main() {
 		if !*quiet {
 			fmt.Println(err)
 		}
-		os.Exit(1)
+		os.Exit(exitSchema)
 	}
 	fmt.Printf("schema %s: ok\n", schema)
 
-	// validate
-	allValid := true
-	for _, instance := range flag.Args()[1:] {
-		if !*quiet {
-			fmt.Println()
-		}
-		inst, err := func() (any, error) {
-			if instance == "-" {
+	// instances --
+	type source struct {
+		name string
+		load func() (any, error)
+	}
+	var sources []source
+	for _, arg := range args {
+		arg := arg
+		sources = append(sources, source{arg, func() (any, error) {
+			if arg == "-" {
 				var inst any
 				err := stdinDecoder.Decode(&inst)
 				return inst, err
 			}
-			return loadFile(instance)
-		}()
+			return loadFile(arg)
+		}})
+	}
+	for i, data := range *dataInline {
+		data := data
+		sources = append(sources, source{fmt.Sprintf("inline[%d]", i), func() (any, error) {
+			return jsonschema.UnmarshalJSON(strings.NewReader(data))
+		}})
+	}
+
+	// validate
+	opts := &jsonschema.ValidateOptions{FailFast: *failFast}
+	exitCode := 0
+	for _, src := range sources {
+		instance := src.name
+		if !*quiet {
+			fmt.Println()
+		}
+		inst, err := src.load()
 		if err != nil {
 			fmt.Printf("instance %s: failed\n", instance)
 			if !*quiet {
 				fmt.Println(err)
 			}
-			allValid = false
+			exitCode = exitIO
 			continue
 		}
 
-		err = sch.Validate(inst)
+		err = sch.ValidateWithOptions(inst, opts)
 		if err != nil {
 			fmt.Printf("instance %s: failed\n", instance)
 			if !*quiet {
 				if verr, ok := err.(*jsonschema.ValidationError); ok {
+					verr, omitted := limitErrors(verr, *maxErrors)
 					switch *output {
 					case "simple":
 						fmt.Printf("%v\n", verr)
@@ -203,18 +248,98 @@ func main() {
 					case "detailed":
 						printJSON(verr.DetailedOutput())
 					}
+					if omitted > 0 {
+						fmt.Printf("%d more errors omitted\n", omitted)
+					}
 				} else {
 					fmt.Println(err)
 				}
 			}
-			allValid = false
+			if exitCode == 0 {
+				exitCode = exitInvalid
+			}
+			if *failFast {
+				break
+			}
 			continue
 		}
 		fmt.Printf("instance %s: ok\n", instance)
 	}
-	if !allValid {
-		os.Exit(1)
+	os.Exit(exitCode)
+}
+
+// limitErrors returns copy of verr with at most n leaf errors,
+// along with number of leaf errors omitted. n <= 0 means no limit.
+func limitErrors(verr *jsonschema.ValidationError, n int) (*jsonschema.ValidationError, int) {
+	if n <= 0 {
+		return verr, 0
+	}
+	var omitted int
+	var limit func(e *jsonschema.ValidationError) *jsonschema.ValidationError
+	limit = func(e *jsonschema.ValidationError) *jsonschema.ValidationError {
+		if len(e.Causes) == 0 {
+			if n == 0 {
+				omitted++
+				return nil
+			}
+			n--
+			return e
+		}
+		clone := *e
+		clone.Causes = nil
+		for _, cause := range e.Causes {
+			if c := limit(cause); c != nil {
+				clone.Causes = append(clone.Causes, c)
+			}
+		}
+		if len(clone.Causes) == 0 {
+			return nil
+		}
+		return &clone
+	}
+	if limited := limit(verr); limited != nil {
+		return limited, omitted
+	}
+	return verr, 0
+}
+
+func parseMappings(maps []string) (map[string]string, error) {
+	mappings := map[string]string{}
+	for _, m := range maps {
+		equal := strings.IndexByte(m, '=')
+		if equal == -1 {
+			return nil, fmt.Errorf("invalid map: %v", m)
+		}
+		u, dir := m[:equal], m[equal+1:]
+		if dir == "" {
+			return nil, fmt.Errorf("invalid map: %v", m)
+		}
+		_, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("invalid map %v: %v", m, err)
+		}
+		if !strings.HasSuffix(u, "/") {
+			u += "/"
+		}
+		mappings[u] = dir
+	}
+	return mappings, nil
+}
+
+func draftFromVersion(version int) *jsonschema.Draft {
+	switch version {
+	case 4:
+		return jsonschema.Draft4
+	case 6:
+		return jsonschema.Draft6
+	case 7:
+		return jsonschema.Draft7
+	case 2019:
+		return jsonschema.Draft2019
+	case 2020:
+		return jsonschema.Draft2020
 	}
+	return nil
 }
 
 func eprintln(format string, args ...any) {
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/migrate"
+	flag "github.com/spf13/pflag"
+)
+
+func migrateMain(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		eprintln("Usage: jv migrate [OPTIONS] SCHEMA")
+		eprintln("")
+		eprintln("Rewrites SCHEMA to newer draft and prints it to stdout.")
+		eprintln("Changes which need review are printed to stderr.")
+		eprintln("")
+		eprintln("Options:")
+		fs.PrintDefaults()
+	}
+	help := fs.BoolP("help", "h", false, "Print help information")
+	draftVersion := fs.IntP("draft", "d", 4, "Draft `version` used when '$schema' is missing. Valid values 4, 6, 7, 2019, 2020")
+	to := fs.String("to", "2020-12", "Draft to migrate to. Valid values 2020-12")
+	fs.SortFlags = false
+	_ = fs.Parse(args)
+
+	if *help {
+		fs.Usage()
+		os.Exit(0)
+	}
+
+	from := draftFromVersion(*draftVersion)
+	if from == nil {
+		eprintln("invalid draft: %v", *draftVersion)
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	if *to != "2020-12" && *to != "2020" {
+		eprintln("invalid to: %v", *to)
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	if fs.NArg() != 1 {
+		eprintln("missing SCHEMA")
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	doc, err := loadFile(fs.Arg(0))
+	if err != nil {
+		eprintln("%v", err)
+		os.Exit(exitSchema)
+	}
+	v, issues, err := migrate.Migrate(doc, from, jsonschema.Draft2020)
+	if err != nil {
+		eprintln("%v", err)
+		os.Exit(exitSchema)
+	}
+	printJSON(v)
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "warning %v\n", issue)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+	flag "github.com/spf13/pflag"
+)
+
+// oasDialect is the default dialect of schemas in OpenAPI 3.1.
+const oasDialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+func openapiMain(args []string) {
+	fs := flag.NewFlagSet("openapi", flag.ExitOnError)
+	fs.Usage = func() {
+		eprintln("Usage: jv openapi [OPTIONS] SPEC")
+		eprintln("")
+		eprintln("Compiles schemas in OpenAPI document SPEC, and prints result")
+		eprintln("of each. It compiles schemas in components, and schemas of")
+		eprintln("parameters, request bodies and responses in paths.")
+		eprintln("")
+		eprintln("Schemas are compiled with draft-04 for OpenAPI 3.0, and with")
+		eprintln("draft 2020-12 for OpenAPI 3.1. The --draft option is ignored.")
+		eprintln("")
+		eprintln("Options:")
+		fs.PrintDefaults()
+		eprintln("")
+		eprintln("Exit codes:")
+		eprintln("  0  all schemas are valid")
+		eprintln("  2  invalid command line")
+		eprintln("  3  some schema failed to compile")
+		eprintln("  4  SPEC failed to load")
+	}
+	help := fs.BoolP("help", "h", false, "Print help information")
+	quiet := fs.BoolP("quiet", "q", false, "Do not print errors")
+	lf := addLoaderFlags(fs)
+	fs.SortFlags = false
+	_ = fs.Parse(args)
+
+	if *help {
+		fs.Usage()
+		os.Exit(0)
+	}
+	c := lf.newCompiler(fs)
+	if fs.NArg() != 1 {
+		eprintln("missing SPEC")
+		eprintln("")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	spec := fs.Arg(0)
+
+	doc, err := func() (any, error) {
+		if strings.Contains(spec, "://") {
+			return lf.loader.Load(spec)
+		}
+		return loadFile(spec)
+	}()
+	if err != nil {
+		fmt.Printf("spec %s: failed\n", spec)
+		if !*quiet {
+			fmt.Println(err)
+		}
+		os.Exit(exitIO)
+	}
+	doc = stringKeys(doc)
+	obj, _ := doc.(map[string]any)
+	version, _ := obj["openapi"].(string)
+	switch {
+	case strings.HasPrefix(version, "3.0."):
+		c.DefaultDraft(jsonschema.Draft4)
+		c.InterpretNullable()
+	case strings.HasPrefix(version, "3.1."):
+		c.DefaultDraft(jsonschema.Draft2020)
+		c.RegisterDialect(&jsonschema.Dialect{URL: oasDialect, Draft: jsonschema.Draft2020})
+	default:
+		fmt.Printf("spec %s: failed\n", spec)
+		if !*quiet {
+			fmt.Printf("unsupported openapi version %q\n", version)
+		}
+		os.Exit(exitIO)
+	}
+	if err := c.AddResource(spec, doc); err != nil {
+		fmt.Printf("spec %s: failed\n", spec)
+		if !*quiet {
+			fmt.Println(err)
+		}
+		os.Exit(exitIO)
+	}
+
+	exitCode := 0
+	for _, ptr := range openapiSchemas(obj) {
+		if _, err := c.Compile(spec + "#" + ptr); err != nil {
+			fmt.Printf("schema %s: failed\n", ptr)
+			if !*quiet {
+				fmt.Println(err)
+			}
+			exitCode = exitSchema
+			continue
+		}
+		fmt.Printf("schema %s: ok\n", ptr)
+	}
+	os.Exit(exitCode)
+}
+
+// openapiSchemas returns json-pointers of schemas in
+// openapi document.
+func openapiSchemas(spec map[string]any) []string {
+	var ptrs []string
+	addSchema := func(ptr string, v any) {
+		if _, ok := v.(map[string]any); ok {
+			ptrs = append(ptrs, ptr)
+		}
+	}
+	// parameter, header or media type objects
+	var addSchemaOf func(ptr string, v any)
+	addSchemaOf = func(ptr string, v any) {
+		obj, ok := v.(map[string]any)
+		if !ok || obj["$ref"] != nil {
+			return
+		}
+		addSchema(jsonpointer.Append(ptr, "schema"), obj["schema"])
+		for _, name := range sortedKeys(obj["content"]) {
+			content := obj["content"].(map[string]any)
+			addSchemaOf(jsonpointer.Append(ptr, "content", name), content[name])
+		}
+	}
+	addEach := func(ptr string, v any, fn func(ptr string, v any)) {
+		if arr, ok := v.([]any); ok {
+			for i, item := range arr {
+				fn(jsonpointer.Append(ptr, fmt.Sprint(i)), item)
+			}
+			return
+		}
+		for _, name := range sortedKeys(v) {
+			fn(jsonpointer.Append(ptr, name), v.(map[string]any)[name])
+		}
+	}
+	// request body or response objects
+	addBody := func(ptr string, v any) {
+		addSchemaOf(ptr, v)
+		if obj, ok := v.(map[string]any); ok && obj["$ref"] == nil {
+			addEach(jsonpointer.Append(ptr, "headers"), obj["headers"], addSchemaOf)
+		}
+	}
+
+	if components, ok := spec["components"].(map[string]any); ok {
+		addEach("/components/schemas", components["schemas"], addSchema)
+		addEach("/components/parameters", components["parameters"], addSchemaOf)
+		addEach("/components/headers", components["headers"], addSchemaOf)
+		addEach("/components/requestBodies", components["requestBodies"], addBody)
+		addEach("/components/responses", components["responses"], addBody)
+	}
+	methods := []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	paths, _ := spec["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]any)
+		if !ok || item["$ref"] != nil {
+			continue
+		}
+		ptr := jsonpointer.Append("/paths", path)
+		addEach(jsonpointer.Append(ptr, "parameters"), item["parameters"], addSchemaOf)
+		for _, method := range methods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			ptr := jsonpointer.Append(ptr, method)
+			addEach(jsonpointer.Append(ptr, "parameters"), op["parameters"], addSchemaOf)
+			addBody(jsonpointer.Append(ptr, "requestBody"), op["requestBody"])
+			addEach(jsonpointer.Append(ptr, "responses"), op["responses"], addBody)
+		}
+	}
+	return ptrs
+}
+
+func sortedKeys(v any) []string {
+	obj, _ := v.(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// stringKeys converts maps with non-string keys, as decoded from
+// yaml, for example with unquoted response codes, to json objects.
+func stringKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = stringKeys(item)
+		}
+	case map[any]any:
+		obj := make(map[string]any, len(v))
+		for k, item := range v {
+			obj[fmt.Sprint(k)] = stringKeys(item)
+		}
+		return obj
+	case []any:
+		for i, item := range v {
+			v[i] = stringKeys(item)
+		}
+	}
+	return v
+}
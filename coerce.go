@@ -0,0 +1,248 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// CoerceOptions tells which type conversions are
+// attempted by [Schema.Coerce].
+type CoerceOptions struct {
+	// Numbers converts string, which is a valid json number,
+	// to number or integer.
+	Numbers bool
+
+	// Booleans converts strings "true" and "false" to boolean.
+	Booleans bool
+
+	// Null converts empty string to null.
+	Null bool
+
+	// SingleItemArrays wraps value into array, if array is expected,
+	// and unwraps array with single item, if array is not expected.
+	SingleItemArrays bool
+
+	// EnumNumbers converts integer n to n-th value of `enum`, if
+	// `enum` has only strings, and does not contain n. This matches
+	// enums generated from protobuf, which list names in the order
+	// of their numbers, starting from zero.
+	EnumNumbers bool
+
+	// NullAsAbsent removes object properties whose value is null,
+	// if `type` of property schema does not allow null.
+	NullAsAbsent bool
+}
+
+// ProtoJSONCoerceOptions returns options, which make canonical
+// proto3 JSON mapping valid against schemas generated from protobuf
+// messages:
+//   - int64, uint64 and fixed64 values encoded as strings are
+//     converted to numbers
+//   - enum values given as numbers are converted to names
+//   - null fields are treated as absent, which means the default
+//     value in proto3
+//
+// Timestamps are encoded as RFC 3339 strings with uppercase "Z",
+// which are valid `date-time`, and need no conversion.
+func ProtoJSONCoerceOptions() *CoerceOptions {
+	return &CoerceOptions{
+		Numbers:      true,
+		EnumNumbers:  true,
+		NullAsAbsent: true,
+	}
+}
+
+// Coerce returns copy of instance v, in which the values that do not
+// match `type` of the schema are converted to the expected type, as
+// per opts. This is useful for html form and query string data, where
+// all values are strings. Use [Schema.Validate] to validate the result.
+//
+// Values are coerced using `type` found by following `$ref`, `allOf`,
+// `properties`, `patternProperties`, `additionalProperties` and items
+// keywords. Conversion is attempted to the expected types in order
+// null, boolean, number, integer, string, array, object.
+//
+// The v is not modified.
+func (sch *Schema) Coerce(v any, opts *CoerceOptions) any {
+	return coerce(sch, v, opts, map[*Schema]bool{})
+}
+
+// coerce coerces v using sch. seen holds schemas
+// already applied to v, to avoid infinite loop.
+func coerce(sch *Schema, v any, opts *CoerceOptions, seen map[*Schema]bool) any {
+	if sch == nil || seen[sch] {
+		return v
+	}
+	seen[sch] = true
+
+	if sch.Types != nil {
+		v = coerceType(*sch.Types, v, opts)
+	}
+	if opts.EnumNumbers && sch.Enum != nil {
+		v = coerceEnum(sch.Enum, v)
+	}
+
+	// applicators on same value --
+	for _, ref := range sch.refs() {
+		v = coerce(ref, v, opts, seen)
+	}
+	for _, s := range sch.AllOf {
+		v = coerce(s, v, opts, seen)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for pname, pvalue := range v {
+			var schemas []*Schema
+			if s, ok := sch.Properties[pname]; ok {
+				schemas = append(schemas, s)
+			}
+			for re, s := range sch.PatternProperties {
+				if re.MatchString(pname) {
+					schemas = append(schemas, s)
+				}
+			}
+			if len(schemas) == 0 {
+				if s, ok := sch.AdditionalProperties.(*Schema); ok {
+					schemas = append(schemas, s)
+				}
+			}
+			if pvalue == nil && opts.NullAsAbsent && !allowsNull(schemas) {
+				continue
+			}
+			for _, s := range schemas {
+				pvalue = coerce(s, pvalue, opts, map[*Schema]bool{})
+			}
+			obj[pname] = pvalue
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			if s := itemSchema(sch, i); s != nil {
+				item = coerce(s, item, opts, map[*Schema]bool{})
+			}
+			arr[i] = item
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+// itemSchema returns schema for i-th item, nil if none.
+func itemSchema(sch *Schema, i int) *Schema {
+	switch items := sch.Items.(type) {
+	case *Schema:
+		return items
+	case []*Schema:
+		if i < len(items) {
+			return items[i]
+		}
+		s, _ := sch.AdditionalItems.(*Schema)
+		return s
+	}
+	if i < len(sch.PrefixItems) {
+		return sch.PrefixItems[i]
+	}
+	return sch.Items2020
+}
+
+func typeMatches(types Types, v any) bool {
+	t := typeOf(v)
+	return types.contains(t) || (types.contains(integerType) && t == numberType && isInteger(v))
+}
+
+func coerceType(types Types, v any, opts *CoerceOptions) any {
+	if typeMatches(types, v) {
+		return v
+	}
+	if arr, ok := v.([]any); ok && opts.SingleItemArrays && len(arr) == 1 {
+		return coerceType(types, arr[0], opts)
+	}
+	s, isStr := v.(string)
+	for _, t := range types.ToStrings() {
+		switch t {
+		case "null":
+			if opts.Null && isStr && s == "" {
+				return nil
+			}
+		case "boolean":
+			if opts.Booleans && isStr && (s == "true" || s == "false") {
+				return s == "true"
+			}
+		case "number", "integer":
+			if opts.Numbers && isStr {
+				if num, ok := parseNumber(s); ok && (t == "number" || isInteger(num)) {
+					return num
+				}
+			}
+		case "array":
+			if opts.SingleItemArrays {
+				return []any{v}
+			}
+		}
+	}
+	return v
+}
+
+func coerceEnum(enum *Enum, v any) any {
+	if enum.types != Types(stringType) || typeOf(v) != numberType {
+		return v
+	}
+	n, ok := new(big.Rat).SetString(fmt.Sprint(v))
+	if !ok || !n.IsInt() || n.Sign() < 0 || !n.Num().IsInt64() {
+		return v
+	}
+	if i := n.Num().Int64(); i < int64(len(enum.Values)) {
+		return enum.Values[i]
+	}
+	return v
+}
+
+// allowsNull tells whether null is allowed by `type` of any of
+// given schemas. `type` is searched by following `$ref` and `allOf`.
+// If none of the schemas has `type`, null is assumed to be allowed.
+func allowsNull(schemas []*Schema) bool {
+	if len(schemas) == 0 {
+		return true
+	}
+	for _, sch := range schemas {
+		if types := declaredTypes(sch, map[*Schema]bool{}); types == nil || types.contains(nullType) {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredTypes returns `type` of sch, nil if not found.
+func declaredTypes(sch *Schema, seen map[*Schema]bool) *Types {
+	if sch == nil || seen[sch] {
+		return nil
+	}
+	seen[sch] = true
+	if sch.Types != nil {
+		return sch.Types
+	}
+	for _, s := range append(sch.refs(), sch.AllOf...) {
+		if types := declaredTypes(s, seen); types != nil {
+			return types
+		}
+	}
+	return nil
+}
+
+// parseNumber parses s as json number.
+func parseNumber(s string) (json.Number, bool) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return "", false
+	}
+	num, ok := v.(json.Number)
+	return num, ok && string(num) == s
+}
@@ -0,0 +1,124 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestCoerce(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"age": { "type": "integer" },
+			"price": { "type": ["null", "number"] },
+			"active": { "type": "boolean" },
+			"tags": { "type": "array", "items": { "type": "integer" } },
+			"name": { "type": "string" },
+			"ref": { "$ref": "#/$defs/num" }
+		},
+		"patternProperties": { "^b": { "type": "boolean" } },
+		"$defs": { "num": { "type": "number" } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := &jsonschema.CoerceOptions{Numbers: true, Booleans: true, Null: true, SingleItemArrays: true}
+	tests := []struct {
+		name string
+		opts *jsonschema.CoerceOptions
+		inst map[string]any
+		want map[string]any
+	}{
+		{"numbers", all, map[string]any{"age": "12", "price": "1.5", "ref": "2"}, map[string]any{"age": json.Number("12"), "price": json.Number("1.5"), "ref": json.Number("2")}},
+		{"notInteger", all, map[string]any{"age": "1.5"}, map[string]any{"age": "1.5"}},
+		{"notNumber", all, map[string]any{"age": " 1", "price": "abc"}, map[string]any{"age": " 1", "price": "abc"}},
+		{"booleans", all, map[string]any{"active": "true", "bx": "false", "name": "true"}, map[string]any{"active": true, "bx": false, "name": "true"}},
+		{"null", all, map[string]any{"price": ""}, map[string]any{"price": nil}},
+		{"wrap", all, map[string]any{"tags": "1"}, map[string]any{"tags": []any{json.Number("1")}}},
+		{"unwrap", all, map[string]any{"age": []any{"3"}}, map[string]any{"age": json.Number("3")}},
+		{"disabled", &jsonschema.CoerceOptions{}, map[string]any{"age": "12", "active": "true", "price": ""}, map[string]any{"age": "12", "active": "true", "price": ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := sch.Coerce(test.inst, test.opts)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	// instance is not modified
+	inst := map[string]any{"age": "12"}
+	if err := sch.Validate(sch.Coerce(inst, all)); err != nil {
+		t.Fatal(err)
+	}
+	if inst["age"] != "12" {
+		t.Fatal("instance is modified")
+	}
+}
+
+func TestCoerceProtoJSON(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": "object",
+		"properties": {
+			"id": { "type": "integer" },
+			"status": { "type": "string", "enum": ["UNKNOWN", "ACTIVE", "DELETED"] },
+			"createTime": { "type": "string", "format": "date-time" },
+			"parent": { "$ref": "#/$defs/msg" },
+			"note": { "type": ["null", "string"] }
+		},
+		"required": ["id", "status"],
+		"$defs": { "msg": { "type": "object" } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst := map[string]any{
+		"id":         "9007199254740993",
+		"status":     json.Number("1"),
+		"createTime": "2024-01-02T03:04:05.123Z",
+		"parent":     nil,
+		"note":       nil,
+	}
+	got := sch.Coerce(inst, jsonschema.ProtoJSONCoerceOptions())
+	want := map[string]any{
+		"id":         json.Number("9007199254740993"),
+		"status":     "ACTIVE",
+		"createTime": "2024-01-02T03:04:05.123Z",
+		"note":       nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if err := sch.Validate(got); err != nil {
+		t.Fatal(err)
+	}
+
+	// unknown enum number
+	got = sch.Coerce(map[string]any{"id": 1, "status": 5}, jsonschema.ProtoJSONCoerceOptions())
+	if err := sch.Validate(got); err == nil {
+		t.Fatal("want validation error")
+	}
+}
@@ -1,9 +1,14 @@
 package jsonschema
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"runtime/debug"
 	"slices"
+	"strings"
 )
 
 // Compiler compiles json schema into *Schema.
@@ -14,7 +19,25 @@ type Compiler struct {
 	decoders      map[string]*Decoder
 	mediaTypes    map[string]*MediaType
 	assertFormat  bool
+	assertFormats map[string]bool // nil if not restricted
 	assertContent bool
+	dataRef       bool
+	limits        *compileLimits
+	recoverPanics bool
+
+	strictIntegers  bool
+	nullable        bool
+	lengthUnit      LengthUnit
+	refInterceptor  RefInterceptor
+	warnFloat       func(string, any)
+	noOneOfDispatch bool
+	formatter       Formatter
+	instrumentation Instrumentation
+
+	warnUnknownKeywords bool
+	warnDraftKeywords   bool
+	strict              bool
+	warnings            []*Warning
 }
 
 // NewCompiler create Compiler Object.
@@ -49,9 +72,24 @@ func (c *Compiler) DefaultDraft(d *Draft) {
 // Default Behavior:
 // for draft-07: enabled.
 // for draft/2019-09: disabled unless metaschema says `format` vocabulary is required.
-// for draft/2020-12: disabled unless metaschema says `format-assertion` vocabulary is required.
+// for draft/2020-12: disabled unless metaschema declares `format-assertion` vocabulary,
+// either as required or optional.
 func (c *Compiler) AssertFormat() {
 	c.assertFormat = true
+	c.assertFormats = nil
+}
+
+// AssertFormats enables assertions only for formats with given
+// names, irrespective of draft. Other formats are annotations only.
+// This is useful when only some format validators are trusted.
+//
+// It overrides earlier call to [Compiler.AssertFormat], and vice versa.
+func (c *Compiler) AssertFormats(names ...string) {
+	c.assertFormat = false
+	c.assertFormats = make(map[string]bool, len(names))
+	for _, name := range names {
+		c.assertFormats[name] = true
+	}
 }
 
 // AssertContent enables content assertions.
@@ -66,6 +104,119 @@ func (c *Compiler) AssertContent() {
 	c.assertContent = true
 }
 
+// StrictIntegers enables strict integer semantics of draft-04,
+// where a number with fraction or exponent, such as 1.0, is not
+// an integer, though it has no fractional part. This affects
+// only the `type` keyword.
+//
+// In this mode, go float32 and float64 values are never integers.
+// So the instance must be decoded using [UnmarshalJSON] or with
+// [json.Decoder.UseNumber].
+//
+// Default behavior is to treat numbers with zero fractional part
+// as integers, as per modern drafts.
+func (c *Compiler) StrictIntegers() {
+	c.strictIntegers = true
+}
+
+// InterpretNullable enables compatibility with OpenAPI 3.0 schemas,
+// where `nullable: true` allows null, in addition to the types in
+// `type` keyword. Such schemas can be validated as is, without
+// rewriting them to use `type: ["string", "null"]`.
+//
+// As `nullable` is not a json-schema keyword, each use is reported
+// as [*Nullable] warning, available via [Compiler.Warnings].
+// It has no effect if `type` is not specified.
+func (c *Compiler) InterpretNullable() {
+	c.nullable = true
+}
+
+// DisableOneOfDispatch disables validating only one subschema
+// of `oneOf`, when the subschemas discriminate by value of a
+// property.
+//
+// By default, if every subschema of `oneOf` requires same
+// property and restricts it using `const` or `enum` with
+// distinct values, then only the subschema matching value
+// of that property is tried. This is faster, and the errors
+// are reported only for that subschema. Use this, if errors
+// of all subschemas are needed.
+func (c *Compiler) DisableOneOfDispatch() {
+	c.noOneOfDispatch = true
+}
+
+// EnableDataRef enables the `$data` extension, which allows
+// value of following keywords to be specified as
+// `{"$data": "relative-json-pointer"}`:
+//   - const, enum, format
+//   - minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//   - minLength, maxLength, pattern
+//   - minItems, maxItems, uniqueItems
+//   - minProperties, maxProperties, required
+//
+// The relative-json-pointer is evaluated against the instance
+// being validated, starting from the current location. If it
+// does not resolve to any value, the keyword is ignored.
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) EnableDataRef() {
+	c.dataRef = true
+	c.roots.dataRef = true
+}
+
+// WarnUnknownKeywords enables reporting of keywords which are
+// not recognized by the dialect of schema, such as typos like
+// "requird" or keywords from other drafts. Such keywords are
+// otherwise silently ignored.
+//
+// Keywords are recognized from the properties of metaschemas
+// of the vocabularies enabled in the dialect.
+//
+// The warnings are available via [Compiler.Warnings].
+func (c *Compiler) WarnUnknownKeywords() {
+	c.warnUnknownKeywords = true
+}
+
+// WarnDraftKeywords enables reporting of keywords which have
+// no effect in the draft of schema, but have effect in other drafts.
+// For example `prefixItems` in draft-07 or `dependencies` in
+// draft 2020-12. This helps in migrating schemas between drafts.
+//
+// The warnings are available via [Compiler.Warnings].
+func (c *Compiler) WarnDraftKeywords() {
+	c.warnDraftKeywords = true
+}
+
+// Strict enables strict mode, similar to that of ajv. In strict mode,
+// compilation fails with [*StrictModeError], if any of following is found:
+//   - unknown keywords and keywords from other drafts, see [Compiler.WarnUnknownKeywords]
+//     and [Compiler.WarnDraftKeywords]
+//   - unknown formats
+//   - `properties` without `additionalProperties` or `unevaluatedProperties`,
+//     which allows any additional property
+//   - type unions other than with null, like `["string", "number"]`
+//
+// Note that number never equals to numeric string in `const`, `enum`
+// and `uniqueItems`, irrespective of strict mode.
+func (c *Compiler) Strict() {
+	c.strict = true
+	c.warnUnknownKeywords = true
+	c.warnDraftKeywords = true
+}
+
+// Warnings returns warnings found so far, while compiling schemas.
+func (c *Compiler) Warnings() []*Warning {
+	return slices.Clone(c.warnings)
+}
+
+func (c *Compiler) warn(schemaURL, keyword string, kind WarningKind) {
+	c.warnings = append(c.warnings, &Warning{
+		SchemaURL: schemaURL,
+		Keyword:   keyword,
+		Kind:      kind,
+	})
+}
+
 // RegisterFormat registers custom format.
 //
 // NOTE:
@@ -103,6 +254,24 @@ func (c *Compiler) RegisterVocabulary(vocab *Vocabulary) {
 	c.roots.vocabularies[vocab.URL] = vocab
 }
 
+// RegisterDialect registers custom dialect. Schemas whose
+// `$schema` is d.URL are compiled using d.Draft with vocabularies
+// d.Vocabularies, without loading metaschema from d.URL.
+// If d.Draft is nil, latest draft is used.
+//
+// Such schemas are validated against metaschemas of the
+// enabled vocabularies.
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) RegisterDialect(d *Dialect) {
+	dialect := *d
+	if dialect.Draft == nil {
+		dialect.Draft = draftLatest
+	}
+	u, _ := split(d.URL)
+	c.roots.loader.dialects[url(u)] = &dialect
+}
+
 // AssertVocabs always enables user-defined vocabularies assertions.
 //
 // Default Behavior:
@@ -132,12 +301,110 @@ func (c *Compiler) AddResource(url string, doc any) error {
 	return nil
 }
 
+// CompileValue compiles schema document doc, which is already
+// unmarshalled, without the need to invent url for it. The doc
+// is added as resource with url "urn:jsonschema:sha256:<hash>",
+// where hash is [Hash] of doc. So compiling same doc again
+// returns the schema compiled earlier.
+//
+// References in doc are resolved against its `$id`, if present.
+// Without `$id`, only references to fragments and absolute urls
+// can be resolved.
+func (c *Compiler) CompileValue(doc any) (*Schema, error) {
+	hash, err := Hash(doc)
+	if err != nil {
+		return nil, err
+	}
+	url := "urn:jsonschema:sha256:" + hash
+	if err := c.AddResource(url, doc); err != nil {
+		if _, ok := err.(*ResourceExistsError); !ok {
+			return nil, err
+		}
+	}
+	return c.Compile(url)
+}
+
+// AddResourceJSON is like [Compiler.AddResource], but takes json
+// encoded doc. Numbers are decoded using [UnmarshalJSON] without
+// losing precision, exactly as documents loaded by [URLLoader].
+//
+// If data is not valid json, it returns [*LoadURLError].
+func (c *Compiler) AddResourceJSON(url string, data []byte) error {
+	return c.AddResourceReader(url, bytes.NewReader(data))
+}
+
+// AddResourceReader is like [Compiler.AddResourceJSON], but
+// reads json encoded doc from r.
+func (c *Compiler) AddResourceReader(url string, r io.Reader) error {
+	doc, err := UnmarshalJSON(r)
+	if err != nil {
+		return &LoadURLError{URL: url, Err: err}
+	}
+	return c.AddResource(url, doc)
+}
+
 // UseLoader overrides the default [URLLoader] used
-// to load schema resources.
+// to load schema resources. The default loader delegates
+// to loaders registered using [RegisterDefaultLoader].
 func (c *Compiler) UseLoader(loader URLLoader) {
 	c.roots.loader.loader = loader
 }
 
+// UseLoaderMiddleware wraps the [URLLoader] with given middlewares.
+// The first middleware is the outermost, i.e. it sees each url first.
+// Middlewares apply to the loader set by [Compiler.UseLoader]
+// irrespective of the order of calls, and multiple calls append
+// to the chain.
+//
+// Middlewares are not consulted for urls already loaded, and for
+// metaschemas which are embedded in this library.
+func (c *Compiler) UseLoaderMiddleware(mws ...LoaderMiddleware) {
+	c.roots.loader.mws = append(c.roots.loader.mws, mws...)
+}
+
+// UseLoadPolicy sets policy which is consulted before loading
+// any url using [URLLoader]. If policy returns error, compilation
+// fails with [*PolicyError]. This can be used to restrict which
+// hosts, schemes or paths schemas may reference.
+//
+// Resources added using [Compiler.AddResource] and the standard
+// metaschemas are not subject to policy.
+func (c *Compiler) UseLoadPolicy(policy func(url string) error) {
+	c.roots.loader.policy = policy
+}
+
+// RecoverPanics makes [Compiler.Compile] recover from any panic
+// during compilation, for example in [Vocabulary] Compile function,
+// and return it as [*CompilePanicError].
+//
+// The Compiler should not be used after such error.
+func (c *Compiler) RecoverPanics() {
+	c.recoverPanics = true
+}
+
+// LoadedResources returns resources loaded so far using
+// [URLLoader], sorted by url. This can be used to record
+// exactly which external documents contributed to compiled
+// schemas.
+//
+// Resources added using [Compiler.AddResource] and the standard
+// metaschemas are not included.
+func (c *Compiler) LoadedResources() ([]LoadedResource, error) {
+	l := &c.roots.loader
+	list := make([]LoadedResource, 0, len(l.loaded))
+	for _, u := range l.loaded {
+		res, err := newLoadedResource(u, l.docs[u])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, res)
+	}
+	slices.SortFunc(list, func(a, b LoadedResource) int {
+		return strings.Compare(a.URL, b.URL)
+	})
+	return list, nil
+}
+
 // UseRegexpEngine changes the regexp-engine used.
 // By default it uses regexp package from go standard
 // library.
@@ -148,6 +415,46 @@ func (c *Compiler) UseRegexpEngine(engine RegexpEngine) {
 		engine = goRegexpCompile
 	}
 	c.roots.regexpEngine = engine
+	c.roots.regexpChain = false
+}
+
+// UseRegexpEngines sets chain of regexp-engines to be used.
+// Each pattern is compiled using first engine in the chain
+// which accepts it. If no engine accepts the pattern, compilation
+// fails with [*UnsupportedPatternError].
+//
+// For example, to try go regexp first and then an ECMA
+// compatible engine:
+//
+//	c.UseRegexpEngines(jsonschema.GoRegexpEngine, ecmaCompile)
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) UseRegexpEngines(engines ...RegexpEngine) {
+	if len(engines) == 0 {
+		c.UseRegexpEngine(nil)
+		return
+	}
+	c.roots.regexpEngine = regexpChain(engines)
+	c.roots.regexpChain = true
+}
+
+// IgnoreUnsupportedPatterns treats `pattern` and `patternProperties`
+// whose regex is not accepted by regexp-engine as annotations,
+// instead of failing the compilation. i.e, such `pattern` is
+// not asserted and such `patternProperties` entry is ignored.
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) IgnoreUnsupportedPatterns() {
+	c.roots.ignoreUnsupportedPatterns = true
+}
+
+// UseLimits sets limits used during compilation. It is
+// recommended when compiling untrusted schemas.
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) UseLimits(limits CompileLimits) {
+	c.limits = &compileLimits{opts: limits}
+	c.roots.loader.limits = c.limits
 }
 
 func (c *Compiler) enqueue(q *queue, up urlPtr) *Schema {
@@ -175,7 +482,10 @@ func (c *Compiler) MustCompile(loc string) *Schema {
 }
 
 // Compile compiles json-schema at given loc.
-func (c *Compiler) Compile(loc string) (*Schema, error) {
+func (c *Compiler) Compile(loc string) (sch *Schema, err error) {
+	if c.recoverPanics {
+		defer c.recoverPanic(loc, &err)
+	}
 	uf, err := absolute(loc)
 	if err != nil {
 		return nil, err
@@ -184,16 +494,85 @@ func (c *Compiler) Compile(loc string) (*Schema, error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.doCompile(up)
+	schemas, err := c.doCompile(up)
+	if err != nil {
+		return nil, err
+	}
+	return schemas[0], nil
 }
 
-func (c *Compiler) doCompile(up urlPtr) (*Schema, error) {
+// CompileAllUnder compiles all schemas in the json object at given
+// loc, and returns them keyed by property name. For example, to
+// compile all schemas of an openapi document:
+//
+//	schemas, err := c.CompileAllUnder("openapi.json#/components/schemas")
+//
+// This is same as calling [Compiler.Compile] for each property, but
+// the schemas are compiled in single pass.
+func (c *Compiler) CompileAllUnder(loc string) (schemas map[string]*Schema, err error) {
+	if c.recoverPanics {
+		defer c.recoverPanic(loc, &err)
+	}
+	uf, err := absolute(loc)
+	if err != nil {
+		return nil, err
+	}
+	up, err := c.roots.resolveFragment(*uf)
+	if err != nil {
+		return nil, err
+	}
+	v, err := up.lookup(c.roots.roots[up.url].doc)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, &NotAnObjectError{URL: up.String()}
+	}
+
+	names := make([]string, 0, len(obj))
+	for pname := range obj {
+		names = append(names, pname)
+	}
+	slices.Sort(names)
+	ups := make([]urlPtr, len(names))
+	for i, pname := range names {
+		ups[i] = urlPtr{up.url, up.ptr.append(pname)}
+	}
+	compiled, err := c.doCompile(ups...)
+	if err != nil {
+		return nil, err
+	}
+	schemas = make(map[string]*Schema, len(names))
+	for i, pname := range names {
+		schemas[pname] = compiled[i]
+	}
+	return schemas, nil
+}
+
+func (c *Compiler) recoverPanic(loc string, err *error) {
+	if r := recover(); r != nil {
+		*err = &CompilePanicError{URL: loc, Value: r, Stack: debug.Stack()}
+	}
+}
+
+// doCompile compiles schemas at ups, in single pass.
+func (c *Compiler) doCompile(ups ...urlPtr) ([]*Schema, error) {
 	q := &queue{}
 	compiled := 0
+	numWarnings := len(c.warnings)
 
-	c.enqueue(q, up)
+	for _, up := range ups {
+		c.enqueue(q, up)
+	}
 	for q.len() > compiled {
 		sch := q.at(compiled)
+		if c.limits != nil {
+			err := c.limits.check("MaxSchemas", c.limits.opts.MaxSchemas, len(c.schemas)+q.len(), sch.up.String())
+			if err != nil {
+				return nil, err
+			}
+		}
 		if err := c.roots.ensureSubschema(sch.up); err != nil {
 			return nil, err
 		}
@@ -207,15 +586,27 @@ func (c *Compiler) doCompile(up urlPtr) (*Schema, error) {
 		}
 		compiled++
 	}
+	if c.strict && len(c.warnings) > numWarnings {
+		return nil, &StrictModeError{Warnings: slices.Clone(c.warnings[numWarnings:])}
+	}
 	for _, sch := range *q {
+		if !c.noOneOfDispatch {
+			// needs subschemas to be compiled
+			sch.oneOfIndex = newOneOfIndex(sch)
+		}
 		c.schemas[sch.up] = sch
 	}
-	return c.schemas[up], nil
+	schemas := make([]*Schema, len(ups))
+	for i, up := range ups {
+		schemas[i] = c.schemas[up]
+	}
+	return schemas, nil
 }
 
 func (c *Compiler) compileValue(v any, sch *Schema, r *root, q *queue) error {
 	res := r.resource(sch.up.ptr)
 	sch.DraftVersion = res.dialect.draft.version
+	sch.dialect = res.dialect
 
 	base := urlPtr{sch.up.url, res.ptr}
 	sch.resource = c.enqueue(q, base)
@@ -235,6 +626,10 @@ func (c *Compiler) compileValue(v any, sch *Schema, r *root, q *queue) error {
 		}
 	}
 
+	sch.doc = v
+	sch.warnFloat = c.warnFloat
+	sch.formatter = c.formatter
+	sch.instrumentation = c.instrumentation
 	switch v := v.(type) {
 	case bool:
 		sch.Bool = &v
@@ -313,6 +708,21 @@ type Regexp interface {
 	MatchString(string) bool
 }
 
+// FallibleRegexp is optionally implemented by [Regexp], whose
+// matching may not complete, for example due to timeout.
+//
+// If matching fails during validation, the validation is aborted
+// with [*RegexpMatchError], so that the failure is not masked by
+// keywords like `not`. MatchString is used where the error
+// cannot be reported, like in [Schema.RemoveAdditional].
+type FallibleRegexp interface {
+	Regexp
+
+	// TryMatchString is same as MatchString, but returns
+	// error, if matching does not complete.
+	TryMatchString(string) (bool, error)
+}
+
 // RegexpEngine parses a regular expression and returns,
 // if successful, a Regexp object that can be used to
 // match against text.
@@ -330,3 +740,129 @@ func (re RegexpEngine) validate(v any) error {
 func goRegexpCompile(s string) (Regexp, error) {
 	return regexp.Compile(s)
 }
+
+// GoRegexpEngine is the RegexpEngine which uses regexp
+// package from go standard library. This is the default.
+func GoRegexpEngine(s string) (Regexp, error) {
+	return goRegexpCompile(s)
+}
+
+// regexpChain returns RegexpEngine which uses first
+// engine that accepts the pattern.
+func regexpChain(engines []RegexpEngine) RegexpEngine {
+	return func(s string) (Regexp, error) {
+		var errs []error
+		for _, engine := range engines {
+			re, err := engine(s)
+			if err == nil {
+				return re, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// acceptAllRegexps is used in metaschema validation, so that
+// unsupported patterns are reported during compilation.
+func acceptAllRegexps(string) (Regexp, error) {
+	return nil, nil
+}
+
+// --
+
+// UnsupportedPatternError is returned when none of the
+// regexp-engines set using [Compiler.UseRegexpEngines]
+// accept the pattern.
+type UnsupportedPatternError struct {
+	// location of the keyword in schema.
+	URL string
+
+	// the regex pattern.
+	Pattern string
+
+	// joined errors from all regexp-engines.
+	Err error
+}
+
+func (e *UnsupportedPatternError) Error() string {
+	return fmt.Sprintf("unsupported regex %q at %q: %v", e.Pattern, e.URL, e.Err)
+}
+
+// --
+
+// RegexpMatchError is returned by [Schema.Validate], if matching
+// of [FallibleRegexp] fails. The validation is aborted, as neither
+// matched nor not matched is safe to assume.
+type RegexpMatchError struct {
+	// the regex pattern.
+	Pattern string
+
+	// location of the value within the instance,
+	// whose string is being matched.
+	InstanceLocation []string
+
+	// error returned by TryMatchString.
+	Err error
+}
+
+func (e *RegexpMatchError) Error() string {
+	return fmt.Sprintf("matching regex %q at %q failed: %v", e.Pattern, jsonPtr(e.InstanceLocation), e.Err)
+}
+
+func (e *RegexpMatchError) Unwrap() error {
+	return e.Err
+}
+
+// --
+
+// CompilePanicError is returned by [Compiler.Compile] and
+// [Compiler.CompileAllUnder], if compilation panics and
+// [Compiler.RecoverPanics] is used.
+type CompilePanicError struct {
+	URL   string
+	Value any    // value passed to panic
+	Stack []byte // stack trace of panic
+}
+
+func (e *CompilePanicError) Error() string {
+	return fmt.Sprintf("panic while compiling %q: %v", e.URL, e.Value)
+}
+
+// Unwrap returns the value passed to panic, if it is an error.
+func (e *CompilePanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// --
+
+// NotAnObjectError is returned by [Compiler.CompileAllUnder],
+// if json value at given location is not an object.
+type NotAnObjectError struct {
+	URL string
+}
+
+func (e *NotAnObjectError) Error() string {
+	return fmt.Sprintf("json value at %q is not an object", e.URL)
+}
+
+// --
+
+// StrictModeError is returned by [Compiler.Compile],
+// if strict mode violations are found. see [Compiler.Strict].
+type StrictModeError struct {
+	Warnings []*Warning
+}
+
+func (e *StrictModeError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("strict mode violations:")
+	for _, w := range e.Warnings {
+		sb.WriteString("\n  ")
+		sb.WriteString(w.String())
+	}
+	return sb.String()
+}
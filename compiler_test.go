@@ -1,10 +1,21 @@
 package jsonschema_test
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
 )
 
 func TestMetaschemaResource(t *testing.T) {
@@ -229,3 +240,1615 @@ func TestCustomVocabSubschemaResource(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSchemaDialect(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"type": "object"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sch.Draft(); got != jsonschema.Draft2019 {
+		t.Fatalf("draft: got %v, want %v", got, jsonschema.Draft2019)
+	}
+	if got, want := sch.DialectURL(), "https://json-schema.org/draft/2019-09/schema"; got != want {
+		t.Fatalf("dialectURL: got %q, want %q", got, want)
+	}
+	vocabs := sch.Vocabularies()
+	if !slices.Contains(vocabs, "https://json-schema.org/draft/2019-09/vocab/validation") {
+		t.Fatalf("vocabularies %v must contain validation", vocabs)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"allOf": [
+			{ "$ref": "http://trusted.com/a.json" },
+			{ "$ref": "http://untrusted.com/b.json" }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errDenied := errors.New("host not allowed")
+	var loaded []string
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{
+		"http": invalidRemotes{
+			"http://trusted.com/a.json":   true,
+			"http://untrusted.com/b.json": true,
+		},
+	})
+	c.UseLoadPolicy(func(url string) error {
+		loaded = append(loaded, url)
+		if !strings.HasPrefix(url, "http://trusted.com/") {
+			return errDenied
+		}
+		return nil
+	})
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Compile("schema.json")
+	var perr *jsonschema.PolicyError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want PolicyError, got %v", err)
+	}
+	if perr.URL != "http://untrusted.com/b.json" || perr.Err != errDenied {
+		t.Fatalf("got %#v", perr)
+	}
+	if !errors.Is(err, errDenied) {
+		t.Fatalf("errors.Is(%v, errDenied) = false", err)
+	}
+	if !slices.Contains(loaded, "http://trusted.com/a.json") {
+		t.Fatalf("policy not consulted for trusted url: %v", loaded)
+	}
+}
+
+func TestCompileValue(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("http://example.com/name.json", []byte(`{"type": "string"}`)); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"name": {"$ref": "http://example.com/name.json"},
+			"age": {"$ref": "#/$defs/age"},
+			"child": {"$ref": "http://example.com/child.json"}
+		},
+		"$defs": {
+			"age": {"type": "integer"},
+			"child": {"$id": "http://example.com/child.json", "$ref": "name.json"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.CompileValue(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sch.Location, "urn:jsonschema:sha256:") {
+		t.Fatalf("got location %q", sch.Location)
+	}
+	tests := []struct {
+		inst  map[string]any
+		valid bool
+	}{
+		{map[string]any{"name": "x", "age": 1, "child": "y"}, true},
+		{map[string]any{"name": 1}, false},
+		{map[string]any{"age": "x"}, false},
+		{map[string]any{"child": 1}, false},
+	}
+	for _, test := range tests {
+		if got := sch.Validate(test.inst) == nil; got != test.valid {
+			t.Errorf("%v: valid got %v, want %v", test.inst, got, test.valid)
+		}
+	}
+
+	// same doc
+	again, err := c.CompileValue(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != sch {
+		t.Fatal("want same schema for same doc")
+	}
+}
+
+func TestRegisterDefaultLoader(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{"$ref": "test-registry://host/a.json"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+
+	// scheme not registered
+	_, err = c.Compile("schema.json")
+	var lerr *jsonschema.LoadURLError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("want LoadURLError, got %v", err)
+	}
+	if _, ok := lerr.Err.(*jsonschema.UnsupportedURLSchemeError); !ok {
+		t.Fatalf("want UnsupportedURLSchemeError, got %v", lerr.Err)
+	}
+
+	jsonschema.RegisterDefaultLoader("test-registry", invalidRemotes{
+		"test-registry://host/a.json": map[string]any{"type": "string"},
+	})
+	defer jsonschema.RegisterDefaultLoader("test-registry", nil)
+	if _, ok := jsonschema.DefaultLoaders()["file"]; !ok {
+		t.Fatal("file loader must be registered by default")
+	}
+
+	// registered after compiler creation
+	c = jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(1); err == nil {
+		t.Fatal("want validation error")
+	}
+}
+
+func TestUseLoaderMiddleware(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{"$ref": "http://old.com/a.json"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log []string
+	logger := func(name string) jsonschema.LoaderMiddleware {
+		return func(next jsonschema.URLLoader) jsonschema.URLLoader {
+			return jsonschema.URLLoaderFunc(func(url string) (any, error) {
+				log = append(log, name+" "+url)
+				return next.Load(url)
+			})
+		}
+	}
+	rewrite := func(next jsonschema.URLLoader) jsonschema.URLLoader {
+		return jsonschema.URLLoaderFunc(func(url string) (any, error) {
+			return next.Load(strings.Replace(url, "old.com", "new.com", 1))
+		})
+	}
+	c := jsonschema.NewCompiler()
+	c.UseLoaderMiddleware(logger("outer"), rewrite)
+	c.UseLoaderMiddleware(logger("inner"))
+	c.UseLoader(jsonschema.SchemeURLLoader{
+		"http": invalidRemotes{
+			"http://new.com/a.json": map[string]any{"type": "string"},
+		},
+	})
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(1); err == nil {
+		t.Fatal("want validation error")
+	}
+	want := []string{"outer http://old.com/a.json", "inner http://new.com/a.json"}
+	if !slices.Equal(log, want) {
+		t.Fatalf("got %q, want %q", log, want)
+	}
+}
+
+func TestResourceCache(t *testing.T) {
+	var loads atomic.Int32
+	loader := jsonschema.URLLoaderFunc(func(url string) (any, error) {
+		loads.Add(1)
+		return map[string]any{"type": "string"}, nil
+	})
+	var cache jsonschema.ResourceCache
+	newCompiler := func() *jsonschema.Compiler {
+		c := jsonschema.NewCompiler()
+		c.UseLoader(loader)
+		c.UseResourceCache(&cache)
+		return c
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := newCompiler()
+			if _, err := c.Compile("http://remote.com/a.json"); err != nil {
+				t.Error(err)
+			}
+			if rr, err := c.LoadedResources(); err != nil || len(rr) != 1 {
+				t.Errorf("LoadedResources: got %v, %v", rr, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loads: got %d, want 1", n)
+	}
+
+	// policy applies to cached documents
+	c := newCompiler()
+	c.UseLoadPolicy(func(url string) error { return errors.New("denied") })
+	_, err := c.Compile("http://remote.com/a.json")
+	var perr *jsonschema.PolicyError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want PolicyError", err)
+	}
+
+	cache.Delete("http://remote.com/a.json")
+	if _, err := newCompiler().Compile("http://remote.com/a.json"); err != nil {
+		t.Fatal(err)
+	}
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("loads after delete: got %d, want 2", n)
+	}
+}
+
+func TestLoadedResources(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"allOf": [
+			{ "$ref": "http://remote.com/b.json" },
+			{ "$ref": "http://remote.com/a.json" }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{
+		"http": invalidRemotes{
+			"http://remote.com/a.json": map[string]any{"type": "string", "$id": "http://remote.com/a.json"},
+			"http://remote.com/b.json": true,
+		},
+	})
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("schema.json"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.LoadedResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []jsonschema.LoadedResource{
+		{
+			URL:    "http://remote.com/a.json",
+			SHA256: "a4638faf7c8383ae32896ee7d407143c9b163f47f5aef2a61dd7ccb145d1ad2d",
+			Size:   len(`{"$id":"http://remote.com/a.json","type":"string"}`),
+		},
+		{
+			URL:    "http://remote.com/b.json",
+			SHA256: "b5bea41b6c623f7c09f1bf24dcae58ebab3c0cdd90ad966bc43a45b44867e12b",
+			Size:   len(`true`),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestSchemaValidationErrorLocations(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"a": { "type": 1 },
+			"b": { "minLength": -1 }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Compile("http://example.com/schema.json")
+	var serr *jsonschema.SchemaValidationError
+	if !errors.As(err, &serr) {
+		t.Fatalf("want SchemaValidationError, got %v", err)
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatal("want SchemaValidationError to unwrap to ValidationError")
+	}
+	got := map[string]bool{}
+	for _, loc := range serr.Locations() {
+		if loc.ErrorKind == nil || len(loc.Keyword) == 0 {
+			t.Fatalf("keyword missing in %#v", loc)
+		}
+		got[loc.URL] = true
+	}
+	for _, want := range []string{
+		"http://example.com/schema.json#/properties/a/type",
+		"http://example.com/schema.json#/properties/b/minLength",
+	} {
+		if !got[want] {
+			t.Errorf("location %q not found in %v", want, got)
+		}
+	}
+}
+
+func TestRegexpEngines(t *testing.T) {
+	// golang regexp does not support escape sequence: `\c`
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": "string",
+		"pattern": "^\\cc$"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compile := func(setup func(c *jsonschema.Compiler)) (*jsonschema.Schema, error) {
+		c := jsonschema.NewCompiler()
+		setup(c)
+		if err := c.AddResource("schema.json", schema); err != nil {
+			t.Fatal(err)
+		}
+		return c.Compile("schema.json")
+	}
+
+	// default
+	_, err = compile(func(c *jsonschema.Compiler) {})
+	if _, ok := err.(*jsonschema.SchemaValidationError); !ok {
+		t.Fatalf("want SchemaValidationError, got %v", err)
+	}
+
+	// chain with no engine accepting the pattern
+	_, err = compile(func(c *jsonschema.Compiler) {
+		c.UseRegexpEngines(jsonschema.GoRegexpEngine)
+	})
+	if perr, ok := err.(*jsonschema.UnsupportedPatternError); !ok {
+		t.Fatalf("want UnsupportedPatternError, got %v", err)
+	} else if perr.Pattern != `^\cc$` {
+		t.Fatalf("pattern: got %q", perr.Pattern)
+	}
+
+	// fallback engine
+	sch, err := compile(func(c *jsonschema.Compiler) {
+		c.UseRegexpEngines(jsonschema.GoRegexpEngine, dlclarkCompile)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate("\u0003"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate("c"); err == nil {
+		t.Fatal("want validation to fail")
+	}
+
+	// treat as annotation
+	sch, err = compile(func(c *jsonschema.Compiler) {
+		c.IgnoreUnsupportedPatterns()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate("c"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// failingRegexp fails matching of strings ending with "!".
+type failingRegexp struct {
+	jsonschema.Regexp
+}
+
+func (re failingRegexp) TryMatchString(s string) (bool, error) {
+	if strings.HasSuffix(s, "!") {
+		return false, errors.New("match timed out")
+	}
+	return re.Regexp.MatchString(s), nil
+}
+
+func TestFallibleRegexp(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		valid    any
+		invalid  any
+		failing  any
+		location string
+	}{
+		{"not", `{"not": {"pattern": "^a"}}`, "b", "a", "a!", ""},
+		{"patternProperties", `{"patternProperties": {"^x": {"type": "string"}}}`, map[string]any{"x": "1"}, map[string]any{"x": 1}, map[string]any{"x!": 1}, ""},
+		{"nested", `{"items": {"not": {"pattern": "^a"}}}`, []any{"b"}, []any{"a"}, []any{"b", "a!"}, "/1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := jsonschema.NewCompiler()
+			c.UseRegexpEngine(func(s string) (jsonschema.Regexp, error) {
+				re, err := jsonschema.GoRegexpEngine(s)
+				if err != nil {
+					return nil, err
+				}
+				return failingRegexp{re}, nil
+			})
+			sch, err := c.CompileValue(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sch.Validate(test.valid); err != nil {
+				t.Fatal(err)
+			}
+			if err := sch.Validate(test.invalid); err == nil {
+				t.Fatal("want validation error")
+			}
+			validators := map[string]func(v any) error{
+				"Validate":  sch.Validate,
+				"Validator": jsonschema.NewValidator(sch).Validate,
+				"FailFast": func(v any) error {
+					return sch.ValidateWithOptions(v, &jsonschema.ValidateOptions{FailFast: true})
+				},
+			}
+			for name, validate := range validators {
+				var merr *jsonschema.RegexpMatchError
+				if err := validate(test.failing); !errors.As(err, &merr) {
+					t.Fatalf("%s: want RegexpMatchError, got %v", name, err)
+				}
+				if loc := strings.Join(merr.InstanceLocation, "/"); loc != strings.TrimPrefix(test.location, "/") {
+					t.Fatalf("%s: location: got %q, want %q", name, loc, test.location)
+				}
+			}
+			if sch.IsValid(test.failing) {
+				t.Fatal("IsValid: want false")
+			}
+		})
+	}
+}
+
+func TestFormatAssertionVocab(t *testing.T) {
+	for _, reqd := range []string{"true", "false"} {
+		meta, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "http://example.com/meta",
+			"$vocabulary": {
+				"https://json-schema.org/draft/2020-12/vocab/core": true,
+				"https://json-schema.org/draft/2020-12/vocab/format-assertion": ` + reqd + `,
+				"http://example.com/vocab/unknown": false
+			},
+			"$dynamicAnchor": "meta",
+			"allOf": [
+				{ "$ref": "https://json-schema.org/draft/2020-12/meta/core" },
+				{ "$ref": "https://json-schema.org/draft/2020-12/meta/format-assertion" }
+			]
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+			"$schema": "http://example.com/meta",
+			"format": "ipv4"
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource("http://example.com/meta", meta); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.AddResource("schema.json", schema); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Validate("127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Validate("not-ipv4"); err == nil {
+			t.Fatalf("format-assertion %s: want validation to fail", reqd)
+		}
+	}
+}
+
+func TestOptionalFormatVocab2019(t *testing.T) {
+	meta, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "http://example.com/meta",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2019-09/vocab/core": true,
+			"https://json-schema.org/draft/2019-09/vocab/format": false
+		},
+		"$recursiveAnchor": true,
+		"allOf": [
+			{ "$ref": "https://json-schema.org/draft/2019-09/meta/core" },
+			{ "$ref": "https://json-schema.org/draft/2019-09/meta/format" }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/meta", meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("schema.json", map[string]any{
+		"$schema": "http://example.com/meta",
+		"format":  "email",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// optional format vocabulary is annotation only
+	if err := sch.Validate("x"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "http://example.com/dialect",
+		"type": "array",
+		"uniqueKeys": "id"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(uniqueKeysVocab())
+	c.RegisterDialect(&jsonschema.Dialect{
+		URL:   "http://example.com/dialect",
+		Draft: jsonschema.Draft2020,
+		Vocabularies: []string{
+			"https://json-schema.org/draft/2020-12/vocab/core",
+			"https://json-schema.org/draft/2020-12/vocab/validation",
+			"http://example.com/meta/unique-keys",
+		},
+	})
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sch.DialectURL(); got != "http://example.com/dialect" {
+		t.Fatalf("DialectURL: got %q", got)
+	}
+	if err := sch.Validate([]any{map[string]any{"id": 1}, map[string]any{"id": 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate([]any{map[string]any{"id": 1}, map[string]any{"id": 1}}); err == nil {
+		t.Fatal("want validation to fail")
+	}
+	if err := sch.Validate("not-array"); err == nil {
+		t.Fatal("want validation to fail")
+	}
+
+	// metaschema of vocabulary is used
+	invalid, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "http://example.com/dialect",
+		"uniqueKeys": 1
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("invalid.json", invalid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("invalid.json"); err == nil {
+		t.Fatal("want compilation to fail")
+	}
+}
+
+func TestWarnUnknownKeywords(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   []string
+	}{
+		{
+			`{"type": "object", "requird": ["a"], "title": "x", "x-custom": 1}`,
+			[]string{`at "http://example.com/schema.json#/requird": unknown keyword 'requird', did you mean 'required'`, `at "http://example.com/schema.json#/x-custom": unknown keyword 'x-custom'`},
+		},
+		{
+			`{"$schema": "http://json-schema.org/draft-07/schema#", "prefixItems": [true], "items": {"$anchor": "a"}}`,
+			[]string{`at "http://example.com/schema.json#/items/$anchor": unknown keyword '$anchor'`, `at "http://example.com/schema.json#/prefixItems": unknown keyword 'prefixItems'`},
+		},
+		{
+			`{"$defs": {"a": {"minimum": 1}}, "$ref": "#/$defs/a", "unevaluatedProperties": false}`,
+			nil,
+		},
+	}
+	for i, test := range tests {
+		schema, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := jsonschema.NewCompiler()
+		c.WarnUnknownKeywords()
+		if err := c.AddResource("http://example.com/schema.json", schema); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Compile("http://example.com/schema.json"); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, w := range c.Warnings() {
+			got = append(got, w.String())
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("test %d:\n got %q\nwant %q", i, got, test.want)
+		}
+	}
+}
+
+func TestWarnDraftKeywords(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"prefixItems": [true],
+		"properties": {
+			"a": { "$defs": {}, "dependentRequired": {} }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.WarnUnknownKeywords()
+	c.WarnDraftKeywords()
+	if err := c.AddResource("http://example.com/schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("http://example.com/schema.json"); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]int{}
+	for _, w := range c.Warnings() {
+		k, ok := w.Kind.(*jsonschema.DraftKeyword)
+		if !ok {
+			t.Fatalf("unexpected warning %s", w)
+		}
+		if k.Draft != jsonschema.Draft7 {
+			t.Fatalf("draft: got %v", k.Draft)
+		}
+		for _, d := range k.Drafts {
+			got[w.SchemaURL] = append(got[w.SchemaURL], d.Version())
+		}
+	}
+	want := map[string][]int{
+		"http://example.com/schema.json#/prefixItems":                    {2020},
+		"http://example.com/schema.json#/properties/a/$defs":             {2019, 2020},
+		"http://example.com/schema.json#/properties/a/dependentRequired": {2019, 2020},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/vocab/panic",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			if _, ok := obj["x-panic"]; ok {
+				panic("boom")
+			}
+			return nil, nil
+		},
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	c.RecoverPanics()
+	if err := c.AddResource("schema.json", map[string]any{"x-panic": true}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.Compile("schema.json")
+	var perr *jsonschema.CompilePanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want CompilePanicError, got %v", err)
+	}
+	if perr.Value != "boom" || len(perr.Stack) == 0 {
+		t.Fatalf("got %v", perr)
+	}
+}
+
+func TestMalformedSchemaNoPanic(t *testing.T) {
+	// metaschema validation is skipped for json-schema.org urls
+	keywords := []string{
+		"$ref", "$id", "id", "$anchor", "$dynamicRef", "$dynamicAnchor", "$recursiveRef", "$recursiveAnchor",
+		"type", "enum", "const", "not", "allOf", "anyOf", "oneOf", "if", "then", "else", "format",
+		"maxProperties", "minProperties", "required", "propertyNames", "properties", "patternProperties",
+		"additionalProperties", "dependencies", "dependentRequired", "dependentSchemas", "unevaluatedProperties",
+		"minItems", "maxItems", "uniqueItems", "contains", "minContains", "maxContains", "items",
+		"additionalItems", "prefixItems", "unevaluatedItems", "minLength", "maxLength", "pattern",
+		"contentEncoding", "contentMediaType", "contentSchema", "maximum", "minimum", "exclusiveMaximum",
+		"exclusiveMinimum", "multipleOf", "default", "examples", "$defs", "definitions", "$vocabulary", "$schema",
+	}
+	values := []any{nil, true, 1.5, -1, "[", []any{1}, []any{"a", 2}, map[string]any{"a": 1}, map[string]any{"a": []any{1}}}
+	drafts := []string{"", "http://json-schema.org/draft-04/schema#", "http://json-schema.org/draft-07/schema#", "https://json-schema.org/draft/2019-09/schema"}
+	for _, draft := range drafts {
+		for _, kw := range keywords {
+			for i, v := range values {
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							t.Errorf("%s %s=%v: %v", draft, kw, v, r)
+						}
+					}()
+					doc := map[string]any{kw: v}
+					if draft != "" && kw != "$schema" {
+						doc["$schema"] = draft
+					}
+					c := jsonschema.NewCompiler()
+					url := fmt.Sprintf("http://json-schema.org/malformed/%s/%d.json", kw, i)
+					if err := c.AddResource(url, doc); err != nil {
+						return
+					}
+					if sch, err := c.Compile(url); err == nil {
+						_ = sch.Validate(map[string]any{"a": []any{1, "x"}})
+						_ = sch.Validate("x")
+					}
+				}()
+			}
+		}
+	}
+}
+
+func TestExclusiveBool(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"maximum": 10,
+		"exclusiveMaximum": true,
+		"minimum": 1,
+		"exclusiveMinimum": false
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.ExclusiveMaximumBool == nil || !*sch.ExclusiveMaximumBool {
+		t.Fatal("ExclusiveMaximumBool must be true")
+	}
+	if sch.Maximum != nil || sch.ExclusiveMaximum.RatString() != "10" {
+		t.Fatal("maximum must be exclusive")
+	}
+	if sch.ExclusiveMinimumBool == nil || *sch.ExclusiveMinimumBool {
+		t.Fatal("ExclusiveMinimumBool must be false")
+	}
+	if sch.Minimum.RatString() != "1" || sch.ExclusiveMinimum != nil {
+		t.Fatal("minimum must be inclusive")
+	}
+}
+
+func TestStrictIntegers(t *testing.T) {
+	tests := []struct {
+		instance string
+		strict   bool
+		valid    bool
+	}{
+		{`1`, false, true},
+		{`1.0`, false, true},
+		{`1e2`, false, true},
+		{`1`, true, true},
+		{`1.0`, true, false},
+		{`1e2`, true, false},
+		{`1.5`, true, false},
+	}
+	for _, test := range tests {
+		c := jsonschema.NewCompiler()
+		if test.strict {
+			c.StrictIntegers()
+		}
+		if err := c.AddResource("schema.json", map[string]any{"type": "integer"}); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.instance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sch.Validate(inst) == nil; got != test.valid {
+			t.Errorf("strict=%v %s: got valid %v, want %v", test.strict, test.instance, got, test.valid)
+		}
+	}
+}
+
+func TestInterpretNullable(t *testing.T) {
+	doc := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "nullable": true},
+			"age":  map[string]any{"type": "integer", "nullable": false},
+		},
+	}
+	inst := map[string]any{"name": nil}
+
+	c := jsonschema.NewCompiler()
+	c.WarnUnknownKeywords()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(inst); err == nil {
+		t.Fatal("null must not be allowed by default")
+	}
+
+	c = jsonschema.NewCompiler()
+	c.WarnUnknownKeywords()
+	c.InterpretNullable()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err = c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(inst); err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(map[string]any{"age": nil}); err == nil {
+		t.Fatal("nullable: false must not allow null")
+	}
+	var got []string
+	for _, w := range c.Warnings() {
+		got = append(got, fmt.Sprintf("%s %T", w.Keyword, w.Kind))
+	}
+	want := []string{"nullable *jsonschema.Nullable"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("warnings: got %q, want %q", got, want)
+	}
+}
+
+func TestCompileAllUnder(t *testing.T) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"openapi": "3.1.0",
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": { "owner": { "$ref": "#/components/schemas/Person" } }
+				},
+				"Person": {
+					"type": "object",
+					"properties": { "name": { "type": "string" } },
+					"required": ["name"]
+				},
+				"a/b": { "type": "integer" }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("openapi.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := c.CompileAllUnder("openapi.json#/components/schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 3 {
+		t.Fatalf("got %d schemas, want 3", len(schemas))
+	}
+	if schemas["Pet"].Properties["owner"].Ref != schemas["Person"] {
+		t.Fatal("Pet must refer to same Person schema")
+	}
+	if err := schemas["Pet"].Validate(map[string]any{"owner": map[string]any{}}); err == nil {
+		t.Fatal("owner must require name")
+	}
+	if err := schemas["a/b"].Validate("x"); err == nil {
+		t.Fatal("a/b must be integer")
+	}
+	sch, err := c.Compile("openapi.json#/components/schemas/Person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch != schemas["Person"] {
+		t.Fatal("Compile must return already compiled schema")
+	}
+
+	_, err = c.CompileAllUnder("openapi.json#/openapi")
+	if _, ok := err.(*jsonschema.NotAnObjectError); !ok {
+		t.Fatalf("got %#v, want NotAnObjectError", err)
+	}
+}
+
+type refExt struct {
+	sch *jsonschema.Schema
+}
+
+func (s *refExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	if err := ctx.Validate(s.sch, v, nil); err != nil {
+		ctx.AddErr(err)
+	}
+}
+
+func TestCompilerContext(t *testing.T) {
+	var draft *jsonschema.Draft
+	var dialect, baseURL, location string
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/meta/x-ref",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			ref, ok := obj["x-ref"].(string)
+			if !ok {
+				return nil, nil
+			}
+			draft, dialect, baseURL, location = ctx.Draft(), ctx.Dialect(), ctx.BaseURL(), ctx.Location()
+			sch, err := ctx.EnqueueRef(ref)
+			if err != nil {
+				return nil, err
+			}
+			return &refExt{sch}, nil
+		},
+	}
+
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("http://example.com/types.json", map[string]any{
+		"$defs": map[string]any{"int": map[string]any{"type": "integer"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("http://example.com/schemas/schema.json", map[string]any{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": map[string]any{
+			"x": map[string]any{"x-ref": "../types.json#/$defs/int"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("http://example.com/schemas/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft != jsonschema.Draft7 {
+		t.Errorf("draft: got %v, want %v", draft, jsonschema.Draft7)
+	}
+	if dialect != "http://json-schema.org/draft-07/schema" {
+		t.Errorf("dialect: got %q", dialect)
+	}
+	if baseURL != "http://example.com/schemas/schema.json" {
+		t.Errorf("baseURL: got %q", baseURL)
+	}
+	if location != "http://example.com/schemas/schema.json#/properties/x" {
+		t.Errorf("location: got %q", location)
+	}
+	if err := sch.Validate(map[string]any{"x": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(map[string]any{"x": "one"}); err == nil {
+		t.Fatal("x must be integer")
+	}
+}
+
+type dynamicRefExt struct {
+	anchor string
+	locs   *[][]string
+	scope  *[]*jsonschema.Schema
+}
+
+func (s *dynamicRefExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	*s.locs = append(*s.locs, ctx.InstanceLocation())
+	*s.scope = ctx.Scope()
+	if sch := ctx.ResolveDynamicAnchor(s.anchor); sch != nil {
+		if err := ctx.Validate(sch, v, nil); err != nil {
+			ctx.AddErr(err)
+		}
+	}
+}
+
+func TestValidatorContextDynamicScope(t *testing.T) {
+	var locs [][]string
+	var scope []*jsonschema.Schema
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/meta/x-dynamicRef",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			if anchor, ok := obj["x-dynamicRef"].(string); ok {
+				return &dynamicRefExt{anchor, &locs, &scope}, nil
+			}
+			return nil, nil
+		},
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("http://example.com/tree", map[string]any{
+		"$dynamicAnchor": "node",
+		"properties": map[string]any{
+			"children": map[string]any{
+				"items": map[string]any{"x-dynamicRef": "node"},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("http://example.com/strict", map[string]any{
+		"$dynamicAnchor":        "node",
+		"$ref":                  "tree",
+		"unevaluatedProperties": false,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := c.Compile("http://example.com/tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict, err := c.Compile("http://example.com/strict")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst := map[string]any{"children": []any{map[string]any{"foo": 1}}}
+	if err := tree.Validate(inst); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"children", "0"}}; !reflect.DeepEqual(locs, want) {
+		t.Fatalf("InstanceLocation: got %q, want %q", locs, want)
+	}
+	var scopeLocs []string
+	for _, sch := range scope {
+		scopeLocs = append(scopeLocs, sch.Location)
+	}
+	wantScope := []string{
+		"http://example.com/tree#",
+		"http://example.com/tree#/properties/children",
+		"http://example.com/tree#/properties/children/items",
+	}
+	if !reflect.DeepEqual(scopeLocs, wantScope) {
+		t.Fatalf("Scope: got %q, want %q", scopeLocs, wantScope)
+	}
+	if err := strict.Validate(inst); err == nil {
+		t.Fatal("child must be validated with strict schema")
+	}
+}
+
+type selfExt struct {
+	depths    *[][2]int
+	recovered *[]any
+}
+
+func (s *selfExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	defer func() {
+		if r := recover(); r != nil {
+			*s.recovered = append(*s.recovered, r)
+		}
+	}()
+	*s.depths = append(*s.depths, [2]int{ctx.Depth(), ctx.ScopeDepth()})
+	scope := ctx.Scope()
+	self := scope[len(scope)-1]
+	if err := ctx.Validate(self, v, nil); err != nil {
+		ctx.AddErr(err)
+	}
+	if err := ctx.Validate(self, struct{}{}, []string{"a", "b"}); err != nil {
+		ctx.AddErr(err)
+	}
+}
+
+func TestValidatorContextNoPanics(t *testing.T) {
+	var depths [][2]int
+	var recovered []any
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/meta/x-self",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			if _, ok := obj["x-self"]; ok {
+				return &selfExt{&depths, &recovered}, nil
+			}
+			return nil, nil
+		},
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", map[string]any{
+		"properties": map[string]any{
+			"x": map[string]any{"x-self": true},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sch.Validate(map[string]any{"x": 1})
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if len(recovered) != 0 {
+		t.Fatalf("extension recovered %v", recovered)
+	}
+	for _, want := range []string{"reference cycle", "invalid jsonType"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error must contain %q: %v", want, err)
+		}
+	}
+	if len(depths) == 0 || depths[0] != [2]int{1, 2} {
+		t.Fatalf("depths: got %v, want [1 2] first", depths)
+	}
+}
+
+type allExt struct {
+	subschemas []*jsonschema.Schema
+}
+
+func (s *allExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	var causes []error
+	for _, sch := range s.subschemas {
+		causes = append(causes, ctx.Validate(sch, v, nil))
+	}
+	ctx.AddErrors(&kind.Custom{
+		Keyword: []string{"x-all"},
+		Format:  "x-all: %d subschemas",
+		Args:    []any{len(s.subschemas)},
+	}, causes...)
+	if len(s.subschemas) == 0 {
+		ctx.AddErrorf([]string{"x-all"}, "x-all: empty")
+	}
+}
+
+func TestValidatorContextAddErrors(t *testing.T) {
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/meta/x-all",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			arr, ok := obj["x-all"].([]any)
+			if !ok {
+				return nil, nil
+			}
+			ext := &allExt{}
+			for i := range arr {
+				ext.subschemas = append(ext.subschemas, ctx.Enqueue([]string{"x-all", strconv.Itoa(i)}))
+			}
+			return ext, nil
+		},
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("http://example.com/schema.json", map[string]any{
+		"properties": map[string]any{
+			"x": map[string]any{"x-all": []any{
+				map[string]any{"type": "integer"},
+				map[string]any{"minimum": 10},
+			}},
+			"y": map[string]any{"x-all": []any{}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("http://example.com/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(map[string]any{"x": 20}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = sch.Validate(map[string]any{"x": 1.5})
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatalf("want ValidationError, got %v", err)
+	}
+	group := verr.Causes[0]
+	if group.SchemaURL != "http://example.com/schema.json#/properties/x" || group.KeywordPath().String() != "/x-all" {
+		t.Errorf("got %q, %q", group.SchemaURL, group.KeywordPath())
+	}
+	if len(group.Causes) != 2 {
+		t.Fatalf("got %d causes, want 2", len(group.Causes))
+	}
+
+	cat := catalog.NewBuilder()
+	if err := cat.SetString(language.French, "x-all: %d subschemas", "x-all: %d sous-schémas"); err != nil {
+		t.Fatal(err)
+	}
+	p := message.NewPrinter(language.French, message.Catalog(cat))
+	if got, want := group.ErrorKind.LocalizedString(p), "x-all: 2 sous-schémas"; got != want {
+		t.Errorf("localized: got %q, want %q", got, want)
+	}
+
+	err = sch.Validate(map[string]any{"y": 1})
+	if err == nil || !strings.Contains(err.Error(), "x-all: empty") {
+		t.Errorf("want x-all: empty error, got %v", err)
+	}
+}
+
+type tupleExt struct {
+	n int
+}
+
+func (s *tupleExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	if arr, ok := v.([]any); ok && len(arr) >= s.n {
+		ctx.EvaluatedItems(0, s.n)
+		ctx.AddAnnotation("x-tuple", s.n)
+	}
+}
+
+func TestValidatorContextAnnotations(t *testing.T) {
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/meta/x-tuple",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			if n, ok := obj["x-tuple"].(int); ok {
+				return &tupleExt{n}, nil
+			}
+			return nil, nil
+		},
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", map[string]any{
+		"properties": map[string]any{
+			"pair": map[string]any{
+				"anyOf": []any{
+					map[string]any{"x-tuple": 2},
+					map[string]any{"x-tuple": 3, "type": "string"},
+				},
+				"unevaluatedItems": false,
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annots, err := sch.Annotations(map[string]any{"pair": []any{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(annots) != 1 {
+		t.Fatalf("got %d annotations, want 1", len(annots))
+	}
+	a := annots[0]
+	if !reflect.DeepEqual(a.InstanceLocation, []string{"pair"}) || a.Keyword != "x-tuple" || a.Value != 2 {
+		t.Fatalf("got %+v", a)
+	}
+	if a.SchemaURL != sch.Properties["pair"].AnyOf[0].Location {
+		t.Fatalf("SchemaURL: got %q", a.SchemaURL)
+	}
+
+	if err := sch.Validate(map[string]any{"pair": []any{1, 2, 3}}); err == nil {
+		t.Fatal("third item must not be evaluated")
+	}
+}
+
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   []string
+	}{
+		{
+			`{"type": "object", "properties": {"a": {"type": "string"}}, "additionalProperties": false}`,
+			nil,
+		},
+		{
+			`{"format": "emial", "type": ["string", "null"]}`,
+			[]string{`at "http://example.com/schema.json#/format": unknown format 'emial'`},
+		},
+		{
+			`{"type": ["string", "number"], "properties": {"a": true}}`,
+			[]string{
+				`at "http://example.com/schema.json#/properties": additionalProperties not specified, allows any additional property`,
+				`at "http://example.com/schema.json#/type": type union string, number is ambiguous`,
+			},
+		},
+		{
+			`{"requird": ["a"], "definitions": {}}`,
+			[]string{
+				`at "http://example.com/schema.json#/definitions": keyword 'definitions' has no effect in draft 2020, it has effect only in drafts 4, 6, 7`,
+				`at "http://example.com/schema.json#/requird": unknown keyword 'requird', did you mean 'required'`,
+			},
+		},
+	}
+	for i, test := range tests {
+		schema, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := jsonschema.NewCompiler()
+		c.Strict()
+		if err := c.AddResource("http://example.com/schema.json", schema); err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.Compile("http://example.com/schema.json")
+		if test.want == nil {
+			if err != nil {
+				t.Errorf("test %d: %v", i, err)
+			}
+			continue
+		}
+		serr, ok := err.(*jsonschema.StrictModeError)
+		if !ok {
+			t.Errorf("test %d: got %v, want StrictModeError", i, err)
+			continue
+		}
+		var got []string
+		for _, w := range serr.Warnings {
+			got = append(got, w.String())
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("test %d:\n got %q\nwant %q", i, got, test.want)
+		}
+	}
+}
+
+func TestRefTargets(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"oneOf": [
+			{ "$ref": "#/$defs/a" },
+			{ "$ref": "#/$defs/b" }
+		],
+		"$defs": {
+			"a": { "type": "string" },
+			"b": {
+				"$dynamicAnchor": "b",
+				"items": { "$dynamicRef": "#b" }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("http://example.com/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, rt := range sch.RefTargets() {
+		got = append(got, fmt.Sprintf("%s -> %s", rt.KeywordLocation, rt.Target.Location))
+	}
+	want := []string{
+		"http://example.com/schema.json#/oneOf/0/$ref -> http://example.com/schema.json#/$defs/a",
+		"http://example.com/schema.json#/oneOf/1/$ref -> http://example.com/schema.json#/$defs/b",
+		"http://example.com/schema.json#/$defs/b/items/$dynamicRef -> http://example.com/schema.json#/$defs/b",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestAddResourceJSON(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(`{"$ref": "max.json"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResourceReader("max.json", strings.NewReader(`{"maximum": 12345678901234567890}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst, err := jsonschema.UnmarshalJSON(strings.NewReader(`12345678901234567891`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.Validate(inst) == nil {
+		t.Fatal("want validation error, as maximum must not lose precision")
+	}
+
+	err = c.AddResourceJSON("bad.json", []byte(`{"type": `))
+	if _, ok := err.(*jsonschema.LoadURLError); !ok {
+		t.Fatalf("got %#v, want *LoadURLError", err)
+	}
+	err = c.AddResourceJSON("schema.json", []byte(`{}`))
+	if _, ok := err.(*jsonschema.ResourceExistsError); !ok {
+		t.Fatalf("got %#v, want *ResourceExistsError", err)
+	}
+}
+
+func TestAssertFormats(t *testing.T) {
+	schema := `{
+		"properties": {
+			"id": {"format": "uuid"},
+			"email": {"format": "email"}
+		}
+	}`
+	tests := []struct {
+		name   string
+		config func(c *jsonschema.Compiler)
+		id     bool // id asserted
+		email  bool // email asserted
+	}{
+		{"default", func(c *jsonschema.Compiler) {}, false, false},
+		{"all", func(c *jsonschema.Compiler) { c.AssertFormat() }, true, true},
+		{"subset", func(c *jsonschema.Compiler) { c.AssertFormats("uuid") }, true, false},
+		{"overridden", func(c *jsonschema.Compiler) { c.AssertFormat(); c.AssertFormats("email") }, false, true},
+		{"none", func(c *jsonschema.Compiler) { c.AssertFormats() }, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			test.config(c)
+			if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := !sch.IsValid(map[string]any{"id": "x"}); got != test.id {
+				t.Errorf("id asserted: got %v, want %v", got, test.id)
+			}
+			if got := !sch.IsValid(map[string]any{"email": "x"}); got != test.email {
+				t.Errorf("email asserted: got %v, want %v", got, test.email)
+			}
+		})
+	}
+}
+
+func TestRefInterceptor(t *testing.T) {
+	models := map[string]any{
+		"User@v3": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"name": map[string]any{"$ref": "internal://model/Name@v1"}},
+		},
+		"Name@v1": map[string]any{"type": "string", "minLength": 2},
+	}
+	var calls []string
+	c := jsonschema.NewCompiler()
+	c.UseRefInterceptor(func(loc, target string) (string, any, error) {
+		calls = append(calls, target)
+		name, ok := strings.CutPrefix(target, "internal://model/")
+		if !ok {
+			return target, nil, nil
+		}
+		name = strings.TrimSuffix(name, "#")
+		switch name {
+		case "Old":
+			// rewrite target
+			return "internal://model/User@v3", nil, nil
+		case "Ping":
+			return "internal://model/Pong", nil, nil
+		case "Pong":
+			return "internal://model/Ping", nil, nil
+		}
+		doc, ok := models[name]
+		if !ok {
+			return "", nil, fmt.Errorf("model %s not found", name)
+		}
+		return target, doc, nil
+	})
+	if err := c.AddResource("schema.json", map[string]any{
+		"properties": map[string]any{
+			"user":   map[string]any{"$ref": "internal://model/User@v3"},
+			"legacy": map[string]any{"$ref": "internal://model/Old"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.Properties["legacy"].Ref != sch.Properties["user"].Ref {
+		t.Error("rewritten ref must resolve to same schema")
+	}
+	if err := sch.Validate(map[string]any{"user": map[string]any{"name": "ab"}}); err != nil {
+		t.Error(err)
+	}
+	if err := sch.Validate(map[string]any{"user": map[string]any{"name": "a"}}); err == nil {
+		t.Error("want error")
+	}
+	if !slices.Contains(calls, "internal://model/Name@v1#") {
+		t.Errorf("interceptor not called for nested ref: %v", calls)
+	}
+
+	// error
+	if err := c.AddResource("missing.json", map[string]any{"$ref": "internal://model/Missing"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Compile("missing.json")
+	var ierr *jsonschema.RefInterceptorError
+	if !errors.As(err, &ierr) {
+		t.Fatalf("want RefInterceptorError, got %v", err)
+	}
+	if ierr.Target != "internal://model/Missing#" {
+		t.Errorf("target: got %q", ierr.Target)
+	}
+
+	// rewrite cycle
+	if err := c.AddResource("cycle.json", map[string]any{"$ref": "internal://model/Ping"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Compile("cycle.json")
+	if !errors.As(err, &ierr) {
+		t.Fatalf("want RefInterceptorError, got %v", err)
+	}
+}
+
+func TestOverrideMetaschema(t *testing.T) {
+	const meta = "https://json-schema.org/draft/2020-12/schema"
+	c := jsonschema.NewCompiler()
+	err := c.OverrideMetaschema(meta, map[string]any{
+		"$schema":        meta,
+		"$id":            meta,
+		"$dynamicAnchor": "meta",
+		"allOf": []any{
+			map[string]any{"$ref": "meta/core"},
+			map[string]any{"$ref": "meta/applicator"},
+			map[string]any{"$ref": "meta/validation"},
+			map[string]any{"$ref": "meta/meta-data"},
+		},
+		"required": []any{"title"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("untitled.json", map[string]any{"type": "string"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("untitled.json"); err == nil {
+		t.Error("want error for schema without title")
+	}
+	if err := c.AddResource("titled.json", map[string]any{
+		"title": "name",
+		"type":  "string",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("titled.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// other compilers are not affected
+	c = jsonschema.NewCompiler()
+	if err := c.AddResource("untitled.json", map[string]any{"type": "string"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("untitled.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// errors
+	var oerr *jsonschema.MetaschemaOverrideError
+	if err := c.OverrideMetaschema("http://example.com/meta.json", map[string]any{}); !errors.As(err, &oerr) {
+		t.Errorf("want MetaschemaOverrideError for non-embedded url, got %v", err)
+	}
+	err = c.OverrideMetaschema(meta, map[string]any{
+		"$schema": meta,
+		"$id":     meta,
+		"allOf":   []any{map[string]any{"$ref": meta}},
+	})
+	if !errors.As(err, &oerr) {
+		t.Errorf("want MetaschemaOverrideError for cycle, got %v", err)
+	}
+}
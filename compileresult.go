@@ -0,0 +1,150 @@
+package jsonschema
+
+import "slices"
+
+// CompileResult is the result of [Compiler.CompileWithWarnings].
+type CompileResult struct {
+	Schema *Schema
+
+	// Warnings found while compiling Schema. These are
+	// advice for tooling, and do not affect validation.
+	Warnings []*Warning
+}
+
+// CompileWithWarnings is like [Compiler.Compile], but also returns
+// warnings found while compiling the schema. In addition to the
+// warnings enabled on c, such as [Compiler.WarnUnknownKeywords],
+// following are reported:
+//   - [*UnknownFormat] for formats neither builtin nor registered
+//   - [*DeprecatedKeyword] for keywords deprecated by the metaschema,
+//     such as `definitions` in draft 2020-12
+//   - [*RefSibling] for keywords ignored, as siblings of `$ref`
+//     in drafts before 2019-09
+//   - [*UnreachableDef] for `$defs` and `definitions` entries, which
+//     are not referenced from the schema
+//   - [*OverlappingPatterns] for regexes of `patternProperties`,
+//     which match same property name. These are detected using
+//     sample names, so not all overlaps are reported
+//
+// Only the warnings enabled on c are available via [Compiler.Warnings],
+// and are considered in strict mode.
+//
+// Warnings are computed from the schemas reachable from the compiled
+// schema, so same warnings are returned, even if the schema is already
+// compiled by c.
+func (c *Compiler) CompileWithWarnings(loc string) (*CompileResult, error) {
+	sch, err := c.Compile(loc)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []*Schema
+	walkSchemas(sch, func(s *Schema) {
+		schemas = append(schemas, s)
+	})
+
+	// warnings enabled on c, reported when schemas were compiled
+	kwURLs := map[string]bool{}
+	for _, s := range schemas {
+		if obj, ok := s.doc.(map[string]any); ok {
+			for kw := range obj {
+				kwURLs[s.up.format(kw)] = true
+			}
+		}
+	}
+	var warnings []*Warning
+	for _, w := range c.warnings {
+		if kwURLs[w.SchemaURL] {
+			warnings = append(warnings, w)
+		}
+	}
+
+	reachable := make(map[urlPtr]bool, len(schemas))
+	for _, s := range schemas {
+		reachable[s.up] = true
+	}
+	for _, s := range schemas {
+		warnings = append(warnings, c.advice(s, reachable)...)
+	}
+	return &CompileResult{Schema: sch, Warnings: warnings}, nil
+}
+
+// advice returns warnings of compiled schema s, which are
+// reported only by CompileWithWarnings. reachable has
+// locations of schemas reachable from the schema compiled.
+func (c *Compiler) advice(s *Schema, reachable map[urlPtr]bool) []*Warning {
+	obj, ok := s.doc.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var warnings []*Warning
+	advise := func(kw string, kind WarningKind) {
+		warnings = append(warnings, &Warning{
+			SchemaURL: s.up.format(kw),
+			Keyword:   kw,
+			Kind:      kind,
+		})
+	}
+
+	if f, ok := obj["format"].(string); ok && !c.strict {
+		// in strict mode, reported by warnStrict
+		if f != "regex" && c.formats[f] == nil && formats[f] == nil {
+			advise("format", &UnknownFormat{Format: f})
+		}
+	}
+
+	kws := make([]string, 0, len(obj))
+	for kw := range obj {
+		kws = append(kws, kw)
+	}
+	slices.Sort(kws)
+	schemas := c.keywordSchemas(&s.dialect)
+	for _, kw := range kws {
+		if slices.ContainsFunc(schemas, func(sch *Schema) bool {
+			s := sch.Properties[kw]
+			return s != nil && s.Deprecated
+		}) {
+			advise(kw, &DeprecatedKeyword{Keyword: kw})
+		}
+	}
+
+	if _, ok := obj["$ref"]; ok && s.DraftVersion < 2019 {
+		for _, kw := range kws {
+			switch kw {
+			case "$ref", "$schema", "$comment", "definitions":
+				// not ignored, or have no effect anyway
+			default:
+				advise(kw, &RefSibling{Keyword: kw})
+			}
+		}
+	}
+
+	for _, p := range overlappingPatterns(s) {
+		advise("patternProperties", &OverlappingPatterns{
+			Patterns: [2]string{p[0], p[1]},
+			Example:  p[2],
+		})
+	}
+
+	for _, kw := range []string{"$defs", "definitions"} {
+		defs, ok := obj[kw].(map[string]any)
+		if !ok {
+			continue
+		}
+		names := make([]string, 0, len(defs))
+		for name := range defs {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			up := urlPtr{s.up.url, s.up.ptr.append2(kw, name)}
+			if !reachable[up] {
+				warnings = append(warnings, &Warning{
+					SchemaURL: up.String(),
+					Keyword:   kw,
+					Kind:      &UnreachableDef{Keyword: kw, Name: name},
+				})
+			}
+		}
+	}
+	return warnings
+}
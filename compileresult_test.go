@@ -0,0 +1,135 @@
+package jsonschema_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestCompileWithWarnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   map[string]any
+		wants []string // SchemaURL of warnings, in order
+	}{
+		{
+			name: "unknown format",
+			doc:  map[string]any{"type": "string", "format": "zipcode"},
+			wants: []string{
+				"schema.json#/format",
+			},
+		},
+		{
+			name: "deprecated keyword",
+			doc: map[string]any{
+				"$ref":        "#/definitions/a",
+				"definitions": map[string]any{"a": true},
+			},
+			wants: []string{
+				"schema.json#/definitions",
+			},
+		},
+		{
+			name: "ref siblings",
+			doc: map[string]any{
+				"$schema":     "http://json-schema.org/draft-07/schema#",
+				"$ref":        "#/definitions/a",
+				"type":        "string",
+				"definitions": map[string]any{"a": true},
+			},
+			wants: []string{
+				"schema.json#/type",
+			},
+		},
+		{
+			name: "unreachable defs",
+			doc: map[string]any{
+				"$ref": "#/$defs/a",
+				"$defs": map[string]any{
+					"a": map[string]any{"$ref": "#/$defs/b"},
+					"b": true,
+					"c": map[string]any{"$ref": "#/$defs/d"},
+					"d": true,
+				},
+			},
+			wants: []string{
+				"schema.json#/$defs/c",
+				"schema.json#/$defs/d",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			if err := c.AddResource("http://example.com/schema.json", test.doc); err != nil {
+				t.Fatal(err)
+			}
+			result, err := c.CompileWithWarnings("http://example.com/schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Schema == nil {
+				t.Fatal("schema is nil")
+			}
+			var got []string
+			for _, w := range result.Warnings {
+				got = append(got, strings.TrimPrefix(w.SchemaURL, "http://example.com/"))
+			}
+			if !slices.Equal(got, test.wants) {
+				t.Errorf("got %v, want %v", got, test.wants)
+			}
+			if ws := c.Warnings(); len(ws) != 0 {
+				t.Errorf("compiler warnings: got %v, want none", ws)
+			}
+		})
+	}
+}
+
+func TestCompileWithWarningsTwice(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.WarnUnknownKeywords()
+	if err := c.AddResource("http://example.com/schema.json", map[string]any{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"$ref":        "#/definitions/a",
+		"format":      "zipcode",
+		"typo":        1,
+		"definitions": map[string]any{"a": true, "b": true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// other root, referring to unreachable def
+	if err := c.AddResource("http://example.com/other.json", map[string]any{
+		"$ref": "schema.json#/definitions/b",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	warnings := func(loc string) []string {
+		t.Helper()
+		result, err := c.CompileWithWarnings(loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, w := range result.Warnings {
+			got = append(got, strings.TrimPrefix(w.SchemaURL, "http://example.com/"))
+		}
+		return got
+	}
+	want := []string{
+		"schema.json#/typo",
+		"schema.json#/format",
+		"schema.json#/format",
+		"schema.json#/typo",
+		"schema.json#/definitions/b",
+	}
+	if _, err := c.Compile("http://example.com/other.json"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if got := warnings("http://example.com/schema.json"); !slices.Equal(got, want) {
+			t.Errorf("call %d: got %v, want %v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package jsonschema_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+func TestContainsConstraint(t *testing.T) {
+	tests := []struct {
+		schema   string
+		draft    *jsonschema.Draft
+		min, max int
+		trivial  bool
+	}{
+		{`{"contains": true}`, jsonschema.Draft2020, 1, -1, false},
+		{`{"contains": true, "minContains": 0}`, jsonschema.Draft2020, 0, -1, true},
+		{`{"contains": true, "minContains": 0, "maxContains": 2}`, jsonschema.Draft2019, 0, 2, false},
+		{`{"contains": true, "minContains": 0}`, jsonschema.Draft7, 1, -1, false},
+	}
+	for _, test := range tests {
+		c := jsonschema.NewCompiler()
+		c.DefaultDraft(test.draft)
+		if err := c.AddResource("schema.json", mustUnmarshal(t, test.schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cc := sch.ContainsConstraint()
+		if cc.Schema != sch.Contains || cc.Min != test.min || cc.Max != test.max || cc.Trivial() != test.trivial {
+			t.Errorf("%s with %v: got %+v", test.schema, test.draft, cc)
+		}
+	}
+}
+
+func TestContainsFailed(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	schema := `{"contains": {"type": "integer"}, "minContains": 2}`
+	if err := c.AddResource("schema.json", mustUnmarshal(t, schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sch.Validate(mustUnmarshal(t, `["a", 1, "b"]`))
+	verr := err.(*jsonschema.ValidationError).Causes[0]
+	k, ok := verr.ErrorKind.(*kind.MinContains)
+	if !ok {
+		t.Fatalf("got %#v, want MinContains", verr.ErrorKind)
+	}
+	if !slices.Equal(k.Got, []int{1}) || !slices.Equal(k.Failed, []int{0, 2}) {
+		t.Fatalf("got %+v", k)
+	}
+}
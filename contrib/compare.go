@@ -0,0 +1,324 @@
+package contrib
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// CompareVocab returns vocabulary for cross-field rules.
+//
+// It introduces keyword `x-compare`, which compares value at
+// `pointer` with value at another location. Both are json-pointers
+// relative to the object being validated:
+//
+//	{
+//	    "x-compare": [
+//	        { "pointer": "/start", "lessThan": "/end" },
+//	        { "pointer": "/confirm", "equals": "/password" }
+//	    ]
+//	}
+//
+// Supported operators are `equals`, `notEquals`, `lessThan`,
+// `lessThanOrEqual`, `greaterThan` and `greaterThanOrEqual`.
+// Ordering operators work on numbers and strings. Rule is
+// ignored if any of the values is missing.
+//
+// It also introduces keyword `x-requiredIf`, which requires
+// properties when a property has specific value:
+//
+//	{
+//	    "x-requiredIf": [
+//	        { "property": "country", "value": "US", "required": ["state"] }
+//	    ]
+//	}
+func CompareVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("compare", `{
+		"$defs": {
+			"pointer": { "type": "string", "format": "json-pointer" },
+			"compare": {
+				"type": "object",
+				"properties": {
+					"pointer":            { "$ref": "#/$defs/pointer" },
+					"equals":             { "$ref": "#/$defs/pointer" },
+					"notEquals":          { "$ref": "#/$defs/pointer" },
+					"lessThan":           { "$ref": "#/$defs/pointer" },
+					"lessThanOrEqual":    { "$ref": "#/$defs/pointer" },
+					"greaterThan":        { "$ref": "#/$defs/pointer" },
+					"greaterThanOrEqual": { "$ref": "#/$defs/pointer" }
+				},
+				"required": ["pointer"],
+				"minProperties": 2,
+				"maxProperties": 2
+			},
+			"requiredIf": {
+				"type": "object",
+				"properties": {
+					"property": { "type": "string" },
+					"value": true,
+					"required": {
+						"type": "array",
+						"items": { "type": "string" },
+						"uniqueItems": true
+					}
+				},
+				"required": ["property", "value", "required"],
+				"additionalProperties": false
+			}
+		},
+		"properties": {
+			"x-compare": {
+				"oneOf": [
+					{ "$ref": "#/$defs/compare" },
+					{ "type": "array", "items": { "$ref": "#/$defs/compare" } }
+				]
+			},
+			"x-requiredIf": {
+				"oneOf": [
+					{ "$ref": "#/$defs/requiredIf" },
+					{ "type": "array", "items": { "$ref": "#/$defs/requiredIf" } }
+				]
+			}
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileCompare,
+	}
+}
+
+var compareOps = []string{"equals", "notEquals", "lessThan", "lessThanOrEqual", "greaterThan", "greaterThanOrEqual"}
+
+type compareRule struct {
+	index   int // -1 if x-compare is not array
+	pointer string
+	op      string
+	other   string
+}
+
+type requiredIfRule struct {
+	property string
+	value    any
+	required []string
+}
+
+type compare struct {
+	rules      []compareRule
+	requiredIf []requiredIfRule
+}
+
+func compileCompare(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	var ext compare
+	_, isArr := obj["x-compare"].([]any)
+	for i, item := range oneOrMany(obj["x-compare"]) {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if !isArr {
+			i = -1
+		}
+		ptr, _ := m["pointer"].(string)
+		for _, op := range compareOps {
+			if other, ok := m[op].(string); ok {
+				ext.rules = append(ext.rules, compareRule{i, ptr, op, other})
+			}
+		}
+	}
+	for _, item := range oneOrMany(obj["x-requiredIf"]) {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		rule := requiredIfRule{value: m["value"]}
+		rule.property, _ = m["property"].(string)
+		arr, _ := m["required"].([]any)
+		for _, item := range arr {
+			if s, ok := item.(string); ok {
+				rule.required = append(rule.required, s)
+			}
+		}
+		ext.requiredIf = append(ext.requiredIf, rule)
+	}
+	if len(ext.rules) == 0 && len(ext.requiredIf) == 0 {
+		return nil, nil
+	}
+	return &ext, nil
+}
+
+func oneOrMany(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
+func (s *compare) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	for _, rule := range s.rules {
+		got, ok := lookup(v, rule.pointer)
+		if !ok {
+			continue
+		}
+		want, ok := lookup(v, rule.other)
+		if !ok {
+			continue
+		}
+		var matched bool
+		switch rule.op {
+		case "equals", "notEquals":
+			eq, err := ctx.Equals(got, want)
+			if err != nil {
+				ctx.AddErr(err)
+				continue
+			}
+			matched = eq == (rule.op == "equals")
+		default:
+			cmp, ok := compareValues(got, want)
+			if !ok {
+				matched = false
+				break
+			}
+			switch rule.op {
+			case "lessThan":
+				matched = cmp < 0
+			case "lessThanOrEqual":
+				matched = cmp <= 0
+			case "greaterThan":
+				matched = cmp > 0
+			case "greaterThanOrEqual":
+				matched = cmp >= 0
+			}
+		}
+		if !matched {
+			ctx.AddError(&Compare{
+				Index:   rule.index,
+				Pointer: rule.pointer,
+				Op:      rule.op,
+				Other:   rule.other,
+				Got:     got,
+				Want:    want,
+			})
+		}
+	}
+
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, rule := range s.requiredIf {
+		pvalue, ok := obj[rule.property]
+		if !ok {
+			continue
+		}
+		if eq, err := ctx.Equals(pvalue, rule.value); err != nil || !eq {
+			continue
+		}
+		var missing []string
+		for _, pname := range rule.required {
+			if _, ok := obj[pname]; !ok {
+				missing = append(missing, pname)
+			}
+		}
+		if len(missing) > 0 {
+			ctx.AddError(&RequiredIf{Property: rule.property, Value: rule.value, Missing: missing})
+		}
+	}
+}
+
+// compareValues compares two numbers or two strings.
+// returns false if values are not comparable.
+func compareValues(v1, v2 any) (int, bool) {
+	if s1, ok := v1.(string); ok {
+		s2, ok := v2.(string)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case s1 < s2:
+			return -1, true
+		case s1 > s2:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	n1, ok1 := toRat(v1)
+	n2, ok2 := toRat(v2)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	return n1.Cmp(n2), true
+}
+
+func toRat(v any) (*big.Rat, bool) {
+	switch v.(type) {
+	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return new(big.Rat).SetString(fmt.Sprint(v))
+	}
+	return nil, false
+}
+
+// ErrorKind --
+
+// Compare is the ErrorKind reported when `x-compare` rule fails.
+type Compare struct {
+	Index   int    // index of rule, -1 if x-compare is not array
+	Pointer string // json-pointer of value compared
+	Op      string // comparison operator
+	Other   string // json-pointer of value compared with
+	Got     any    // value at Pointer
+	Want    any    // value at Other
+}
+
+func (k *Compare) KeywordPath() []string {
+	if k.Index < 0 {
+		return []string{"x-compare"}
+	}
+	return []string{"x-compare", strconv.Itoa(k.Index)}
+}
+
+func (k *Compare) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("value at %s must be %s value at %s, but %s and %s", quote(k.Pointer), opText(k.Op), quote(k.Other), display(k.Got), display(k.Want))
+}
+
+func opText(op string) string {
+	switch op {
+	case "equals":
+		return "equal to"
+	case "notEquals":
+		return "not equal to"
+	case "lessThan":
+		return "less than"
+	case "lessThanOrEqual":
+		return "less than or equal to"
+	case "greaterThan":
+		return "greater than"
+	case "greaterThanOrEqual":
+		return "greater than or equal to"
+	}
+	return op
+}
+
+// RequiredIf is the ErrorKind reported when `x-requiredIf` rule fails.
+type RequiredIf struct {
+	Property string   // property checked
+	Value    any      // value of property
+	Missing  []string // missing properties
+}
+
+func (*RequiredIf) KeywordPath() []string {
+	return []string{"x-requiredIf"}
+}
+
+func (k *RequiredIf) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("properties %s required, if %s is %s", joinQuoted(k.Missing, ", "), quote(k.Property), display(k.Value))
+}
@@ -0,0 +1,95 @@
+package contrib_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+type vocabTest struct {
+	data  string
+	valid bool
+}
+
+func testVocab(t *testing.T, vocab *jsonschema.Vocabulary, schema string, tests []vocabTest) {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sch.Validate(inst)
+		if got := err == nil; got != test.valid {
+			t.Errorf("%s: valid got %v, want %v", test.data, got, test.valid)
+		}
+		if err != nil {
+			t.Log(err)
+		}
+	}
+}
+
+func testInvalidSchema(t *testing.T, vocab *jsonschema.Vocabulary, schema string) {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("schema.json"); err == nil {
+		t.Fatalf("%s: want compilation to fail", schema)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testVocab(t, contrib.CompareVocab(), `{
+		"x-compare": [
+			{ "pointer": "/start", "lessThan": "/end" },
+			{ "pointer": "/confirm", "equals": "/password" }
+		]
+	}`, []vocabTest{
+		{`{"start": 1, "end": 2}`, true},
+		{`{"start": 2, "end": 2}`, false},
+		{`{"start": "2020-01-01", "end": "2021-01-01"}`, true},
+		{`{"start": "2022-01-01", "end": "2021-01-01"}`, false},
+		{`{"start": 1, "end": "x"}`, false},
+		{`{"start": 1}`, true},
+		{`{"password": "a", "confirm": "a"}`, true},
+		{`{"password": "a", "confirm": "b"}`, false},
+	})
+}
+
+func TestRequiredIf(t *testing.T) {
+	testVocab(t, contrib.CompareVocab(), `{
+		"x-requiredIf": { "property": "country", "value": "US", "required": ["state"] }
+	}`, []vocabTest{
+		{`{"country": "US", "state": "CA"}`, true},
+		{`{"country": "US"}`, false},
+		{`{"country": "IN"}`, true},
+	})
+}
+
+func TestCompareInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.CompareVocab(), `{"x-compare": {"pointer": "/a"}}`)
+	testInvalidSchema(t, contrib.CompareVocab(), `{"x-requiredIf": {"property": "a"}}`)
+}
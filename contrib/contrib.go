@@ -0,0 +1,71 @@
+// Package contrib provides maintained implementations of popular
+// non-standard keywords as opt-in vocabularies.
+//
+// To use a vocabulary, register it with the compiler and enable it
+// either via `$vocabulary` in metaschema or with [jsonschema.Compiler.AssertVocabs]:
+//
+//	c := jsonschema.NewCompiler()
+//	c.RegisterVocabulary(contrib.CompareVocab())
+//	c.AssertVocabs()
+package contrib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+)
+
+// urlPrefix is prefix used in url of vocabularies in this package.
+const urlPrefix = "https://github.com/santhosh-tekuri/jsonschema/vocab/"
+
+func mustVocab(name, metaschema string) (url string, sch *jsonschema.Schema) {
+	url = urlPrefix + name
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(metaschema))
+	if err != nil {
+		panic(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, doc); err != nil {
+		panic(err)
+	}
+	return url, c.MustCompile(url)
+}
+
+// lookup returns value at json-pointer ptr in v.
+func lookup(v any, ptr string) (any, bool) {
+	v, err := jsonpointer.Eval(v, ptr)
+	return v, err == nil
+}
+
+// quote returns single-quoted string.
+func quote(s string) string {
+	s = fmt.Sprintf("%q", s)
+	s = strings.ReplaceAll(s, `\"`, `"`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s[1:len(s)-1] + "'"
+}
+
+func joinQuoted(arr []string, sep string) string {
+	var sb strings.Builder
+	for _, s := range arr {
+		if sb.Len() > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(quote(s))
+	}
+	return sb.String()
+}
+
+// to be used only for primitive.
+func display(v any) string {
+	switch v := v.(type) {
+	case string:
+		return quote(v)
+	case []any, map[string]any:
+		return "value"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
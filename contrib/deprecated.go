@@ -0,0 +1,59 @@
+package contrib
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// DeprecatedVocab returns vocabulary which enforces the standard
+// `deprecated` keyword. Values validated against a schema having
+// `"deprecated": true` are rejected:
+//
+//	{
+//		"properties": {
+//			"fax": { "type": "string", "deprecated": true }
+//		}
+//	}
+//
+// This is useful to stop accepting deprecated properties, after
+// clients had time to migrate. Note that `deprecated` remains an
+// annotation, if this vocabulary is not enabled.
+func DeprecatedVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("deprecated", `{
+		"properties": {
+			"deprecated": { "type": "boolean" }
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileDeprecated,
+	}
+}
+
+type deprecated struct{}
+
+func compileDeprecated(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	if b, ok := obj["deprecated"].(bool); ok && b {
+		return deprecated{}, nil
+	}
+	return nil, nil
+}
+
+func (deprecated) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	ctx.AddError(&Deprecated{})
+}
+
+// ErrorKind --
+
+// Deprecated is the ErrorKind reported when value is
+// validated against deprecated schema.
+type Deprecated struct{}
+
+func (*Deprecated) KeywordPath() []string {
+	return []string{"deprecated"}
+}
+
+func (*Deprecated) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("value is deprecated")
+}
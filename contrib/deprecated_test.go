@@ -0,0 +1,24 @@
+package contrib_test
+
+import (
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+func TestDeprecated(t *testing.T) {
+	testVocab(t, contrib.DeprecatedVocab(), `{
+		"properties": {
+			"fax": { "type": "string", "deprecated": true },
+			"phone": { "type": "string", "deprecated": false }
+		}
+	}`, []vocabTest{
+		{`{"phone": "123"}`, true},
+		{`{"fax": "123"}`, false},
+		{`{}`, true},
+	})
+}
+
+func TestDeprecatedInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.DeprecatedVocab(), `{"deprecated": "yes"}`)
+}
@@ -0,0 +1,128 @@
+package contrib
+
+import (
+	"slices"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// DiscriminatorVocab returns vocabulary for the `discriminator`
+// keyword of OpenAPI, which selects the schema to validate an
+// object with, by value of a property:
+//
+//	{
+//		"oneOf": [{ "$ref": "#/$defs/cat" }, { "$ref": "#/$defs/dog" }],
+//		"discriminator": {
+//			"propertyName": "petType",
+//			"mapping": { "cat": "#/$defs/cat", "dog": "#/$defs/dog" }
+//		}
+//	}
+//
+// The mapping values are references, resolved same as `$ref`. The
+// property is required in objects, and its value must be one of the
+// mapping keys. The object is validated against the mapped schema,
+// and its errors are reported. Note that sibling `oneOf` is still
+// evaluated, and reports errors from all its subschemas, along with
+// the errors of the mapped schema. Values which are not objects
+// are ignored.
+func DiscriminatorVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("discriminator", `{
+		"properties": {
+			"discriminator": {
+				"type": "object",
+				"properties": {
+					"propertyName": { "type": "string" },
+					"mapping": {
+						"type": "object",
+						"additionalProperties": { "type": "string", "format": "uri-reference" }
+					}
+				},
+				"required": ["propertyName"]
+			}
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileDiscriminator,
+	}
+}
+
+type discriminator struct {
+	pname   string
+	mapping map[string]*jsonschema.Schema
+}
+
+func compileDiscriminator(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	d, ok := obj["discriminator"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	pname, _ := d["propertyName"].(string)
+	ext := &discriminator{pname: pname}
+	if mapping, ok := d["mapping"].(map[string]any); ok {
+		ext.mapping = map[string]*jsonschema.Schema{}
+		for value, ref := range mapping {
+			ref, _ := ref.(string)
+			sch, err := ctx.EnqueueRef(ref)
+			if err != nil {
+				return nil, err
+			}
+			ext.mapping[value] = sch
+		}
+	}
+	return ext, nil
+}
+
+func (d *discriminator) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	pvalue, ok := obj[d.pname]
+	if !ok {
+		ctx.AddError(&Discriminator{Property: d.pname})
+		return
+	}
+	if d.mapping == nil {
+		return
+	}
+	value, _ := pvalue.(string)
+	sch, ok := d.mapping[value]
+	if !ok {
+		var values []string
+		for value := range d.mapping {
+			values = append(values, value)
+		}
+		slices.Sort(values)
+		ctx.AddError(&Discriminator{Property: d.pname, Value: pvalue, Want: values})
+		return
+	}
+	if err := ctx.Validate(sch, v, nil); err != nil {
+		ctx.AddErr(err)
+	} else {
+		ctx.EvaluatedProp(d.pname)
+	}
+}
+
+// ErrorKind --
+
+// Discriminator is the ErrorKind reported when `discriminator`
+// property is missing, or has value not in mapping.
+type Discriminator struct {
+	Property string   // discriminator property
+	Value    any      // value of property, nil if missing
+	Want     []string // mapping keys, nil if missing
+}
+
+func (*Discriminator) KeywordPath() []string {
+	return []string{"discriminator"}
+}
+
+func (k *Discriminator) LocalizedString(p *message.Printer) string {
+	if k.Want == nil {
+		return p.Sprintf("missing discriminator property %s", quote(k.Property))
+	}
+	return p.Sprintf("discriminator property %s must be one of %s, but got %s", quote(k.Property), joinQuoted(k.Want, ", "), display(k.Value))
+}
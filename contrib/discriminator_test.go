@@ -0,0 +1,118 @@
+package contrib_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+func TestDiscriminator(t *testing.T) {
+	testVocab(t, contrib.DiscriminatorVocab(), `{
+		"$defs": {
+			"cat": {
+				"properties": { "petType": { "const": "cat" }, "lives": { "type": "integer" } },
+				"required": ["lives"]
+			},
+			"dog": {
+				"properties": { "petType": { "const": "dog" }, "bark": { "type": "boolean" } },
+				"required": ["bark"]
+			}
+		},
+		"oneOf": [{ "$ref": "#/$defs/cat" }, { "$ref": "#/$defs/dog" }],
+		"discriminator": {
+			"propertyName": "petType",
+			"mapping": { "cat": "#/$defs/cat", "dog": "#/$defs/dog" }
+		}
+	}`, []vocabTest{
+		{`{"petType": "cat", "lives": 9}`, true},
+		{`{"petType": "dog", "bark": true}`, true},
+		{`{"petType": "dog", "lives": 9}`, false},
+		{`{"petType": "cow"}`, false},
+		{`{"petType": 1}`, false},
+		{`{"lives": 9}`, false},
+		{`"not an object"`, false},
+	})
+	testVocab(t, contrib.DiscriminatorVocab(), `{
+		"discriminator": { "propertyName": "kind" }
+	}`, []vocabTest{
+		{`{"kind": "any"}`, true},
+		{`{}`, false},
+		{`[]`, true},
+	})
+}
+
+func TestDiscriminatorErrors(t *testing.T) {
+	defs := `"$defs": {
+		"cat": {
+			"properties": { "petType": { "const": "cat" } },
+			"required": ["lives"]
+		},
+		"dog": {
+			"properties": { "petType": { "const": "dog" } },
+			"required": ["bark"]
+		}
+	}`
+	discriminator := `"discriminator": {
+		"propertyName": "petType",
+		"mapping": { "cat": "#/$defs/cat", "dog": "#/$defs/dog" }
+	}`
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			"withoutOneOf",
+			`{` + defs + `, ` + discriminator + `}`,
+			[]string{"/$defs/dog/required"},
+		},
+		{
+			// oneOf reports errors from all subschemas
+			"withOneOf",
+			`{` + defs + `, ` + discriminator + `, "oneOf": [{ "$ref": "#/$defs/cat" }, { "$ref": "#/$defs/dog" }]}`,
+			[]string{
+				"/$defs/dog/required",
+				"/oneOf",
+				"/oneOf/0/$ref/properties/petType/const",
+				"/oneOf/1/$ref/required",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := jsonschema.NewCompiler()
+			c.RegisterVocabulary(contrib.DiscriminatorVocab())
+			c.AssertVocabs()
+			sch, err := c.CompileValue(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sch.Validate(map[string]any{"petType": "dog", "lives": 9})
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("want ValidationError, got %v", err)
+			}
+			var got []string
+			for _, unit := range verr.BasicOutput().Errors {
+				got = append(got, unit.KeywordLocation)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, test.want) {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscriminatorInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.DiscriminatorVocab(), `{"discriminator": "kind"}`)
+	testInvalidSchema(t, contrib.DiscriminatorVocab(), `{"discriminator": {"mapping": {}}}`)
+	testInvalidSchema(t, contrib.DiscriminatorVocab(), `{"discriminator": {"propertyName": "kind", "mapping": {"a": "#/$defs/missing"}}}`)
+}
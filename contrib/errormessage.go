@@ -0,0 +1,179 @@
+package contrib
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/message"
+)
+
+// ErrorMessages provides vocabulary for the `errorMessage` keyword
+// of ajv-errors, which replaces errors of a schema with custom
+// messages. If its value is string, all errors of the schema and
+// its subschemas are replaced by single error with that message:
+//
+//	{ "type": "string", "minLength": 3, "errorMessage": "must be at least 3 characters" }
+//
+// If its value is object, errors of each keyword listed are
+// replaced by the given message:
+//
+//	{
+//		"type": "object",
+//		"required": ["name"],
+//		"errorMessage": { "type": "must be an object", "required": "name is required" }
+//	}
+//
+// Since a keyword cannot modify errors of other keywords, the messages
+// are applied to validation errors using [ErrorMessages.Apply].
+//
+// The zero value is ready to use. Use separate ErrorMessages for
+// each [jsonschema.Compiler].
+type ErrorMessages struct {
+	mu   sync.RWMutex
+	exts map[string]*errorMessage // key is schema location
+}
+
+// Vocab returns vocabulary which introduces `errorMessage` keyword.
+// The messages of schemas compiled with it are recorded in em.
+func (em *ErrorMessages) Vocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("error-message", `{
+		"properties": {
+			"errorMessage": {
+				"oneOf": [
+					{ "type": "string" },
+					{
+						"type": "object",
+						"additionalProperties": { "type": "string" },
+						"minProperties": 1
+					}
+				]
+			}
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:    url,
+		Schema: sch,
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			return em.compile(ctx, obj)
+		},
+	}
+}
+
+type errorMessage struct {
+	all      *string           // if errorMessage is string
+	keywords map[string]string // if errorMessage is object
+}
+
+func (em *ErrorMessages) compile(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	ext := &errorMessage{}
+	switch v := obj["errorMessage"].(type) {
+	case string:
+		ext.all = &v
+	case map[string]any:
+		ext.keywords = map[string]string{}
+		for kw, msg := range v {
+			if msg, ok := msg.(string); ok {
+				ext.keywords[kw] = msg
+			}
+		}
+	default:
+		return nil, nil
+	}
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	if em.exts == nil {
+		em.exts = map[string]*errorMessage{}
+	}
+	em.exts[ctx.Location()] = ext
+	return ext, nil
+}
+
+func (*errorMessage) Validate(ctx *jsonschema.ValidatorContext, v any) {}
+
+// Apply returns err with errors replaced by custom messages of the
+// schemas that reported them. If err is not [*jsonschema.ValidationError],
+// it is returned as is. The err is not modified.
+func (em *ErrorMessages) Apply(err error) error {
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return em.apply(verr)
+}
+
+func (em *ErrorMessages) apply(e *jsonschema.ValidationError) *jsonschema.ValidationError {
+	if ext := em.exts[e.SchemaURL]; ext != nil {
+		switch e.ErrorKind.(type) {
+		case *kind.Schema:
+			if ext.all != nil {
+				// keep top-level error
+				c := *e
+				c.Causes = []*jsonschema.ValidationError{withMessage(e, "", *ext.all)}
+				return &c
+			}
+		case *kind.Group:
+			if ext.all != nil {
+				return withMessage(e, "", *ext.all)
+			}
+		default:
+			if ext.all != nil {
+				return withMessage(e, "", *ext.all)
+			}
+			if kw := e.ErrorKind.KeywordPath(); len(kw) > 0 {
+				if msg, ok := ext.keywords[kw[0]]; ok {
+					return withMessage(e, kw[0], msg)
+				}
+			}
+		}
+	}
+	if len(e.Causes) == 0 {
+		return e
+	}
+	c := *e
+	c.Causes = make([]*jsonschema.ValidationError, 0, len(e.Causes))
+	replaced := map[string]bool{} // schema and instance locations replaced by single message
+	for _, cause := range e.Causes {
+		cause = em.apply(cause)
+		if k, ok := cause.ErrorKind.(*ErrorMessage); ok && k.Keyword == "" {
+			// sibling keyword errors of same schema are not grouped
+			key := cause.SchemaURL + "\x00" + strings.Join(cause.InstanceLocation, "\x00")
+			if replaced[key] {
+				continue
+			}
+			replaced[key] = true
+		}
+		c.Causes = append(c.Causes, cause)
+	}
+	return &c
+}
+
+func withMessage(e *jsonschema.ValidationError, keyword, msg string) *jsonschema.ValidationError {
+	c := *e
+	c.ErrorKind = &ErrorMessage{Keyword: keyword, Message: msg}
+	c.Causes = nil
+	return &c
+}
+
+// ErrorKind --
+
+// ErrorMessage is the ErrorKind which replaces errors
+// by [ErrorMessages.Apply].
+type ErrorMessage struct {
+	Keyword string // keyword whose errors are replaced, empty if all
+	Message string // custom message
+}
+
+func (k *ErrorMessage) KeywordPath() []string {
+	if k.Keyword == "" {
+		return []string{"errorMessage"}
+	}
+	return []string{"errorMessage", k.Keyword}
+}
+
+func (k *ErrorMessage) LocalizedString(p *message.Printer) string {
+	return k.Message
+}
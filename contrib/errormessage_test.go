@@ -0,0 +1,97 @@
+package contrib_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func TestErrorMessages(t *testing.T) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {
+				"type": "string",
+				"minLength": 3,
+				"pattern": "^[a-z]+$",
+				"errorMessage": "name must be at least 3 lowercase letters"
+			},
+			"age": { "type": "integer", "minimum": 0 },
+			"tags": {
+				"items": { "type": "string", "maxLength": 2, "errorMessage": "invalid tag" }
+			}
+		},
+		"errorMessage": { "type": "must be an object", "required": "name is required" }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	em := &contrib.ErrorMessages{}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(em.Vocab())
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := message.NewPrinter(language.English)
+
+	tests := []struct {
+		data string
+		want []string // messages of leaf errors
+	}{
+		{`[]`, []string{"must be an object"}},
+		{`{}`, []string{"name is required"}},
+		{`{"name": "AB"}`, []string{"name must be at least 3 lowercase letters"}},
+		{`{"name": "abc", "age": -1}`, []string{"minimum: got -1, want 0"}},
+		{`{"name": "abc", "tags": ["abc", 1]}`, []string{"invalid tag", "invalid tag"}},
+	}
+	for _, test := range tests {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sch.Validate(inst)
+		if err == nil {
+			t.Fatalf("%s: want error", test.data)
+		}
+		verr := em.Apply(err).(*jsonschema.ValidationError)
+		var got []string
+		var leaves func(e *jsonschema.ValidationError)
+		leaves = func(e *jsonschema.ValidationError) {
+			if len(e.Causes) == 0 {
+				got = append(got, e.ErrorKind.LocalizedString(p))
+			}
+			for _, c := range e.Causes {
+				leaves(c)
+			}
+		}
+		leaves(verr)
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("%s:\n got %q\nwant %q", test.data, got, test.want)
+		}
+	}
+
+	// original error is not modified
+	err = sch.Validate(map[string]any{})
+	want := err.Error()
+	em.Apply(err)
+	if got := err.Error(); got != want {
+		t.Errorf("original error modified:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestErrorMessagesInvalidSchema(t *testing.T) {
+	em := &contrib.ErrorMessages{}
+	testInvalidSchema(t, em.Vocab(), `{"errorMessage": 1}`)
+	testInvalidSchema(t, em.Vocab(), `{"errorMessage": {}}`)
+	testInvalidSchema(t, em.Vocab(), `{"errorMessage": {"type": 1}}`)
+}
@@ -0,0 +1,50 @@
+package contrib
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// NullableVocab returns vocabulary for the `x-nullable` keyword,
+// used by Swagger 2.0 tools and code generators to mark values
+// which may be null:
+//
+//	{ "type": "string", "x-nullable": true }
+//
+// The keyword does not affect validation, since a keyword cannot
+// suppress errors of other keywords. Use `"type": ["string", "null"]`
+// for null to be valid. Use [IsNullable] to get it from compiled
+// schema.
+func NullableVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("nullable", `{
+		"properties": {
+			"x-nullable": { "type": "boolean" }
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileNullable,
+	}
+}
+
+type nullable struct{}
+
+func compileNullable(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	if b, ok := obj["x-nullable"].(bool); ok && b {
+		return nullable{}, nil
+	}
+	return nil, nil
+}
+
+func (nullable) Validate(ctx *jsonschema.ValidatorContext, v any) {}
+
+// IsNullable tells whether sch has `"x-nullable": true`.
+// It requires [NullableVocab] to be registered and enabled.
+func IsNullable(sch *jsonschema.Schema) bool {
+	for _, ext := range sch.Extensions {
+		if _, ok := ext.(nullable); ok {
+			return true
+		}
+	}
+	return false
+}
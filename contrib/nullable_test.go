@@ -0,0 +1,44 @@
+package contrib_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+func TestNullable(t *testing.T) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"a": { "type": "string", "x-nullable": true },
+			"b": { "type": "string", "x-nullable": false }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(contrib.NullableVocab())
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contrib.IsNullable(sch) {
+		t.Error("root: want not nullable")
+	}
+	if !contrib.IsNullable(sch.Properties["a"]) {
+		t.Error("a: want nullable")
+	}
+	if contrib.IsNullable(sch.Properties["b"]) {
+		t.Error("b: want not nullable")
+	}
+}
+
+func TestNullableInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.NullableVocab(), `{"x-nullable": "yes"}`)
+}
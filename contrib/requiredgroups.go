@@ -0,0 +1,164 @@
+package contrib
+
+import (
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// RequiredGroupsVocab returns vocabulary for exclusive property groups.
+//
+// It introduces keyword `x-oneOfRequired`, which requires exactly one
+// of the given property sets to be present. Each set is either a
+// property name or array of property names:
+//
+//	{ "x-oneOfRequired": ["email", ["phone", "countryCode"]] }
+//
+// It also introduces keyword `x-anyOfRequired`, which requires at least
+// one of the given property sets to be present:
+//
+//	{ "x-anyOfRequired": ["id", ["name", "dob"]] }
+//
+// A property set is present, if all its properties are present.
+// Unlike `oneOf` with `required` combinations, these keywords report
+// single error listing the property sets. Values which are not
+// objects are ignored.
+func RequiredGroupsVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("required-groups", `{
+		"$defs": {
+			"groups": {
+				"type": "array",
+				"items": {
+					"oneOf": [
+						{ "type": "string" },
+						{
+							"type": "array",
+							"items": { "type": "string" },
+							"minItems": 1,
+							"uniqueItems": true
+						}
+					]
+				},
+				"minItems": 1
+			}
+		},
+		"properties": {
+			"x-oneOfRequired": { "$ref": "#/$defs/groups" },
+			"x-anyOfRequired": { "$ref": "#/$defs/groups" }
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileRequiredGroups,
+	}
+}
+
+type requiredGroups struct {
+	oneOf [][]string
+	anyOf [][]string
+}
+
+func compileRequiredGroups(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	groups := func(kw string) [][]string {
+		var groups [][]string
+		arr, _ := obj[kw].([]any)
+		for _, item := range arr {
+			var group []string
+			for _, item := range oneOrMany(item) {
+				if s, ok := item.(string); ok {
+					group = append(group, s)
+				}
+			}
+			groups = append(groups, group)
+		}
+		return groups
+	}
+	ext := requiredGroups{
+		oneOf: groups("x-oneOfRequired"),
+		anyOf: groups("x-anyOfRequired"),
+	}
+	if len(ext.oneOf) == 0 && len(ext.anyOf) == 0 {
+		return nil, nil
+	}
+	return &ext, nil
+}
+
+func (s *requiredGroups) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	present := func(groups [][]string) []int {
+		var matched []int
+	loop:
+		for i, group := range groups {
+			for _, pname := range group {
+				if _, ok := obj[pname]; !ok {
+					continue loop
+				}
+			}
+			matched = append(matched, i)
+		}
+		return matched
+	}
+	if s.oneOf != nil {
+		if matched := present(s.oneOf); len(matched) != 1 {
+			ctx.AddError(&OneOfRequired{Groups: s.oneOf, Present: matched})
+		}
+	}
+	if s.anyOf != nil {
+		if matched := present(s.anyOf); len(matched) == 0 {
+			ctx.AddError(&AnyOfRequired{Groups: s.anyOf})
+		}
+	}
+}
+
+func joinGroups(groups [][]string) string {
+	var arr []string
+	for _, group := range groups {
+		if len(group) == 1 {
+			arr = append(arr, quote(group[0]))
+		} else {
+			arr = append(arr, "("+joinQuoted(group, ", ")+")")
+		}
+	}
+	return strings.Join(arr, ", ")
+}
+
+// ErrorKind --
+
+// OneOfRequired is the ErrorKind reported when `x-oneOfRequired` fails.
+type OneOfRequired struct {
+	Groups  [][]string // property sets
+	Present []int      // indexes of property sets present
+}
+
+func (*OneOfRequired) KeywordPath() []string {
+	return []string{"x-oneOfRequired"}
+}
+
+func (k *OneOfRequired) LocalizedString(p *message.Printer) string {
+	if len(k.Present) == 0 {
+		return p.Sprintf("exactly one of %s required, but none found", joinGroups(k.Groups))
+	}
+	var present [][]string
+	for _, i := range k.Present {
+		present = append(present, k.Groups[i])
+	}
+	return p.Sprintf("exactly one of %s required, but found %s", joinGroups(k.Groups), joinGroups(present))
+}
+
+// AnyOfRequired is the ErrorKind reported when `x-anyOfRequired` fails.
+type AnyOfRequired struct {
+	Groups [][]string // property sets
+}
+
+func (*AnyOfRequired) KeywordPath() []string {
+	return []string{"x-anyOfRequired"}
+}
+
+func (k *AnyOfRequired) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("at least one of %s required", joinGroups(k.Groups))
+}
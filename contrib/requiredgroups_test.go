@@ -0,0 +1,35 @@
+package contrib_test
+
+import (
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+func TestOneOfRequired(t *testing.T) {
+	testVocab(t, contrib.RequiredGroupsVocab(), `{"x-oneOfRequired": ["email", ["phone", "countryCode"]]}`, []vocabTest{
+		{`{"email": "a@b.com"}`, true},
+		{`{"phone": "123", "countryCode": "1"}`, true},
+		{`{"phone": "123"}`, false},
+		{`{}`, false},
+		{`{"email": "a@b.com", "phone": "123", "countryCode": "1"}`, false},
+		{`{"email": "a@b.com", "phone": "123"}`, true},
+		{`"not an object"`, true},
+	})
+}
+
+func TestAnyOfRequired(t *testing.T) {
+	testVocab(t, contrib.RequiredGroupsVocab(), `{"x-anyOfRequired": ["id", ["name", "dob"]]}`, []vocabTest{
+		{`{"id": 1}`, true},
+		{`{"name": "x", "dob": "2000-01-01"}`, true},
+		{`{"id": 1, "name": "x", "dob": "2000-01-01"}`, true},
+		{`{"name": "x"}`, false},
+	})
+}
+
+func TestRequiredGroupsInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.RequiredGroupsVocab(), `{"x-oneOfRequired": "email"}`)
+	testInvalidSchema(t, contrib.RequiredGroupsVocab(), `{"x-oneOfRequired": []}`)
+	testInvalidSchema(t, contrib.RequiredGroupsVocab(), `{"x-anyOfRequired": [[]]}`)
+	testInvalidSchema(t, contrib.RequiredGroupsVocab(), `{"x-anyOfRequired": [1]}`)
+}
@@ -0,0 +1,109 @@
+package contrib
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// UniqueKeysVocab returns vocabulary which introduces keyword
+// `x-uniqueKeys`. It requires objects in array to be unique
+// by given key:
+//
+//	{ "x-uniqueKeys": "id" }
+//
+// Key can be composite of multiple properties:
+//
+//	{ "x-uniqueKeys": ["id", "region"] }
+//
+// Items which are not objects, or that do not have all the
+// key properties are ignored.
+func UniqueKeysVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("unique-keys", `{
+		"properties": {
+			"x-uniqueKeys": {
+				"oneOf": [
+					{ "type": "string" },
+					{
+						"type": "array",
+						"items": { "type": "string" },
+						"minItems": 1,
+						"uniqueItems": true
+					}
+				]
+			}
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileUniqueKeys,
+	}
+}
+
+type uniqueKeys struct {
+	keys []string
+}
+
+func compileUniqueKeys(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	var keys []string
+	for _, item := range oneOrMany(obj["x-uniqueKeys"]) {
+		if s, ok := item.(string); ok {
+			keys = append(keys, s)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return &uniqueKeys{keys}, nil
+}
+
+func (s *uniqueKeys) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	arr, ok := v.([]any)
+	if !ok {
+		return
+	}
+	var keys []any
+	var indexes []int
+loop:
+	for i, item := range arr {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		key := make([]any, len(s.keys))
+		for j, pname := range s.keys {
+			pvalue, ok := obj[pname]
+			if !ok {
+				continue loop
+			}
+			key[j] = pvalue
+		}
+		keys = append(keys, key)
+		indexes = append(indexes, i)
+	}
+
+	i, j, err := ctx.Duplicates(keys)
+	if err != nil {
+		ctx.AddErr(err)
+		return
+	}
+	if i != -1 {
+		ctx.AddError(&UniqueKeys{Keys: s.keys, Duplicates: [2]int{indexes[i], indexes[j]}})
+	}
+}
+
+// ErrorKind --
+
+// UniqueKeys is the ErrorKind reported when `x-uniqueKeys` fails.
+type UniqueKeys struct {
+	Keys       []string // key properties
+	Duplicates [2]int   // indexes of items with same key
+}
+
+func (*UniqueKeys) KeywordPath() []string {
+	return []string{"x-uniqueKeys"}
+}
+
+func (k *UniqueKeys) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("items at %d and %d have same %s", k.Duplicates[0], k.Duplicates[1], joinQuoted(k.Keys, ", "))
+}
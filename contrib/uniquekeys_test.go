@@ -0,0 +1,26 @@
+package contrib_test
+
+import (
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+func TestUniqueKeys(t *testing.T) {
+	testVocab(t, contrib.UniqueKeysVocab(), `{"x-uniqueKeys": "id"}`, []vocabTest{
+		{`[{"id": 1}, {"id": 2}]`, true},
+		{`[{"id": 1}, {"id": 2}, {"id": 1.0}]`, false},
+		{`[{"id": 1}, {"name": "x"}, {"name": "x"}]`, true},
+		{`"not an array"`, true},
+	})
+	testVocab(t, contrib.UniqueKeysVocab(), `{"x-uniqueKeys": ["id", "region"]}`, []vocabTest{
+		{`[{"id": 1, "region": "us"}, {"id": 1, "region": "eu"}]`, true},
+		{`[{"id": 1, "region": "us"}, {"id": 2, "region": "us"}, {"id": 1, "region": "us"}]`, false},
+		{`[{"id": 1}, {"id": 1}]`, true},
+	})
+}
+
+func TestUniqueKeysInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.UniqueKeysVocab(), `{"x-uniqueKeys": 1}`)
+	testInvalidSchema(t, contrib.UniqueKeysVocab(), `{"x-uniqueKeys": []}`)
+}
@@ -0,0 +1,84 @@
+package contrib
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// XMLVocab returns vocabulary for the `xml` keyword of OpenAPI,
+// which describes how a value is represented in XML:
+//
+//	{
+//		"type": "array",
+//		"xml": { "name": "pets", "wrapped": true }
+//	}
+//
+// The keyword does not affect validation. Use [XMLOf] to
+// get it from compiled schema.
+func XMLVocab() *jsonschema.Vocabulary {
+	url, sch := mustVocab("xml", `{
+		"properties": {
+			"xml": {
+				"type": "object",
+				"properties": {
+					"name": { "type": "string" },
+					"namespace": { "type": "string", "format": "uri" },
+					"prefix": { "type": "string" },
+					"attribute": { "type": "boolean" },
+					"wrapped": { "type": "boolean" }
+				}
+			}
+		}
+	}`)
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  sch,
+		Compile: compileXML,
+	}
+}
+
+// XML is the value of `xml` keyword.
+type XML struct {
+	// Name replaces the name of element or attribute.
+	Name string
+
+	// Namespace is the absolute uri of namespace.
+	Namespace string
+
+	// Prefix used for Name.
+	Prefix string
+
+	// Attribute tells whether property is an attribute,
+	// instead of element.
+	Attribute bool
+
+	// Wrapped tells whether array items are wrapped in
+	// an element. Used only for arrays.
+	Wrapped bool
+}
+
+func compileXML(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	v, ok := obj["xml"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	x := &XML{}
+	x.Name, _ = v["name"].(string)
+	x.Namespace, _ = v["namespace"].(string)
+	x.Prefix, _ = v["prefix"].(string)
+	x.Attribute, _ = v["attribute"].(bool)
+	x.Wrapped, _ = v["wrapped"].(bool)
+	return x, nil
+}
+
+func (*XML) Validate(ctx *jsonschema.ValidatorContext, v any) {}
+
+// XMLOf returns the `xml` keyword of sch, nil if absent.
+// It requires [XMLVocab] to be registered and enabled.
+func XMLOf(sch *jsonschema.Schema) *XML {
+	for _, ext := range sch.Extensions {
+		if x, ok := ext.(*XML); ok {
+			return x
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package contrib_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/contrib"
+)
+
+func TestXML(t *testing.T) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": "object",
+		"properties": {
+			"id": { "type": "integer", "xml": { "attribute": true } },
+			"tags": {
+				"type": "array",
+				"items": { "type": "string", "xml": { "name": "tag" } },
+				"xml": { "name": "tags", "prefix": "t", "namespace": "https://example.com/tags", "wrapped": true }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(contrib.XMLVocab())
+	c.AssertVocabs()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if x := contrib.XMLOf(sch); x != nil {
+		t.Errorf("root: got %+v, want nil", x)
+	}
+	if x := contrib.XMLOf(sch.Properties["id"]); x == nil || !x.Attribute {
+		t.Errorf("id: got %+v", x)
+	}
+	want := contrib.XML{Name: "tags", Prefix: "t", Namespace: "https://example.com/tags", Wrapped: true}
+	if x := contrib.XMLOf(sch.Properties["tags"]); x == nil || *x != want {
+		t.Errorf("tags: got %+v, want %+v", x, want)
+	}
+	if x := contrib.XMLOf(sch.Properties["tags"].Items2020); x == nil || x.Name != "tag" {
+		t.Errorf("tags/items: got %+v", x)
+	}
+}
+
+func TestXMLInvalidSchema(t *testing.T) {
+	testInvalidSchema(t, contrib.XMLVocab(), `{"xml": "name"}`)
+	testInvalidSchema(t, contrib.XMLVocab(), `{"xml": {"wrapped": "yes"}}`)
+}
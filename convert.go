@@ -0,0 +1,124 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Convert returns copy of instance v, in which the values are converted
+// to go values, using `type` and `format` of the schema:
+//   - number with type integer, or format int32, int64: int64, or
+//     uint64 if it does not fit in int64. Values that do not fit in
+//     both, are not converted
+//   - other numbers with type number: float64
+//   - string with format date-time: time.Time
+//   - string with format date: time.Time in UTC
+//
+// This is meant to be used after successful validation, for handing
+// data to application code. Values that cannot be converted are
+// left as is. The schemas applied to a value are found as in
+// [Schema.RemoveAdditional].
+//
+// The v is not modified.
+func (sch *Schema) Convert(v any) any {
+	return convert([]*Schema{sch}, v)
+}
+
+func convert(schemas []*Schema, v any) any {
+	var applied []*Schema
+	for _, sch := range schemas {
+		applied = appliedSchemas(sch, v, applied)
+	}
+
+	var types Types
+	var format string
+	for _, sch := range applied {
+		if sch.Types != nil {
+			types.add(jsonType(*sch.Types))
+		}
+		if obj, ok := sch.doc.(map[string]any); ok {
+			if f, ok := obj["format"].(string); ok {
+				format = f
+			}
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for pname, pvalue := range v {
+			obj[pname] = convert(propSchemas(applied, pname), pvalue)
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i] = convert(itemSchemas(applied, i), item)
+		}
+		return arr
+	case string:
+		switch format {
+		case "date-time":
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		case "date":
+			if t, err := time.Parse(time.DateOnly, v); err == nil {
+				return t
+			}
+		}
+		return v
+	default:
+		if typeOf(v) != numberType {
+			return v
+		}
+		integer := format == "int32" || format == "int64" ||
+			(types.contains(integerType) && !types.contains(numberType))
+		if integer {
+			if i, ok := toInt(v); ok {
+				return i
+			}
+			return v
+		}
+		if types.contains(numberType) {
+			if f, ok := toFloat(v); ok {
+				return f
+			}
+		}
+		return v
+	}
+}
+
+// toInt converts number to int64 or uint64.
+func toInt(num any) (any, bool) {
+	switch num := num.(type) {
+	case int64:
+		return num, true
+	case uint64:
+		return num, true
+	}
+	rat, ok := new(big.Rat).SetString(fmt.Sprint(num))
+	if !ok || !rat.IsInt() {
+		return nil, false
+	}
+	i := rat.Num()
+	if i.IsInt64() {
+		return i.Int64(), true
+	}
+	if i.IsUint64() {
+		return i.Uint64(), true
+	}
+	return nil, false
+}
+
+// toFloat converts number to float64.
+func toFloat(num any) (float64, bool) {
+	rat, ok := new(big.Rat).SetString(fmt.Sprint(num))
+	if !ok {
+		return 0, false
+	}
+	f, _ := rat.Float64()
+	return f, !math.IsInf(f, 0)
+}
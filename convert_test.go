@@ -0,0 +1,72 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestConvert(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"id": { "type": "integer" },
+			"big": { "type": "integer" },
+			"huge": { "type": "integer" },
+			"count": { "type": "number", "format": "int32" },
+			"price": { "type": "number" },
+			"created": { "type": "string", "format": "date-time" },
+			"birthday": { "$ref": "#/$defs/date" },
+			"name": { "type": "string" },
+			"any": true,
+			"scores": { "items": { "type": "integer" } }
+		},
+		"$defs": { "date": { "type": "string", "format": "date" } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"id": 9007199254740993,
+		"big": 18446744073709551615,
+		"huge": 1e30,
+		"count": 3.0,
+		"price": 1.5,
+		"created": "2024-01-02T03:04:05.5Z",
+		"birthday": "2000-02-29",
+		"name": "x",
+		"any": 1,
+		"scores": [1, 2]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"id":       int64(9007199254740993),
+		"big":      uint64(18446744073709551615),
+		"huge":     json.Number("1e30"),
+		"count":    int64(3),
+		"price":    1.5,
+		"created":  time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC),
+		"birthday": time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC),
+		"name":     "x",
+		"any":      json.Number("1"),
+		"scores":   []any{int64(1), int64(2)},
+	}
+	got := sch.Convert(inst)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,206 @@
+package jsonschema
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+// keywords whose value can be specified using $data reference.
+var dataKeywords = []string{
+	"const", "enum", "format",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"minLength", "maxLength", "pattern",
+	"minItems", "maxItems", "uniqueItems",
+	"minProperties", "maxProperties", "required",
+}
+
+// dataRef is keyword whose value is given by $data reference.
+type dataRef struct {
+	keyword string
+	ptr     string // relative json-pointer
+}
+
+type dataRefs struct {
+	refs          []dataRef
+	regexpEngine  RegexpEngine
+	formats       map[string]*Format // custom formats
+	assertFormat  bool
+	assertFormats map[string]bool // formats asserted, if assertFormat is false
+}
+
+// isDataRef checks whether v is of form {"$data": "relative-json-pointer"}.
+func isDataRef(v any) (string, bool) {
+	obj, ok := v.(map[string]any)
+	if !ok || len(obj) != 1 {
+		return "", false
+	}
+	ptr, ok := obj["$data"].(string)
+	return ptr, ok
+}
+
+// collectDataRefs removes keywords with $data reference from obj.
+// obj is cloned if any such keywords are found.
+func collectDataRefs(obj map[string]any) (map[string]any, []dataRef) {
+	var refs []dataRef
+	for _, kw := range dataKeywords {
+		if ptr, ok := isDataRef(obj[kw]); ok {
+			refs = append(refs, dataRef{kw, ptr})
+		}
+	}
+	if len(refs) == 0 {
+		return obj, nil
+	}
+	clone := make(map[string]any, len(obj))
+	for k, v := range obj {
+		clone[k] = v
+	}
+	for _, ref := range refs {
+		delete(clone, ref.keyword)
+	}
+	return clone, refs
+}
+
+// stripDataRefs returns copy of schema document v with all
+// keywords with $data reference removed. This is used for
+// validating schema against metaschema.
+func stripDataRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		obj, _ := collectDataRefs(v)
+		clone := make(map[string]any, len(obj))
+		for k, v := range obj {
+			clone[k] = stripDataRefs(v)
+		}
+		return clone
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i] = stripDataRefs(item)
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+// --
+
+// lookupRelative evaluates relative json-pointer ptr
+// in doc starting from location loc.
+//
+// returns false if ptr does not resolve to a value.
+func lookupRelative(doc any, loc []string, ptr string) (any, bool) {
+	v, err := jsonpointer.EvalRelative(doc, loc, ptr)
+	return v, err == nil
+}
+
+// --
+
+func (vd *validator) dataValidate() {
+	s := vd.sch
+	obj := map[string]any{}
+	for _, ref := range s.dataRefs.refs {
+		if v, ok := lookupRelative(vd.root, vd.vloc, ref.ptr); ok {
+			obj[ref.keyword] = v
+		}
+	}
+	if len(obj) == 0 {
+		return
+	}
+
+	c := &objCompiler{obj: obj}
+	ds := &Schema{
+		up:           s.up,
+		resource:     s.resource,
+		dialect:      s.dialect,
+		lengthUnit:   s.lengthUnit,
+		DraftVersion: s.DraftVersion,
+		Location:     s.Location,
+	}
+	for _, ref := range s.dataRefs.refs {
+		v, ok := obj[ref.keyword]
+		if !ok {
+			continue
+		}
+		switch kw := ref.keyword; kw {
+		case "const":
+			ds.Const = &v
+		case "enum":
+			if arr := c.arrVal(kw); arr != nil {
+				ds.Enum = newEnum(arr)
+			} else {
+				ok = false
+			}
+		case "format":
+			if name := c.strVal(kw); name == nil {
+				ok = false
+			} else if s.dataRefs.assertFormat || s.dataRefs.assertFormats[*name] {
+				if *name == "regex" {
+					ds.Format = &Format{Name: "regex", Validate: s.dataRefs.regexpEngine.validate}
+				} else if f := s.dataRefs.formats[*name]; f != nil {
+					ds.Format = f
+				} else {
+					ds.Format = formats[*name]
+				}
+			}
+		case "minimum":
+			ds.Minimum = c.numVal(kw)
+			ok = ds.Minimum != nil
+		case "maximum":
+			ds.Maximum = c.numVal(kw)
+			ok = ds.Maximum != nil
+		case "exclusiveMinimum":
+			ds.ExclusiveMinimum = c.numVal(kw)
+			ok = ds.ExclusiveMinimum != nil
+		case "exclusiveMaximum":
+			ds.ExclusiveMaximum = c.numVal(kw)
+			ok = ds.ExclusiveMaximum != nil
+		case "multipleOf":
+			ds.MultipleOf = c.numVal(kw)
+			ok = ds.MultipleOf != nil && ds.MultipleOf.Sign() > 0
+		case "minLength":
+			ds.MinLength = c.intVal(kw)
+			ok = ds.MinLength != nil
+		case "maxLength":
+			ds.MaxLength = c.intVal(kw)
+			ok = ds.MaxLength != nil
+		case "pattern":
+			if pat := c.strVal(kw); pat != nil {
+				re, err := s.dataRefs.regexpEngine(*pat)
+				if ok = err == nil; ok {
+					ds.Pattern = re
+				}
+			} else {
+				ok = false
+			}
+		case "minItems":
+			ds.MinItems = c.intVal(kw)
+			ok = ds.MinItems != nil
+		case "maxItems":
+			ds.MaxItems = c.intVal(kw)
+			ok = ds.MaxItems != nil
+		case "uniqueItems":
+			b := c.boolVal(kw)
+			ds.UniqueItems, ok = b != nil && *b, b != nil
+		case "minProperties":
+			ds.MinProperties = c.intVal(kw)
+			ok = ds.MinProperties != nil
+		case "maxProperties":
+			ds.MaxProperties = c.intVal(kw)
+			ok = ds.MaxProperties != nil
+		case "required":
+			if arr := c.arrVal(kw); arr != nil {
+				ds.Required = toStrings(arr)
+			} else {
+				ok = false
+			}
+		}
+		if !ok {
+			vd.addError(&kind.DataRef{Keyword: ref.keyword, Ref: ref.ptr, Got: v})
+		}
+	}
+	if vd.boolResult && len(vd.errors) > 0 {
+		return
+	}
+	vd.addErr(vd.validateSelf(ds, "", false))
+}
@@ -0,0 +1,74 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestDataRef(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": "object",
+		"properties": {
+			"min": { "type": "number" },
+			"max": {
+				"type": "number",
+				"minimum": { "$data": "1/min" }
+			},
+			"password": { "type": "string" },
+			"confirm": {
+				"const": { "$data": "1/password" }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.EnableDataRef()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		data  string
+		valid bool
+	}{
+		{`{"min": 1, "max": 2}`, true},
+		{`{"min": 3, "max": 2}`, false},
+		{`{"max": 2}`, true},              // $data does not resolve
+		{`{"min": "x", "max": 2}`, false}, // $data resolves to invalid value
+		{`{"password": "a", "confirm": "a"}`, true},
+		{`{"password": "a", "confirm": "b"}`, false},
+	}
+	for _, test := range tests {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sch.Validate(inst)
+		if got := err == nil; got != test.valid {
+			t.Errorf("%s: valid got %v, want %v: %v", test.data, got, test.valid, err)
+		}
+	}
+}
+
+func TestDataRefDisabled(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{"minimum": { "$data": "1/min" }}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("schema.json"); err == nil {
+		t.Fatal("want compilation to fail")
+	}
+}
@@ -23,6 +23,12 @@ func (d *Draft) String() string {
 	return d.url
 }
 
+// Version returns the draft version.
+// It is one of 4, 6, 7, 2019, 2020.
+func (d *Draft) Version() int {
+	return d.version
+}
+
 var (
 	Draft4 = &Draft{
 		version: 4,
@@ -130,11 +136,33 @@ func init() {
 	for _, d := range []*Draft{Draft4, Draft6, Draft7, Draft2019, Draft2020} {
 		d.sch = c.MustCompile(d.url)
 		for name := range d.allVocabs {
-			d.allVocabs[name] = c.MustCompile(strings.TrimSuffix(d.url, "schema") + "meta/" + name)
+			d.allVocabs[name] = c.MustCompile(d.vocabMetaURL(name))
 		}
 	}
 }
 
+// vocabMetaURL returns url of metaschema of vocabulary
+// with given name.
+func (d *Draft) vocabMetaURL(name string) string {
+	return strings.TrimSuffix(d.url, "schema") + "meta/" + name
+}
+
+func draftFromVersion(version int) *Draft {
+	switch version {
+	case 4:
+		return Draft4
+	case 6:
+		return Draft6
+	case 7:
+		return Draft7
+	case 2019:
+		return Draft2019
+	case 2020:
+		return Draft2020
+	}
+	return draftLatest
+}
+
 func draftFromURL(url string) *Draft {
 	u, frag := split(url)
 	if frag != "" {
@@ -195,21 +223,28 @@ func (d *Draft) getVocabs(url url, doc any, vocabularies map[string]*Vocabulary)
 		return nil, nil
 	}
 
+	// optional vocabularies i.e. declared with value false, are
+	// ignored. except format-assertion, whose declaration enables
+	// format assertions, even if optional.
 	var vocabs []string
 	for vocab, reqd := range obj {
-		if reqd, ok := reqd.(bool); !ok || !reqd {
+		reqd, ok := reqd.(bool)
+		if !ok {
 			continue
 		}
 		name, ok := strings.CutPrefix(vocab, d.vocabPrefix)
 		if ok {
 			if _, ok := d.allVocabs[name]; ok {
-				if !slices.Contains(vocabs, name) {
+				if (reqd || name == "format-assertion") && !slices.Contains(vocabs, name) {
 					vocabs = append(vocabs, name)
-					continue
 				}
+				continue
 			}
 		}
 		if _, ok := vocabularies[vocab]; !ok {
+			if !reqd {
+				continue
+			}
 			return nil, &UnsupportedVocabularyError{url.String(), vocab}
 		}
 		if !slices.Contains(vocabs, vocab) {
@@ -224,9 +259,82 @@ func (d *Draft) getVocabs(url url, doc any, vocabularies map[string]*Vocabulary)
 
 // --
 
+// Dialect describes a custom dialect, identified by its
+// metaschema url. see [Compiler.RegisterDialect].
+type Dialect struct {
+	// URL is the metaschema url, used as value of `$schema`.
+	URL string
+
+	// Draft on which the dialect is based.
+	Draft *Draft
+
+	// Vocabularies are urls of vocabularies enabled.
+	// These can be standard vocabularies of Draft or custom
+	// vocabularies registered using [Compiler.RegisterVocabulary].
+	// nil means default vocabularies of Draft.
+	//
+	// NOTE: vocabularies are supported only for draft >= 2019-09.
+	Vocabularies []string
+}
+
+// vocabNames returns vocabulary names, in the form used by dialect.
+func (d *Dialect) vocabNames(vocabularies map[string]*Vocabulary) ([]string, error) {
+	if d.Draft.version < 2019 || d.Vocabularies == nil {
+		return nil, nil
+	}
+	var vocabs []string
+	for _, vocab := range d.Vocabularies {
+		name, ok := strings.CutPrefix(vocab, d.Draft.vocabPrefix)
+		if _, known := d.Draft.allVocabs[name]; !ok || !known {
+			if _, ok := vocabularies[vocab]; !ok {
+				return nil, &UnsupportedVocabularyError{d.URL, vocab}
+			}
+			name = vocab
+		}
+		if !slices.Contains(vocabs, name) {
+			vocabs = append(vocabs, name)
+		}
+	}
+	if !slices.Contains(vocabs, "core") {
+		vocabs = append(vocabs, "core")
+	}
+	return vocabs, nil
+}
+
+// --
+
 type dialect struct {
-	draft  *Draft
-	vocabs []string // nil means use draft.defaultVocabs
+	draft     *Draft
+	vocabs    []string        // nil means use draft.defaultVocabs
+	url       string          // value of $schema, "" means draft.url
+	overrides map[url]*Schema // metaschemas to be used instead of embedded
+}
+
+func (d *dialect) metaURL() string {
+	if d.url != "" {
+		return d.url
+	}
+	return d.draft.url
+}
+
+// vocabURLs returns urls of vocabularies enabled in this dialect.
+func (d *dialect) vocabURLs() []string {
+	if d.draft.version < 2019 {
+		return nil
+	}
+	vocabs := d.vocabs
+	if vocabs == nil {
+		vocabs = d.draft.defaultVocabs
+	}
+	urls := make([]string, 0, len(vocabs))
+	for _, vocab := range vocabs {
+		if _, ok := d.draft.allVocabs[vocab]; ok {
+			urls = append(urls, d.draft.vocabPrefix+vocab)
+		} else {
+			urls = append(urls, vocab)
+		}
+	}
+	return urls
 }
 
 func (d *dialect) hasVocab(name string) bool {
@@ -263,16 +371,27 @@ func (d *dialect) activeVocabs(assertVocabs bool, vocabularies map[string]*Vocab
 	return vocabs
 }
 
+// meta returns the metaschema with given url, which is
+// sch, unless overridden.
+func (d *dialect) meta(u string, sch *Schema) *Schema {
+	if override, ok := d.overrides[url(u)]; ok {
+		return override
+	}
+	return sch
+}
+
 func (d *dialect) getSchema(assertVocabs bool, vocabularies map[string]*Vocabulary) *Schema {
 	vocabs := d.activeVocabs(assertVocabs, vocabularies)
 	if vocabs == nil {
-		return d.draft.sch
+		return d.meta(d.draft.url, d.draft.sch)
 	}
 
 	var allOf []*Schema
 	for _, vocab := range vocabs {
 		sch := d.draft.allVocabs[vocab]
-		if sch == nil {
+		if sch != nil {
+			sch = d.meta(d.draft.vocabMetaURL(vocab), sch)
+		} else {
 			if v, ok := vocabularies[vocab]; ok {
 				sch = v.Schema
 			}
@@ -284,7 +403,9 @@ func (d *dialect) getSchema(assertVocabs bool, vocabularies map[string]*Vocabula
 	if !slices.Contains(vocabs, "core") {
 		sch := d.draft.allVocabs["core"]
 		if sch == nil {
-			sch = d.draft.sch
+			sch = d.meta(d.draft.url, d.draft.sch)
+		} else {
+			sch = d.meta(d.draft.vocabMetaURL("core"), sch)
 		}
 		allOf = append(allOf, sch)
 	}
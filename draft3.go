@@ -0,0 +1,205 @@
+package jsonschema
+
+import (
+	"slices"
+	"strings"
+)
+
+// EnableDraft3 enables compiling schema documents with `$schema`
+// of draft-03, by converting them to draft-04 when loaded:
+//   - `required` boolean in property schema, to `required` of parent
+//   - `divisibleBy` to `multipleOf`
+//   - `extends` to `allOf`
+//   - `disallow` to `not`
+//   - schemas in `type` to `anyOf`, type "any" is removed
+//   - string value of `dependencies` to array
+//   - formats "ip-address" and "host-name" to "ipv4" and "hostname"
+//
+// The converted document is validated against draft-04 metaschema.
+// Note that keyword locations in errors are of converted document,
+// which differ from the source, for `extends`, `disallow` and `type`
+// with schemas.
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) EnableDraft3() {
+	c.roots.draft3 = true
+}
+
+func isDraft3(doc any) bool {
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return false
+	}
+	s, ok := obj["$schema"].(string)
+	if !ok {
+		return false
+	}
+	s = strings.TrimSuffix(s, "#")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "http://"), "https://")
+	return s == "json-schema.org/draft-03/schema"
+}
+
+// draft3To4 returns copy of draft-03 schema v, converted to draft-04.
+// It also returns value of `required`, if it is boolean.
+func draft3To4(v any) (any, bool) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v, false
+	}
+	out := make(map[string]any, len(obj))
+	for kw, v := range obj {
+		out[kw] = v
+	}
+
+	required, isBool := out["required"].(bool)
+	if isBool {
+		delete(out, "required")
+	}
+	if isDraft3(out) {
+		out["$schema"] = Draft4.url + "#"
+	}
+
+	// subschemas --
+	for _, kw := range []string{"additionalProperties", "additionalItems"} {
+		if _, ok := out[kw].(map[string]any); ok {
+			out[kw], _ = draft3To4(out[kw])
+		}
+	}
+	switch items := out["items"].(type) {
+	case map[string]any:
+		out["items"], _ = draft3To4(items)
+	case []any:
+		out["items"] = draft3To4Arr(items)
+	}
+	if props, ok := out["properties"].(map[string]any); ok {
+		conv := make(map[string]any, len(props))
+		var reqd []string
+		for pname, psch := range props {
+			var r bool
+			conv[pname], r = draft3To4(psch)
+			if r {
+				reqd = append(reqd, pname)
+			}
+		}
+		out["properties"] = conv
+		if len(reqd) > 0 {
+			slices.Sort(reqd)
+			arr := make([]any, len(reqd))
+			for i, pname := range reqd {
+				arr[i] = pname
+			}
+			out["required"] = arr
+		}
+	}
+	for _, kw := range []string{"patternProperties", "definitions"} {
+		if m, ok := out[kw].(map[string]any); ok {
+			conv := make(map[string]any, len(m))
+			for k, sch := range m {
+				conv[k], _ = draft3To4(sch)
+			}
+			out[kw] = conv
+		}
+	}
+	if deps, ok := out["dependencies"].(map[string]any); ok {
+		conv := make(map[string]any, len(deps))
+		for pname, dep := range deps {
+			switch dep := dep.(type) {
+			case string:
+				conv[pname] = []any{dep}
+			case map[string]any:
+				conv[pname], _ = draft3To4(dep)
+			default:
+				conv[pname] = dep
+			}
+		}
+		out["dependencies"] = conv
+	}
+
+	// renamed --
+	if v, ok := out["divisibleBy"]; ok {
+		delete(out, "divisibleBy")
+		if _, ok := out["multipleOf"]; !ok {
+			out["multipleOf"] = v
+		}
+	}
+	if f, ok := out["format"].(string); ok {
+		switch f {
+		case "ip-address":
+			out["format"] = "ipv4"
+		case "host-name":
+			out["format"] = "hostname"
+		}
+	}
+
+	// restructured --
+	if t, ok := out["type"]; ok {
+		delete(out, "type")
+		if s := draft3Types(t); s != nil {
+			if anyOf, ok := s["anyOf"]; ok {
+				out["anyOf"] = anyOf
+			} else {
+				out["type"] = s["type"]
+			}
+		}
+	}
+	if d, ok := out["disallow"]; ok {
+		delete(out, "disallow")
+		if s := draft3Types(d); s != nil {
+			out["not"] = s
+		} else {
+			// disallow any
+			out["not"] = map[string]any{}
+		}
+	}
+	switch ext := out["extends"].(type) {
+	case map[string]any:
+		sch, _ := draft3To4(ext)
+		out["allOf"] = []any{sch}
+	case []any:
+		out["allOf"] = draft3To4Arr(ext)
+	}
+	delete(out, "extends")
+	return out, required
+}
+
+func draft3To4Arr(arr []any) []any {
+	conv := make([]any, len(arr))
+	for i, item := range arr {
+		conv[i], _ = draft3To4(item)
+	}
+	return conv
+}
+
+// draft3Types returns draft-04 schema, which allows values matched
+// by draft-03 `type` value t. Returns nil, if t allows any value.
+func draft3Types(t any) map[string]any {
+	arr, ok := t.([]any)
+	if !ok {
+		arr = []any{t}
+	}
+	var types []any
+	var schemas []any
+	for _, item := range arr {
+		switch item := item.(type) {
+		case string:
+			if item == "any" {
+				return nil
+			}
+			types = append(types, item)
+		case map[string]any:
+			sch, _ := draft3To4(item)
+			schemas = append(schemas, sch)
+		}
+	}
+	var typesVal any = types
+	if len(types) == 1 {
+		typesVal = types[0]
+	}
+	if len(schemas) == 0 {
+		return map[string]any{"type": typesVal}
+	}
+	if len(types) > 0 {
+		schemas = append([]any{map[string]any{"type": typesVal}}, schemas...)
+	}
+	return map[string]any{"anyOf": schemas}
+}
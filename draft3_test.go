@@ -0,0 +1,83 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestEnableDraft3(t *testing.T) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$schema": "http://json-schema.org/draft-03/schema#",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "required": true},
+			"count": {"type": "integer", "divisibleBy": 5},
+			"id": {"type": ["integer", {"type": "string", "pattern": "^[a-z]+$"}]},
+			"value": {"disallow": ["null", "boolean"]},
+			"host": {"type": "string", "format": "host-name"},
+			"child": {"extends": {"$ref": "#/definitions/base"}, "required": false},
+			"any": {"type": "any"}
+		},
+		"dependencies": {"count": "name"},
+		"definitions": {
+			"base": {"type": "object", "properties": {"kind": {"required": true}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("schema.json"); err == nil {
+		t.Fatal("draft-03 must not be supported by default")
+	}
+
+	c = jsonschema.NewCompiler()
+	c.EnableDraft3()
+	c.AssertFormat()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := []string{
+		`{"name": "x"}`,
+		`{"name": "x", "count": 10, "id": 1}`,
+		`{"name": "x", "id": "abc", "value": 1, "host": "example.com"}`,
+		`{"name": "x", "child": {"kind": 1}, "any": null}`,
+	}
+	for _, v := range valid {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Validate(inst); err != nil {
+			t.Errorf("%s: %v", v, err)
+		}
+	}
+	invalid := []string{
+		`{}`,
+		`{"name": "x", "count": 7}`,
+		`{"name": "x", "id": "ABC"}`,
+		`{"name": "x", "value": null}`,
+		`{"name": "x", "host": "-bad-"}`,
+		`{"name": "x", "child": {}}`,
+		`{"count": 10}`,
+	}
+	for _, v := range invalid {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Validate(inst); err == nil {
+			t.Errorf("%s: want error", v)
+		}
+	}
+}
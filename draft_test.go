@@ -77,7 +77,7 @@ func TestDraft_collectIds(t *testing.T) {
 		resources:           map[jsonPointer]*resource{},
 		subschemasProcessed: map[jsonPointer]struct{}{},
 	}
-	if err := rr.collectResources(&r, doc, u, jsonPointer(""), dialect{Draft4, nil}); err != nil {
+	if err := rr.collectResources(&r, doc, u, jsonPointer(""), dialect{Draft4, nil, "", nil}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -124,7 +124,7 @@ func TestDraft_collectAnchors(t *testing.T) {
 		resources:           map[jsonPointer]*resource{},
 		subschemasProcessed: map[jsonPointer]struct{}{},
 	}
-	if err := rr.collectResources(&r, doc, u, jsonPointer(""), dialect{Draft2020, nil}); err != nil {
+	if err := rr.collectResources(&r, doc, u, jsonPointer(""), dialect{Draft2020, nil, "", nil}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -0,0 +1,70 @@
+package jsonschema
+
+import "slices"
+
+// DynamicRefResolution tells how a `$dynamicRef` or
+// `$recursiveRef` is resolved during validation.
+type DynamicRefResolution struct {
+	// location of the JSON value within the instance.
+	InstanceLocation []string
+
+	// absolute, dereferenced location of schema,
+	// which contains the keyword.
+	SchemaURL string
+
+	// Keyword is `$dynamicRef` or `$recursiveRef`.
+	Keyword string
+
+	// Anchor is the fragment of `$dynamicRef`,
+	// if it is an anchor. empty for `$recursiveRef`.
+	Anchor string
+
+	// InitialTarget is location of the schema, to which
+	// the reference resolves statically.
+	InitialTarget string
+
+	// Target is location of the schema used for validation.
+	Target string
+
+	// Scope lists locations of schema resources in dynamic
+	// scope, outermost first, which are searched for anchor.
+	Scope []string
+
+	// Reason explains why Target is chosen.
+	Reason string
+}
+
+// TraceDynamicRefs validates v, and returns how each `$dynamicRef`
+// and `$recursiveRef` is resolved, in the order they are evaluated.
+// This helps to debug issues with metaschemas and dynamic anchors.
+//
+// Resolutions are returned, even if v is not valid.
+func (sch *Schema) TraceDynamicRefs(v any) ([]*DynamicRefResolution, error) {
+	var dynRefs []*DynamicRefResolution
+	_, err := sch.validateWith(v, runOpts{dynRefs: &dynRefs})
+	return dynRefs, err
+}
+
+func (vd *validator) traceDynamicRef(kw, anchor string, initial, target *Schema, reason string) {
+	if vd.opts.dynRefs == nil {
+		return
+	}
+	var scope []string
+	for scp := vd.scp; scp != nil; scp = scp.parent {
+		loc := scp.sch.resource.Location
+		if len(scope) == 0 || scope[len(scope)-1] != loc {
+			scope = append(scope, loc)
+		}
+	}
+	slices.Reverse(scope)
+	*vd.opts.dynRefs = append(*vd.opts.dynRefs, &DynamicRefResolution{
+		InstanceLocation: vd.instanceLocation(),
+		SchemaURL:        vd.sch.Location,
+		Keyword:          kw,
+		Anchor:           anchor,
+		InitialTarget:    initial.Location,
+		Target:           target.Location,
+		Scope:            scope,
+		Reason:           reason,
+	})
+}
@@ -0,0 +1,75 @@
+package jsonschema_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestTraceDynamicRefs(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	tree := `{
+		"$id": "http://example.com/tree.json",
+		"$dynamicAnchor": "node",
+		"type": "object",
+		"properties": {
+			"children": {"items": {"$dynamicRef": "#node"}}
+		}
+	}`
+	strictTree := `{
+		"$id": "http://example.com/strict-tree.json",
+		"$dynamicAnchor": "node",
+		"$ref": "tree.json",
+		"unevaluatedProperties": false
+	}`
+	for url, schema := range map[string]string{"http://example.com/tree.json": tree, "http://example.com/strict-tree.json": strictTree} {
+		if err := c.AddResourceJSON(url, []byte(schema)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sch, err := c.Compile("http://example.com/strict-tree.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := map[string]any{"children": []any{map[string]any{"daat": 1}}}
+	trace, err := sch.TraceDynamicRefs(inst)
+	if err == nil {
+		t.Fatal("want validation error")
+	}
+	if len(trace) != 1 {
+		t.Fatalf("got %d resolutions, want 1", len(trace))
+	}
+	r := trace[0]
+	if r.Keyword != "$dynamicRef" || r.Anchor != "node" {
+		t.Fatalf("got %s with anchor %q", r.Keyword, r.Anchor)
+	}
+	if r.InitialTarget != "http://example.com/tree.json#" {
+		t.Errorf("got initial target %q", r.InitialTarget)
+	}
+	if r.Target != "http://example.com/strict-tree.json#" {
+		t.Errorf("got target %q", r.Target)
+	}
+	if want := []string{"http://example.com/strict-tree.json#", "http://example.com/tree.json#"}; !slices.Equal(r.Scope, want) {
+		t.Errorf("got scope %v, want %v", r.Scope, want)
+	}
+	if want := []string{"children", "0"}; !slices.Equal(r.InstanceLocation, want) {
+		t.Errorf("got instance location %v, want %v", r.InstanceLocation, want)
+	}
+
+	// without matching anchor in initial target
+	if err := c.AddResourceJSON("http://example.com/plain.json", []byte(`{"$dynamicRef": "tree.json"}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err = c.Compile("http://example.com/plain.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err = sch.TraceDynamicRefs(map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace) != 1 || trace[0].Target != "http://example.com/tree.json#" || trace[0].Reason != "$dynamicRef has no anchor" {
+		t.Fatalf("got %+v", trace)
+	}
+}
@@ -0,0 +1,60 @@
+package jsonschema_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// markdownFormatter renders leaf errors as markdown list.
+func markdownFormatter(e *jsonschema.ValidationError) string {
+	p := message.NewPrinter(language.English)
+	var sb strings.Builder
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			loc := jsonschema.KeywordPath(e.InstanceLocation).String()
+			fmt.Fprintf(&sb, "- `%s`: %s\n", loc, e.ErrorKind.LocalizedString(p))
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(e)
+	return sb.String()
+}
+
+// Example_formatter shows how to control text rendering of errors.
+func Example_formatter() {
+	c := jsonschema.NewCompiler()
+	c.UseFormatter(markdownFormatter)
+	if err := c.AddResourceJSON("schema.json", []byte(`{
+		"properties": {
+			"name": { "type": "string" },
+			"tags": { "maxItems": 1 }
+		}
+	}`)); err != nil {
+		log.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	inst := map[string]any{"name": 1, "tags": []any{"a", "b"}}
+	fmt.Print(sch.Validate(inst))
+
+	// override per call
+	opts := &jsonschema.ValidateOptions{Formatter: func(e *jsonschema.ValidationError) string {
+		return fmt.Sprintf("%d errors", len(e.Causes))
+	}}
+	fmt.Println(sch.ValidateWithOptions(inst, opts))
+	// Output:
+	// - `/name`: got number, want string
+	// - `/tags`: maxItems: got 2, want 1
+	// 2 errors
+}
@@ -141,6 +141,10 @@ func validateUUID(v any) error {
 
 // see https://datatracker.ietf.org/doc/html/rfc3339#appendix-A
 func validateDuration(v any) error {
+	return defaultFormatOptions.validateDuration(v)
+}
+
+func (opts *FormatOptions) validateDuration(v any) error {
 	s, ok := v.(string)
 	if !ok {
 		return nil
@@ -160,6 +164,12 @@ func validateDuration(v any) error {
 		if s == "" {
 			return LocalizableError("no number in week")
 		}
+		if i := strings.IndexAny(s, ".,"); i != -1 && opts.FractionalDuration {
+			if i == 0 || i == len(s)-1 {
+				return LocalizableError("invalid week")
+			}
+			s = s[:i] + s[i+1:]
+		}
 		for _, ch := range s {
 			if ch < '0' || ch > '9' {
 				return LocalizableError("invalid week")
@@ -169,7 +179,8 @@ func validateDuration(v any) error {
 	}
 
 	allUnits := []string{"YMD", "HMS"}
-	for i, s := range strings.Split(s, "T") {
+	parts := strings.Split(s, "T")
+	for i, s := range parts {
 		if i != 0 && s == "" {
 			return LocalizableError("no time elements")
 		}
@@ -190,6 +201,21 @@ func validateDuration(v any) error {
 				return LocalizableError("missing number")
 			}
 			s = s[digitCount:]
+			fraction := false
+			if opts.FractionalDuration && s != "" && (s[0] == '.' || s[0] == ',') {
+				fracCount := 0
+				for _, ch := range s[1:] {
+					if ch >= '0' && ch <= '9' {
+						fracCount++
+					} else {
+						break
+					}
+				}
+				if fracCount == 0 {
+					return LocalizableError("no digits in fraction")
+				}
+				s, fraction = s[1+fracCount:], true
+			}
 			if s == "" {
 				return LocalizableError("missing unit")
 			}
@@ -203,6 +229,9 @@ func validateDuration(v any) error {
 			}
 			units = units[j+1:]
 			s = s[1:]
+			if fraction && (s != "" || i != len(parts)-1) {
+				return LocalizableError("fraction allowed only in last element")
+			}
 		}
 	}
 
@@ -253,11 +282,19 @@ func validateIPV6(v any) error {
 
 // see https://en.wikipedia.org/wiki/Hostname#Restrictions_on_valid_host_names
 func validateHostname(v any) error {
+	return defaultFormatOptions.validateHostname(v)
+}
+
+func (opts *FormatOptions) validateHostname(v any) error {
 	s, ok := v.(string)
 	if !ok {
 		return nil
 	}
 
+	if opts.DenyTrailingDot && strings.HasSuffix(s, ".") {
+		return LocalizableError("trailing dot not allowed")
+	}
+
 	// entire hostname (including the delimiting dots but not a trailing dot) has a maximum of 253 ASCII characters
 	s = strings.TrimSuffix(s, ".")
 	if len(s) > 253 {
@@ -287,6 +324,7 @@ func validateHostname(v any) error {
 			case ch >= 'A' && ch <= 'Z':
 			case ch >= '0' && ch <= '9':
 			case ch == '-':
+			case ch == '_' && opts.HostnameUnderscores:
 			default:
 				return LocalizableError("invalid character %q", ch)
 			}
@@ -297,6 +335,10 @@ func validateHostname(v any) error {
 
 // see https://en.wikipedia.org/wiki/Email_address
 func validateEmail(v any) error {
+	return defaultFormatOptions.validateEmail(v)
+}
+
+func (opts *FormatOptions) validateEmail(v any) error {
 	s, ok := v.(string)
 	if !ok {
 		return nil
@@ -318,7 +360,29 @@ func validateEmail(v any) error {
 		return LocalizableError("local part more than 64 characters long")
 	}
 
-	if len(local) > 1 && strings.HasPrefix(local, `"`) && strings.HasPrefix(local, `"`) {
+	if opts.EmailRFC5321 && len(local) > 1 && strings.HasPrefix(local, `"`) && strings.HasSuffix(local, `"`) {
+		// quoted-string, see https://datatracker.ietf.org/doc/html/rfc5321#section-4.1.2
+		local := local[1 : len(local)-1]
+		escape := false
+		for _, ch := range local {
+			switch {
+			case escape:
+				// quoted-pairSMTP
+				if ch < ' ' || ch > '~' {
+					return LocalizableError("invalid escaped character %q", ch)
+				}
+				escape = false
+			case ch == '\\':
+				escape = true
+			case ch == '"' || ch < ' ' || ch > '~':
+				// qtextSMTP
+				return LocalizableError("invalid character %q in quoted local part", ch)
+			}
+		}
+		if escape {
+			return LocalizableError("incomplete escape in quoted local part")
+		}
+	} else if len(local) > 1 && strings.HasPrefix(local, `"`) && strings.HasPrefix(local, `"`) {
 		// quoted
 		local := local[1 : len(local)-1]
 		if strings.IndexByte(local, '\\') != -1 || strings.IndexByte(local, '"') != -1 {
@@ -367,7 +431,7 @@ func validateEmail(v any) error {
 	}
 
 	// domain must match the requirements for a hostname
-	if err := validateHostname(domain); err != nil {
+	if err := opts.validateHostname(domain); err != nil {
 		return LocalizableError("invalid domain: %v", err)
 	}
 
@@ -387,14 +451,22 @@ func validateDate(v any) error {
 // see https://datatracker.ietf.org/doc/html/rfc3339#section-5.6
 // NOTE: golang time package does not support leap seconds.
 func validateTime(v any) error {
+	return defaultFormatOptions.validateTime(v)
+}
+
+func (opts *FormatOptions) validateTime(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return nil
 	}
 
 	// min: hh:mm:ssZ
-	if len(str) < 9 {
-		return LocalizableError("less than 9 characters long")
+	minLen := 9
+	if opts.OptionalOffset {
+		minLen = 8
+	}
+	if len(str) < minLen {
+		return LocalizableError("less than %d characters long", minLen)
 	}
 	if str[2] != ':' || str[5] != ':' {
 		return LocalizableError("missing colon in correct place")
@@ -437,7 +509,11 @@ func validateTime(v any) error {
 		str = rem[numDigits:]
 	}
 
-	if str != "z" && str != "Z" {
+	switch {
+	case str == "z" && opts.DenyLowercase:
+		return LocalizableError("lowercase z not allowed")
+	case str == "" && opts.OptionalOffset:
+	case str != "z" && str != "Z":
 		// parse time-numoffset
 		if len(str) != 6 {
 			return LocalizableError("offset must be 6 characters long")
@@ -481,6 +557,9 @@ func validateTime(v any) error {
 	}
 
 	// check leap second
+	if s >= 60 && opts.DenyLeapSeconds {
+		return LocalizableError("leap second not allowed")
+	}
 	if s >= 60 && (h != 23 || m != 59) {
 		return LocalizableError("invalid leap second")
 	}
@@ -490,23 +569,34 @@ func validateTime(v any) error {
 
 // see https://datatracker.ietf.org/doc/html/rfc3339#section-5.6
 func validateDateTime(v any) error {
+	return defaultFormatOptions.validateDateTime(v)
+}
+
+func (opts *FormatOptions) validateDateTime(v any) error {
 	s, ok := v.(string)
 	if !ok {
 		return nil
 	}
 
 	// min: yyyy-mm-ddThh:mm:ssZ
-	if len(s) < 20 {
-		return LocalizableError("less than 20 characters long")
+	minLen := 20
+	if opts.OptionalOffset {
+		minLen = 19
+	}
+	if len(s) < minLen {
+		return LocalizableError("less than %d characters long", minLen)
 	}
 
+	if s[10] == 't' && opts.DenyLowercase {
+		return LocalizableError("11th character must be T")
+	}
 	if s[10] != 't' && s[10] != 'T' {
 		return LocalizableError("11th character must be t or T")
 	}
 	if err := validateDate(s[:10]); err != nil {
 		return LocalizableError("invalid date element: %v", err)
 	}
-	if err := validateTime(s[11:]); err != nil {
+	if err := opts.validateTime(s[11:]); err != nil {
 		return LocalizableError("invalid time element: %v", err)
 	}
 	return nil
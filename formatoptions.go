@@ -0,0 +1,148 @@
+package jsonschema
+
+import (
+	"strings"
+	"time"
+)
+
+// FormatOptions configures policies of builtin formats,
+// so that validation matches exactly with an external system.
+// The zero value gives the default behavior.
+//
+// see [Compiler.UseFormatOptions].
+type FormatOptions struct {
+	// DenyLeapSeconds rejects second 60 in `time` and `date-time`.
+	DenyLeapSeconds bool
+
+	// DenyLowercase rejects lowercase 't' and 'z'
+	// in `time` and `date-time`.
+	DenyLowercase bool
+
+	// OptionalOffset allows `time` and `date-time`
+	// without time offset.
+	OptionalOffset bool
+
+	// FractionalDuration allows decimal fraction in the last
+	// element of `duration`, such as "PT0.5S" and "P1,5D", as
+	// permitted by ISO 8601.
+	FractionalDuration bool
+
+	// HostnameUnderscores allows underscore in labels of `hostname`
+	// and domain of `email`, as used by internal DNS and SRV records.
+	HostnameUnderscores bool
+
+	// DenyTrailingDot rejects trailing dot in `hostname`
+	// and domain of `email`.
+	DenyTrailingDot bool
+
+	// EmailRFC5321 validates quoted local part of `email` as per
+	// RFC 5321, which allows escaped characters like `"a\"b"@x.com`.
+	EmailRFC5321 bool
+
+	// Now returns current time, for formats:
+	//   - past-date-time, future-date-time
+	//   - past-date, future-date
+	//
+	// past-* formats reject values after current time, and
+	// future-* formats reject values before current time.
+	// Defaults to [time.Now].
+	Now func() time.Time
+}
+
+var defaultFormatOptions = &FormatOptions{}
+
+// UseFormatOptions replaces builtin formats `time`, `date-time`,
+// `duration`, `hostname` and `email` with the ones using policies
+// in opts. It also registers
+// formats past-date-time, future-date-time, past-date, future-date
+// and go-duration. The go-duration format accepts durations
+// recognized by [time.ParseDuration], such as "1h30m".
+//
+// Formats registered with [Compiler.RegisterFormat] take precedence,
+// if they are registered after this call.
+func (c *Compiler) UseFormatOptions(opts *FormatOptions) {
+	for _, f := range opts.formats() {
+		c.RegisterFormat(f)
+	}
+}
+
+func (opts *FormatOptions) formats() []*Format {
+	return []*Format{
+		{"time", opts.validateTime},
+		{"date-time", opts.validateDateTime},
+		{"duration", opts.validateDuration},
+		{"hostname", opts.validateHostname},
+		{"email", opts.validateEmail},
+		{"go-duration", validateGoDuration},
+		{"past-date-time", func(v any) error { return opts.validateDateTimeAt(v, -1) }},
+		{"future-date-time", func(v any) error { return opts.validateDateTimeAt(v, 1) }},
+		{"past-date", func(v any) error { return opts.validateDateAt(v, -1) }},
+		{"future-date", func(v any) error { return opts.validateDateAt(v, 1) }},
+	}
+}
+
+func validateGoDuration(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	_, err := time.ParseDuration(s)
+	return err
+}
+
+func (opts *FormatOptions) now() time.Time {
+	if opts.Now != nil {
+		return opts.Now()
+	}
+	return time.Now()
+}
+
+// validateDateTimeAt validates date-time, which must not be after
+// current time if when is -1, and not before if when is 1.
+func (opts *FormatOptions) validateDateTimeAt(v any, when int) error {
+	if err := opts.validateDateTime(v); err != nil {
+		return err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	// time package does not support leap seconds, lowercase
+	// and missing offset.
+	s = strings.ToUpper(s)
+	if s[17:19] == "60" {
+		s = s[:17] + "59" + s[19:]
+	}
+	if !strings.ContainsAny(s[19:], "Z+-") {
+		s += "Z"
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+	return checkWhen(t.Compare(opts.now()), when)
+}
+
+// validateDateAt validates date, which must not be after current
+// date if when is -1, and not before if when is 1.
+func (opts *FormatOptions) validateDateAt(v any, when int) error {
+	if err := validateDate(v); err != nil {
+		return err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	return checkWhen(strings.Compare(s, opts.now().Format(time.DateOnly)), when)
+}
+
+func checkWhen(cmp, when int) error {
+	switch {
+	case when < 0 && cmp > 0:
+		return LocalizableError("must not be in future")
+	case when > 0 && cmp < 0:
+		return LocalizableError("must not be in past")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package jsonschema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestFormatOptions(t *testing.T) {
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		opts   jsonschema.FormatOptions
+		format string
+		value  string
+		valid  bool
+	}{
+		{jsonschema.FormatOptions{}, "date-time", "2016-12-31t23:59:60z", true},
+		{jsonschema.FormatOptions{DenyLowercase: true}, "date-time", "2016-12-31t23:59:59Z", false},
+		{jsonschema.FormatOptions{DenyLowercase: true}, "date-time", "2016-12-31T23:59:59z", false},
+		{jsonschema.FormatOptions{DenyLowercase: true}, "time", "23:59:59z", false},
+		{jsonschema.FormatOptions{DenyLowercase: true}, "date-time", "2016-12-31T23:59:59Z", true},
+		{jsonschema.FormatOptions{DenyLeapSeconds: true}, "date-time", "2016-12-31T23:59:60Z", false},
+		{jsonschema.FormatOptions{DenyLeapSeconds: true}, "time", "23:59:60Z", false},
+		{jsonschema.FormatOptions{}, "date-time", "2016-12-31T23:59:59", false},
+		{jsonschema.FormatOptions{OptionalOffset: true}, "date-time", "2016-12-31T23:59:59", true},
+		{jsonschema.FormatOptions{OptionalOffset: true}, "time", "23:59:59.5", true},
+		{jsonschema.FormatOptions{}, "duration", "PT0.5S", false},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "PT0.5S", true},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "P1,5D", true},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "P0.5W", true},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "PT1.5M30S", false},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "P1.5DT1H", false},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "PT.5S", false},
+		{jsonschema.FormatOptions{FractionalDuration: true}, "duration", "PT1.S", false},
+		{jsonschema.FormatOptions{}, "hostname", "_sip._tcp.example.com", false},
+		{jsonschema.FormatOptions{HostnameUnderscores: true}, "hostname", "_sip._tcp.example.com", true},
+		{jsonschema.FormatOptions{HostnameUnderscores: true}, "email", "joe@my_host.internal", true},
+		{jsonschema.FormatOptions{}, "hostname", "example.com.", true},
+		{jsonschema.FormatOptions{DenyTrailingDot: true}, "hostname", "example.com.", false},
+		{jsonschema.FormatOptions{DenyTrailingDot: true}, "email", "joe@example.com.", false},
+		{jsonschema.FormatOptions{}, "email", `"a\"b"@example.com`, false},
+		{jsonschema.FormatOptions{EmailRFC5321: true}, "email", `"a\"b"@example.com`, true},
+		{jsonschema.FormatOptions{EmailRFC5321: true}, "email", `"a"b"@example.com`, false},
+		{jsonschema.FormatOptions{EmailRFC5321: true}, "email", `"ab\"@example.com`, false},
+		{jsonschema.FormatOptions{}, "go-duration", "1h30m", true},
+		{jsonschema.FormatOptions{}, "go-duration", "1.5s", true},
+		{jsonschema.FormatOptions{}, "go-duration", "1d", false},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "past-date-time", "2024-06-15T09:59:59Z", true},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "past-date-time", "2024-06-15T10:00:01Z", false},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "past-date-time", "2024-06-15T12:00:00+05:30", true},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "future-date-time", "2024-06-15T10:00:01Z", true},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "future-date-time", "2024-06-15T09:59:59Z", false},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "past-date", "2024-06-15", true},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "past-date", "2024-06-16", false},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "future-date", "2024-06-14", false},
+		{jsonschema.FormatOptions{Now: func() time.Time { return now }}, "future-date", "2024-06-15", true},
+	}
+	for _, test := range tests {
+		c := jsonschema.NewCompiler()
+		c.AssertFormat()
+		c.UseFormatOptions(&test.opts)
+		if err := c.AddResource("schema.json", map[string]any{"format": test.format}); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sch.Validate(test.value) == nil; got != test.valid {
+			t.Errorf("%+v %s %q: got valid %v, want %v", test.opts, test.format, test.value, got, test.valid)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package jsonschema
+
+// Formatter renders validation error as text. It is used by
+// [ValidationError.Error], when installed using
+// [Compiler.UseFormatter] or [ValidateOptions.Formatter].
+//
+// It gets the top-level error, and can walk its Causes to
+// render them using ErrorKind, for example as markdown list.
+type Formatter func(e *ValidationError) string
+
+// UseFormatter installs f in validation errors returned by
+// [Schema.Validate], [Schema.ValidateWithOptions] and [Validator]
+// of the schemas compiled after this call.
+func (c *Compiler) UseFormatter(f Formatter) {
+	c.formatter = f
+}
+
+// withFormatter installs f in err, if err is [*ValidationError].
+// If f is nil, formatter of sch is installed.
+func (sch *Schema) withFormatter(err error, f Formatter) error {
+	if f == nil {
+		f = sch.formatter
+	}
+	if verr, ok := err.(*ValidationError); ok && f != nil {
+		verr.formatter = f
+	}
+	return err
+}
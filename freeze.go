@@ -0,0 +1,112 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Freeze records the state of exported fields of sch and of
+// schemas reachable from it, so that their modification can be
+// detected using [Schema.CheckFrozen].
+//
+// Extensions are opaque, so schemas reachable only through
+// extensions are not covered, nor is the state held by extensions.
+// Only replacing elements of Extensions field is detected.
+//
+// Compiled schemas are safe for concurrent use, only if they are
+// not modified. Freeze does not prevent modification, nor the data
+// race it causes with concurrent validations; it only helps to find
+// the code responsible. Shared schemas should be frozen before
+// sharing, and checked in tests, to catch code which modifies them.
+//
+// Freeze must not be called concurrently with use of sch.
+func (sch *Schema) Freeze() {
+	var frozen []frozenSchema
+	walkSchemas(sch, func(s *Schema) {
+		frozen = append(frozen, frozenSchema{s, s.fieldStates()})
+	})
+	sch.frozen = frozen
+}
+
+// frozenSchema is the state of schema, recorded by Freeze.
+type frozenSchema struct {
+	sch    *Schema
+	states []string // of exported fields
+}
+
+// CheckFrozen returns [*SchemaModifiedError], if any exported
+// field of sch or of schemas reachable from it, has been modified
+// since [Schema.Freeze]. Modification of values pointed by fields,
+// such as `*sch.MaxLength`, is also detected. It returns nil, if
+// sch is not frozen.
+//
+// If many fields are modified, the first one is reported, in order
+// of schemas reachable from sch, nearest first, and fields in order
+// of declaration. It is not safe to call CheckFrozen concurrently
+// with code, which may modify the schemas.
+func (sch *Schema) CheckFrozen() error {
+	for _, f := range sch.frozen {
+		t := reflect.TypeOf(f.sch).Elem()
+		for i, state := range f.sch.fieldStates() {
+			if state != f.states[i] {
+				return &SchemaModifiedError{Location: f.sch.Location, Field: exportedFields(t)[i].Name}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldStates returns string representation of each exported
+// field, in which schemas are represented by their address.
+func (sch *Schema) fieldStates() []string {
+	v := reflect.ValueOf(sch).Elem()
+	fields := exportedFields(v.Type())
+	states := make([]string, len(fields))
+	for i, f := range fields {
+		states[i] = fieldState(v.FieldByIndex(f.Index))
+	}
+	return states
+}
+
+var schemaType = reflect.TypeOf((*Schema)(nil))
+
+func fieldState(v reflect.Value) string {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		if v.Type() == schemaType {
+			return fmt.Sprintf("%p", v.Interface())
+		}
+		// pointer to value such as *int, *big.Rat, *Enum
+		return fmt.Sprintf("%p %v", v.Interface(), v.Elem().Interface())
+	}
+	// schemas in slices and maps are printed as address
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+func exportedFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// --
+
+// SchemaModifiedError is returned by [Schema.CheckFrozen], if
+// schema is modified after [Schema.Freeze].
+type SchemaModifiedError struct {
+	// Location is absolute url of the modified schema.
+	Location string
+
+	// Field is the name of modified field.
+	Field string
+}
+
+func (e *SchemaModifiedError) Error() string {
+	return fmt.Sprintf("jsonschema: schema %s modified after freeze: field %s", e.Location, e.Field)
+}
@@ -0,0 +1,80 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestFreeze(t *testing.T) {
+	schema := `{
+		"$defs": {"name": {"type": "string", "maxLength": 10}},
+		"properties": {
+			"name": {"$ref": "#/$defs/name"},
+			"tags": {"items": {"enum": ["a", "b"]}}
+		},
+		"required": ["name"]
+	}`
+	tests := []struct {
+		name   string
+		modify func(sch *jsonschema.Schema)
+		field  string
+	}{
+		{"none", func(sch *jsonschema.Schema) {}, ""},
+		{"slice", func(sch *jsonschema.Schema) { sch.Required[0] = "x" }, "Required"},
+		{"map", func(sch *jsonschema.Schema) { delete(sch.Properties, "tags") }, "Properties"},
+		{"pointee", func(sch *jsonschema.Schema) { *sch.Properties["name"].Ref.MaxLength = 5 }, "MaxLength"},
+		{"subschema", func(sch *jsonschema.Schema) {
+			items := sch.Properties["tags"].Items2020
+			items.Enum.Values = append(items.Enum.Values, "c")
+		}, "Enum"},
+		{"many", func(sch *jsonschema.Schema) {
+			// nearest schema is reported
+			*sch.Properties["name"].Ref.MaxLength = 5
+			sch.Properties["tags"].Items2020.Enum.Values[0] = "x"
+			sch.Required = append(sch.Required, "tags")
+		}, "Required"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			if err := c.AddResource("schema.json", mustUnmarshal(t, schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sch.CheckFrozen(); err != nil {
+				t.Fatalf("not frozen: %v", err)
+			}
+			sch.Freeze()
+			test.modify(sch)
+			err = sch.CheckFrozen()
+			if test.field == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var merr *jsonschema.SchemaModifiedError
+			if !errors.As(err, &merr) {
+				t.Fatalf("got %v, want SchemaModifiedError", err)
+			}
+			if merr.Field != test.field {
+				t.Fatalf("field: got %q, want %q", merr.Field, test.field)
+			}
+		})
+	}
+}
+
+func mustUnmarshal(t *testing.T, s string) any {
+	t.Helper()
+	v, err := jsonschema.UnmarshalJSON(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
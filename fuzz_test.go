@@ -0,0 +1,138 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Run fuzz targets using:
+//
+//	go test -run=^$ -fuzz=FuzzCompile -fuzztime=1m
+
+func FuzzCompile(f *testing.F) {
+	seeds := []string{
+		`{}`,
+		`true`,
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"type": "string"}}}`,
+		`{"$id": "http://a.com/", "$defs": {"b": {"$id": "b.json", "$anchor": "x"}}, "$ref": "b.json#x"}`,
+		`{"$schema": "http://json-schema.org/draft-04/schema#", "id": "#a", "items": [{"$ref": "#a"}]}`,
+		`{"$schema": "http://json-schema.org/draft-07/schema#", "definitions": {"a~b/c": {}}, "$ref": "#/definitions/a~0b~1c"}`,
+		`{"$dynamicAnchor": "n", "properties": {"x": {"$dynamicRef": "#n"}}}`,
+		`{"patternProperties": {"^a+$": true}, "pattern": "[0-9]"}`,
+		`{"$ref": "#/$defs/a%25b", "$defs": {"a%b": {}}}`,
+		`{"contentMediaType": "application/json", "contentSchema": {"type": "integer"}}`,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		c := jsonschema.NewCompiler()
+		c.UseLoader(jsonschema.SchemeURLLoader{}) // no loading
+		if err := c.AddResource("http://fuzz.com/schema.json", doc); err != nil {
+			return
+		}
+		sch, err := c.Compile("http://fuzz.com/schema.json")
+		if err != nil {
+			return
+		}
+		_ = sch.Validate(doc)
+	})
+}
+
+func FuzzValidate(f *testing.F) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": ["object", "array", "string", "number"],
+		"properties": {
+			"name": { "type": "string", "minLength": 1, "pattern": "^[a-z]" },
+			"age": { "type": "integer", "minimum": 0, "multipleOf": 0.5 },
+			"tags": { "type": "array", "uniqueItems": true, "contains": { "const": "x" } }
+		},
+		"patternProperties": { "^x-": true },
+		"dependentRequired": { "a": ["b"] },
+		"prefixItems": [{ "enum": [1, "1", null] }],
+		"items": { "$ref": "#" },
+		"unevaluatedProperties": { "$ref": "#" },
+		"contentEncoding": "base64",
+		"contentMediaType": "application/json",
+		"maxLength": 100
+	}`))
+	if err != nil {
+		f.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.AssertContent()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		f.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	seeds := []string{
+		`{}`,
+		`{"name": "a", "age": 1.5, "tags": ["x", "y"]}`,
+		`[1, [2, {"x-y": 1}], "e30="]`,
+		`{"a": 1, "b": {"c": [1e400, -0.0]}}`,
+		`"eyJhIjogMX0="`,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		err = sch.Validate(inst)
+		if err, ok := err.(*jsonschema.ValidationError); ok {
+			_ = err.Error()
+			_ = err.DetailedOutput()
+		}
+	})
+}
+
+func FuzzFormat(f *testing.F) {
+	formatNames := []string{
+		"json-pointer", "relative-json-pointer", "uuid", "duration", "period",
+		"ipv4", "ipv6", "hostname", "email", "date", "time", "date-time",
+		"uri", "iri", "uri-reference", "iri-reference", "uri-template", "semver",
+		"regex",
+	}
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	var schemas []*jsonschema.Schema
+	for _, name := range formatNames {
+		url := "http://fuzz.com/" + name + ".json"
+		if err := c.AddResource(url, map[string]any{"format": name}); err != nil {
+			f.Fatal(err)
+		}
+		sch, err := c.Compile(url)
+		if err != nil {
+			f.Fatal(err)
+		}
+		schemas = append(schemas, sch)
+	}
+
+	seeds := []string{
+		"", "/a~0b", "0/a", "1#", "P1Y2M3DT4H5M6S", "P1W", "2020-01-01T00:00:00Z/P1D",
+		"127.0.0.1", "::ffff:1.2.3.4", "a.b-c.com", "a@[127.0.0.1]", `"a b"@c.com`,
+		"2020-02-29", "23:59:60+00:00", "http://a.com/%zz?q#f", "//a/b", "{/x*,y:3}",
+		"1.0.0-alpha+001", "[a-z]+", "\xff",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, sch := range schemas {
+			_ = sch.Validate(s)
+		}
+	})
+}
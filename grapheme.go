@@ -0,0 +1,149 @@
+package jsonschema
+
+import "unicode"
+
+// graphemeCount returns number of extended grapheme clusters in s,
+// as per Unicode Standard Annex #29. The Prepend and Indic conjunct
+// rules are not implemented, and Extended_Pictographic property is
+// approximated with emoji blocks.
+func graphemeCount(s string) int {
+	n := 0
+	prev := gbOther
+	ri := 0          // number of preceding regional indicators
+	pict := false    // preceded by ExtPict Extend*
+	zwjPict := false // preceded by ExtPict Extend* ZWJ
+	for i, r := range s {
+		p := gbPropOf(r)
+		if i == 0 || gbBreak(prev, p, ri, zwjPict) {
+			n++
+		}
+		if p == gbRI {
+			ri++
+		} else {
+			ri = 0
+		}
+		zwjPict = p == gbZWJ && pict
+		switch p {
+		case gbPict:
+			pict = true
+		case gbExtend:
+		default:
+			pict = false
+		}
+		prev = p
+	}
+	return n
+}
+
+type gbProp int
+
+const (
+	gbOther gbProp = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRI
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+	gbPict
+)
+
+// gbBreak tells whether there is grapheme cluster boundary
+// between characters with properties prev and p.
+func gbBreak(prev, p gbProp, ri int, zwjPict bool) bool {
+	switch {
+	case prev == gbCR && p == gbLF: // GB3
+		return false
+	case prev == gbCR || prev == gbLF || prev == gbControl: // GB4
+		return true
+	case p == gbCR || p == gbLF || p == gbControl: // GB5
+		return true
+	case prev == gbL && (p == gbL || p == gbV || p == gbLV || p == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (p == gbV || p == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && p == gbT: // GB8
+		return false
+	case p == gbExtend || p == gbZWJ || p == gbSpacingMark: // GB9, GB9a
+		return false
+	case prev == gbZWJ && p == gbPict && zwjPict: // GB11
+		return false
+	case prev == gbRI && p == gbRI: // GB12, GB13
+		return ri%2 == 0
+	}
+	return true // GB999
+}
+
+func gbPropOf(r rune) gbProp {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r == 0x200D:
+		return gbZWJ
+	case r == 0x200C, r >= 0xE0020 && r <= 0xE007F, r >= 0x1F3FB && r <= 0x1F3FF:
+		return gbExtend
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gbRI
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case unicode.Is(extPict, r):
+		return gbPict
+	}
+	return gbOther
+}
+
+// extPict approximates Extended_Pictographic property.
+var extPict = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00A9, 0x00AE, 5},
+		{0x203C, 0x2049, 13},
+		{0x2122, 0x2139, 23},
+		{0x2194, 0x2199, 1},
+		{0x21A9, 0x21AA, 1},
+		{0x231A, 0x231B, 1},
+		{0x2328, 0x23CF, 167},
+		{0x23E9, 0x23F3, 1},
+		{0x23F8, 0x23FA, 1},
+		{0x24C2, 0x25AA, 232},
+		{0x25AB, 0x25B6, 11},
+		{0x25C0, 0x25FB, 59},
+		{0x25FC, 0x25FE, 1},
+		{0x2600, 0x27BF, 1},
+		{0x2934, 0x2935, 1},
+		{0x2B05, 0x2B07, 1},
+		{0x2B1B, 0x2B1C, 1},
+		{0x2B50, 0x2B55, 5},
+		{0x3030, 0x303D, 13},
+		{0x3297, 0x3299, 2},
+	},
+	LatinOffset: 1,
+	R32: []unicode.Range32{
+		{0x1F000, 0x1F1E5, 1},
+		{0x1F200, 0x1F3FA, 1},
+		{0x1F400, 0x1FAFF, 1},
+		{0x1FC00, 0x1FFFD, 1},
+	},
+}
@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+// Hash returns stable content hash of v, as hex encoded sha256.
+// v is either compiled *Schema or schema document.
+//
+// For *Schema, the hash covers its document and documents of
+// schemas referenced transitively using $ref, $recursiveRef and
+// $dynamicRef. Urls are not part of the hash, so same schemas
+// loaded from different locations have same hash.
+//
+// Object keys are hashed in sorted order and numbers are hashed
+// by value. i.e. 1.0 and 1 have same hash.
+func Hash(v any) (string, error) {
+	h := &hasher{
+		sum:    sha256.New(),
+		hashed: map[*Schema]int{},
+		walked: map[*Schema]bool{},
+	}
+	h.w = bufio.NewWriter(h.sum)
+	if sch, ok := v.(*Schema); ok {
+		if err := h.schema(sch); err != nil {
+			return "", err
+		}
+	} else if k := writeHash(v, h.w); k != nil {
+		return "", invalidJSONValue("", k)
+	}
+	if err := h.w.Flush(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.sum.Sum(nil)), nil
+}
+
+type hasher struct {
+	sum    hash.Hash
+	w      *bufio.Writer
+	hashed map[*Schema]int // value is order in which it is hashed
+	walked map[*Schema]bool
+}
+
+// schema hashes document of sch, followed by
+// documents of schemas it references.
+func (h *hasher) schema(sch *Schema) error {
+	if i, ok := h.hashed[sch]; ok {
+		// cycle or already hashed
+		_ = h.w.WriteByte(6)
+		_, _ = fmt.Fprint(h.w, i)
+		return nil
+	}
+	h.hashed[sch] = len(h.hashed)
+	if k := writeHash(sch.doc, h.w); k != nil {
+		return invalidJSONValue(sch.Location, k)
+	}
+	return h.walk(sch)
+}
+
+// walk hashes schemas referenced from sch and its subschemas.
+func (h *hasher) walk(sch *Schema) error {
+	if h.walked[sch] {
+		return nil
+	}
+	h.walked[sch] = true
+	for _, ref := range sch.refs() {
+		if err := h.schema(ref); err != nil {
+			return err
+		}
+	}
+	for _, sub := range sch.subschemas() {
+		if err := h.walk(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --
+
+// InvalidJSONValueError is returned by [Hash] and [TypeOf], if
+// the document contains value which is not valid json.
+type InvalidJSONValueError struct {
+	URL   string // empty if not known
+	Value any
+}
+
+func invalidJSONValue(url string, k ErrorKind) error {
+	var v any
+	if k, ok := k.(*kind.InvalidJsonValue); ok {
+		v = k.Value
+	}
+	return &InvalidJSONValueError{URL: url, Value: v}
+}
+
+func (e *InvalidJSONValueError) Error() string {
+	if e.URL == "" {
+		return fmt.Sprintf("invalid json value of type %T", e.Value)
+	}
+	return fmt.Sprintf("invalid json value of type %T in %q", e.Value, e.URL)
+}
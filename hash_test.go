@@ -0,0 +1,57 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestHash(t *testing.T) {
+	hash := func(base string, remotes invalidRemotes) string {
+		t.Helper()
+		c := jsonschema.NewCompiler()
+		c.UseLoader(jsonschema.SchemeURLLoader{"http": remotes})
+		sch, err := c.Compile(base + "/schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := jsonschema.Hash(sch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	remotes := func(base string, max any) invalidRemotes {
+		return invalidRemotes{
+			base + "/schema.json": map[string]any{
+				"properties": map[string]any{
+					"a":    map[string]any{"$ref": "other.json"},
+					"self": map[string]any{"$ref": "#"},
+				},
+			},
+			base + "/other.json": map[string]any{"maximum": max},
+		}
+	}
+
+	h1 := hash("http://a.com", remotes("http://a.com", 10))
+	if h2 := hash("http://b.com", remotes("http://b.com", 10.0)); h1 != h2 {
+		t.Fatal("hash must not depend on url and number representation")
+	}
+	if h2 := hash("http://a.com", remotes("http://a.com", 11)); h1 == h2 {
+		t.Fatal("hash must include referenced documents")
+	}
+
+	// documents --
+	d1, err := jsonschema.Hash(map[string]any{"type": "string", "minLength": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d2, _ := jsonschema.Hash(map[string]any{"minLength": 1.0, "type": "string"}); d1 != d2 {
+		t.Fatal("document hash must be stable")
+	}
+	_, err = jsonschema.Hash(map[string]any{"type": struct{}{}})
+	if !errors.As(err, new(*jsonschema.InvalidJSONValueError)) {
+		t.Fatalf("want InvalidJSONValueError, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package httploader provides [jsonschema.URLLoader] for http and
+// https urls, with hooks to authenticate requests to schema registries.
+//
+// Importing this package registers a [Loader] without authentication
+// for http and https schemes, using [jsonschema.RegisterDefaultLoader]:
+//
+//	import _ "github.com/santhosh-tekuri/jsonschema/v6/httploader"
+//
+// To authenticate, configure a Loader explicitly:
+//
+//	loader := &httploader.Loader{
+//		Client: httploader.TLSClient(tlsConfig), // mTLS
+//		Auth:   httploader.BearerAuth(tokenFunc),
+//	}
+//	c.UseLoader(jsonschema.SchemeURLLoader{
+//		"file":  jsonschema.FileLoader{},
+//		"https": loader,
+//	})
+package httploader
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func init() {
+	jsonschema.RegisterDefaultLoader("http", &Loader{})
+	jsonschema.RegisterDefaultLoader("https", &Loader{})
+}
+
+// Timeout of the client used by [Loader], if not specified.
+const Timeout = 15 * time.Second
+
+var defaultClient = &http.Client{Timeout: Timeout}
+
+// Loader loads json from http and https urls.
+type Loader struct {
+	// Client used to send requests.
+	// Defaults to client with [Timeout].
+	Client *http.Client
+
+	// Auth is called to authenticate each request before sending,
+	// for example by setting Authorization header. If it returns
+	// error, loading fails with that error.
+	//
+	// Note that Auth is called for every url loaded, including the
+	// urls referenced by schemas. Check req.URL.Host to avoid leaking
+	// credentials to other hosts.
+	Auth func(req *http.Request) error
+}
+
+func (l *Loader) Load(url string) (any, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/schema+json, application/json")
+	if l.Auth != nil {
+		if err := l.Auth(req); err != nil {
+			return nil, err
+		}
+	}
+	client := l.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{url, resp.StatusCode}
+	}
+	return jsonschema.UnmarshalJSON(resp.Body)
+}
+
+// --
+
+// BearerAuth returns Auth function, which sets bearer token
+// returned by token in Authorization header.
+//
+// token is called for each request, so that it can refresh
+// expired tokens. For OIDC or OAuth2, it can be implemented
+// using token source:
+//
+//	httploader.BearerAuth(func() (string, error) {
+//		t, err := ts.Token()
+//		if err != nil {
+//			return "", err
+//		}
+//		return t.AccessToken, nil
+//	})
+func BearerAuth(token func() (string, error)) func(*http.Request) error {
+	return HeaderAuth("Authorization", func() (string, error) {
+		t, err := token()
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + t, nil
+	})
+}
+
+// BasicAuth returns Auth function, which sets given
+// username and password in Authorization header.
+func BasicAuth(username, password string) func(*http.Request) error {
+	return func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	}
+}
+
+// HeaderAuth returns Auth function, which sets header
+// with given name to the value returned by value.
+// This is useful for api keys.
+func HeaderAuth(name string, value func() (string, error)) func(*http.Request) error {
+	return func(req *http.Request) error {
+		v, err := value()
+		if err != nil {
+			return fmt.Errorf("httploader: %s: %w", name, err)
+		}
+		req.Header.Set(name, v)
+		return nil
+	}
+}
+
+// TLSClient returns client with [Timeout], which uses given
+// tls config. It can be used for mutual TLS, by setting
+// cfg.Certificates, and custom CAs, by setting cfg.RootCAs.
+func TLSClient(cfg *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	return &http.Client{
+		Timeout:   Timeout,
+		Transport: transport,
+	}
+}
+
+// --
+
+// StatusError is returned by [Loader],
+// if response status code is not 200.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status code %d", e.URL, e.StatusCode)
+}
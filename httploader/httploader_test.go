@@ -0,0 +1,70 @@
+package httploader_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/httploader"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"type": "string"}`))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestLoader(t *testing.T) {
+	ts := newServer(t)
+	token := "secret"
+	loader := &httploader.Loader{
+		Client: ts.Client(),
+		Auth: httploader.BearerAuth(func() (string, error) {
+			return token, nil
+		}),
+	}
+
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{"https": loader})
+	sch, err := c.Compile(ts.URL + "/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(1); err == nil {
+		t.Fatal("want validation error")
+	}
+
+	// invalid token
+	token = "wrong"
+	_, err = loader.Load(ts.URL + "/schema.json")
+	var serr *httploader.StatusError
+	if !errors.As(err, &serr) || serr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("want StatusError, got %v", err)
+	}
+
+	// auth error
+	errToken := errors.New("token expired")
+	loader.Auth = httploader.BearerAuth(func() (string, error) {
+		return "", errToken
+	})
+	if _, err = loader.Load(ts.URL + "/schema.json"); !errors.Is(err, errToken) {
+		t.Fatalf("want %v, got %v", errToken, err)
+	}
+}
+
+func TestDefaultLoaders(t *testing.T) {
+	loaders := jsonschema.DefaultLoaders()
+	for _, scheme := range []string{"http", "https"} {
+		if _, ok := loaders[scheme].(*httploader.Loader); !ok {
+			t.Errorf("loader not registered for %s", scheme)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package jsonschema
+
+import "slices"
+
+// InstanceSchemas validates v, and returns locations of schemas
+// applied to each value in v, keyed by json-pointer of the value.
+// Locations are absolute and dereferenced, as [Schema.Location],
+// in the order the schemas are evaluated. This can be used to
+// navigate from a value to the schemas constraining it, for example
+// "go to definition" in editors.
+//
+// Like annotations, schemas which failed are not included, such
+// as non-matching subschemas of `anyOf`, and `if` which fails.
+// Boolean schemas are not included.
+//
+// If v is not valid, nil and the validation error are returned.
+func (sch *Schema) InstanceSchemas(v any) (map[string][]string, error) {
+	ue, err := sch.validateWith(v, runOpts{mapSchemas: true})
+	if err != nil {
+		return nil, err
+	}
+	m := map[string][]string{}
+	for _, a := range ue.applied {
+		ptr := jsonPtr(a.vloc)
+		if !slices.Contains(m[ptr], a.sch.Location) {
+			m[ptr] = append(m[ptr], a.sch.Location)
+		}
+	}
+	return m, nil
+}
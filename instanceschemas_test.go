@@ -0,0 +1,64 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestInstanceSchemas(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/schema.json", map[string]any{
+		"properties": map[string]any{
+			"name": map[string]any{"$ref": "#/$defs/name"},
+			"id": map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "integer"},
+					map[string]any{"type": "string"},
+				},
+			},
+			"tags": map[string]any{"items": map[string]any{"type": "string"}},
+		},
+		"$defs": map[string]any{
+			"name": map[string]any{"type": "string"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("http://example.com/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sch.InstanceSchemas(map[string]any{
+		"name": "john",
+		"id":   "x1",
+		"tags": []any{"a", "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ptr, locs := range got {
+		for i, loc := range locs {
+			locs[i] = strings.TrimPrefix(loc, "http://example.com/schema.json")
+		}
+		got[ptr] = locs
+	}
+	want := map[string][]string{
+		"":        {"#"},
+		"/name":   {"#/properties/name", "#/$defs/name"},
+		"/id":     {"#/properties/id", "#/properties/id/anyOf/1"},
+		"/tags":   {"#/properties/tags"},
+		"/tags/0": {"#/properties/tags/items"},
+		"/tags/1": {"#/properties/tags/items"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err := sch.InstanceSchemas(map[string]any{"name": 1}); err == nil {
+		t.Fatal("want error")
+	}
+}
@@ -0,0 +1,84 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Instrumentation observes validation, for metrics and tracing.
+// It is installed using [Compiler.UseInstrumentation].
+//
+// Its methods are called synchronously during validation, and
+// concurrently if schemas are used concurrently. So they should
+// be fast and safe for concurrent use.
+type Instrumentation interface {
+	// ExtensionValidated is called after ext of schema sch validated
+	// a value. failed tells whether ext reported errors.
+	ExtensionValidated(sch *Schema, ext SchemaExt, failed bool)
+}
+
+// UseInstrumentation installs in, in the schemas compiled
+// after this call. nil uninstalls.
+func (c *Compiler) UseInstrumentation(in Instrumentation) {
+	c.instrumentation = in
+}
+
+// --
+
+// ExtensionMetrics is [Instrumentation] that counts invocations
+// and failures of custom keywords, grouped by go type of [SchemaExt].
+// This helps to verify that extensions are actually exercised in
+// production.
+//
+// The zero value is ready to use.
+type ExtensionMetrics struct {
+	mu     sync.Mutex
+	counts map[string]*ExtensionCount
+}
+
+// ExtensionCount is the counters of an extension,
+// reported by [ExtensionMetrics].
+type ExtensionCount struct {
+	// Invocations is number of values validated.
+	Invocations int64
+
+	// Failures is number of values, for which errors are reported.
+	Failures int64
+}
+
+func (m *ExtensionMetrics) ExtensionValidated(_ *Schema, ext SchemaExt, failed bool) {
+	key := fmt.Sprintf("%T", ext)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = map[string]*ExtensionCount{}
+	}
+	c, ok := m.counts[key]
+	if !ok {
+		c = &ExtensionCount{}
+		m.counts[key] = c
+	}
+	c.Invocations++
+	if failed {
+		c.Failures++
+	}
+}
+
+// Counts returns snapshot of counters, keyed by go type
+// of extension, for example "*main.discriminator".
+func (m *ExtensionMetrics) Counts() map[string]ExtensionCount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[string]ExtensionCount, len(m.counts))
+	for k, c := range m.counts {
+		counts[k] = *c
+	}
+	return counts
+}
+
+// Reset zeroes all counters.
+func (m *ExtensionMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts = nil
+}
@@ -0,0 +1,57 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+type evenExt struct{}
+
+func (evenExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil && i%2 != 0 {
+			ctx.AddError(&kind.Group{})
+		}
+	}
+}
+
+func TestExtensionMetrics(t *testing.T) {
+	vocab := &jsonschema.Vocabulary{
+		URL: "http://example.com/vocab/even",
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			if _, ok := obj["x-even"]; ok {
+				return evenExt{}, nil
+			}
+			return nil, nil
+		},
+	}
+	metrics := &jsonschema.ExtensionMetrics{}
+	c := jsonschema.NewCompiler()
+	c.RegisterVocabulary(vocab)
+	c.AssertVocabs()
+	c.UseInstrumentation(metrics)
+	schema := map[string]any{"items": map[string]any{"x-even": true}}
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := []any{json.Number("2"), json.Number("3"), json.Number("4")}
+	if err := sch.Validate(inst); err == nil {
+		t.Fatal("want error")
+	}
+
+	got := metrics.Counts()["jsonschema_test.evenExt"]
+	if want := (jsonschema.ExtensionCount{Invocations: 3, Failures: 1}); got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	metrics.Reset()
+	if n := len(metrics.Counts()); n != 0 {
+		t.Fatalf("got %d counts after reset", n)
+	}
+}
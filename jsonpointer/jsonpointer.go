@@ -0,0 +1,130 @@
+// Package jsonpointer implements JSON Pointer as specified in
+// [RFC 6901], with same semantics as used by package jsonschema.
+//
+// This is useful to manipulate instance, using InstanceLocation
+// reported in validation errors:
+//
+//	v, err := jsonpointer.Eval(inst, jsonpointer.Format(verr.InstanceLocation))
+//
+// [RFC 6901]: https://www.rfc-editor.org/rfc/rfc6901
+package jsonpointer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Escape escapes reference token tok, by replacing
+// '~' with "~0" and '/' with "~1".
+func Escape(tok string) string {
+	tok = strings.ReplaceAll(tok, "~", "~0")
+	tok = strings.ReplaceAll(tok, "/", "~1")
+	return tok
+}
+
+// Unescape is inverse of [Escape]. It returns [*SyntaxError],
+// if '~' is not followed by '0' or '1'.
+func Unescape(tok string) (string, error) {
+	tilde := strings.IndexByte(tok, '~')
+	if tilde == -1 {
+		return tok, nil
+	}
+	orig := tok
+	sb := new(strings.Builder)
+	for {
+		sb.WriteString(tok[:tilde])
+		tok = tok[tilde+1:]
+		if tok == "" {
+			return "", &SyntaxError{orig, "~ must be followed by 0 or 1"}
+		}
+		switch tok[0] {
+		case '0':
+			sb.WriteByte('~')
+		case '1':
+			sb.WriteByte('/')
+		default:
+			return "", &SyntaxError{orig, "~ must be followed by 0 or 1"}
+		}
+		tok = tok[1:]
+		tilde = strings.IndexByte(tok, '~')
+		if tilde == -1 {
+			sb.WriteString(tok)
+			break
+		}
+	}
+	return sb.String(), nil
+}
+
+// Parse returns unescaped reference tokens of ptr.
+// Empty ptr refers to whole document, and returns nil.
+func Parse(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, &SyntaxError{ptr, "must start with /"}
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tok, err := Unescape(tok)
+		if err != nil {
+			return nil, &SyntaxError{ptr, err.(*SyntaxError).Reason}
+		}
+		tokens[i] = tok
+	}
+	return tokens, nil
+}
+
+// Format is inverse of [Parse]. It returns json-pointer
+// with given unescaped reference tokens.
+func Format(tokens []string) string {
+	return Append("", tokens...)
+}
+
+// Append returns json-pointer, by appending
+// unescaped reference tokens to ptr.
+func Append(ptr string, tokens ...string) string {
+	var sb strings.Builder
+	sb.WriteString(ptr)
+	for _, tok := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(Escape(tok))
+	}
+	return sb.String()
+}
+
+// Eval returns value in v referred by ptr. The v must be
+// json value, as returned by encoding/json using `any` type.
+func Eval(v any, ptr string) (any, error) {
+	tokens, err := Parse(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return evalTokens(v, tokens)
+}
+
+// --
+
+// SyntaxError is returned when json-pointer is not valid.
+type SyntaxError struct {
+	Pointer string
+	Reason  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid json-pointer %q: %s", e.Pointer, e.Reason)
+}
+
+// NotFoundError is returned when json-pointer
+// does not refer to any value.
+type NotFoundError struct {
+	Pointer string
+
+	// Parent is the longest prefix of Pointer,
+	// which refers to a value.
+	Parent string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("json-pointer %q not found", e.Pointer)
+}
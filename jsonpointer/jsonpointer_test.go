@@ -0,0 +1,172 @@
+package jsonpointer_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct{ tok, escaped string }{
+		{"abc", "abc"},
+		{"a/b", "a~1b"},
+		{"m~n", "m~0n"},
+		{"~1", "~01"},
+	}
+	for _, test := range tests {
+		if got := jsonpointer.Escape(test.tok); got != test.escaped {
+			t.Errorf("Escape(%q): got %q, want %q", test.tok, got, test.escaped)
+		}
+		got, err := jsonpointer.Unescape(test.escaped)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.tok {
+			t.Errorf("Unescape(%q): got %q, want %q", test.escaped, got, test.tok)
+		}
+	}
+	for _, tok := range []string{"~", "a~2", "~a"} {
+		if _, err := jsonpointer.Unescape(tok); err == nil {
+			t.Errorf("Unescape(%q): want error", tok)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		ptr    string
+		tokens []string
+	}{
+		{"", nil},
+		{"/", []string{""}},
+		{"/a~1b/m~0n/0", []string{"a/b", "m~n", "0"}},
+	}
+	for _, test := range tests {
+		got, err := jsonpointer.Parse(test.ptr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.tokens) {
+			t.Errorf("Parse(%q): got %q, want %q", test.ptr, got, test.tokens)
+		}
+		if got := jsonpointer.Format(test.tokens); got != test.ptr {
+			t.Errorf("Format(%q): got %q, want %q", test.tokens, got, test.ptr)
+		}
+	}
+	for _, ptr := range []string{"a", "/a~"} {
+		var serr *jsonpointer.SyntaxError
+		if _, err := jsonpointer.Parse(ptr); !errors.As(err, &serr) || serr.Pointer != ptr {
+			t.Errorf("Parse(%q): got %v, want SyntaxError", ptr, err)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	if got := jsonpointer.Append("/a", "b/c", "d"); got != "/a/b~1c/d" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestEval(t *testing.T) {
+	doc := map[string]any{
+		"foo": []any{"bar", "baz"},
+		"a/b": 1,
+		"m~n": map[string]any{"": 2},
+	}
+	tests := []struct {
+		ptr  string
+		want any
+	}{
+		{"", doc},
+		{"/foo", doc["foo"]},
+		{"/foo/1", "baz"},
+		{"/a~1b", 1},
+		{"/m~0n/", 2},
+	}
+	for _, test := range tests {
+		got, err := jsonpointer.Eval(doc, test.ptr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Eval(%q): got %v, want %v", test.ptr, got, test.want)
+		}
+	}
+	for _, ptr := range []string{"/bar", "/foo/2", "/foo/-", "/foo/0/x"} {
+		var nerr *jsonpointer.NotFoundError
+		if _, err := jsonpointer.Eval(doc, ptr); !errors.As(err, &nerr) {
+			t.Errorf("Eval(%q): got %v, want NotFoundError", ptr, err)
+		}
+	}
+	_, err := jsonpointer.Eval(doc, "/foo/0/x")
+	if got := err.(*jsonpointer.NotFoundError).Parent; got != "/foo/0" {
+		t.Errorf("Parent: got %q, want %q", got, "/foo/0")
+	}
+}
+
+func TestEvalRelative(t *testing.T) {
+	// example from draft-hha-relative-json-pointer
+	doc := map[string]any{
+		"foo": []any{"bar", "baz", "biz"},
+		"highly": map[string]any{
+			"nested": map[string]any{
+				"objects": true,
+			},
+		},
+	}
+	tests := []struct {
+		loc  []string
+		ptr  string
+		want any
+	}{
+		{[]string{"foo", "1"}, "0", "baz"},
+		{[]string{"foo", "1"}, "1/0", "bar"},
+		{[]string{"foo", "1"}, "0-1", "bar"},
+		{[]string{"foo", "1"}, "0+1", "biz"},
+		{[]string{"foo", "1"}, "2/highly/nested/objects", true},
+		{[]string{"foo", "1"}, "0#", 1},
+		{[]string{"foo", "1"}, "0+1#", 2},
+		{[]string{"foo", "1"}, "1#", "foo"},
+		{[]string{"highly", "nested"}, "0/objects", true},
+		{[]string{"highly", "nested"}, "1/nested/objects", true},
+		{[]string{"highly", "nested"}, "2/foo/0", "bar"},
+		{[]string{"highly", "nested"}, "0#", "nested"},
+		{[]string{"highly", "nested"}, "1#", "highly"},
+	}
+	for _, test := range tests {
+		got, err := jsonpointer.EvalRelative(doc, test.loc, test.ptr)
+		if err != nil {
+			t.Fatalf("EvalRelative(%q, %q): %v", test.loc, test.ptr, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("EvalRelative(%q, %q): got %v, want %v", test.loc, test.ptr, got, test.want)
+		}
+	}
+
+	notFound := []struct {
+		loc []string
+		ptr string
+	}{
+		{[]string{"foo", "1"}, "3"},
+		{[]string{"foo", "1"}, "0+2"},
+		{[]string{"foo", "1"}, "0-2"},
+		{[]string{"foo", "1"}, "2#"},
+		{[]string{"highly", "nested"}, "0+1"},
+		{[]string{"highly", "nested"}, "0/missing"},
+	}
+	for _, test := range notFound {
+		var nerr *jsonpointer.NotFoundError
+		if _, err := jsonpointer.EvalRelative(doc, test.loc, test.ptr); !errors.As(err, &nerr) {
+			t.Errorf("EvalRelative(%q, %q): got %v, want NotFoundError", test.loc, test.ptr, err)
+		}
+	}
+
+	for _, ptr := range []string{"", "a", "01", "0+", "0+01", "0foo", "0#/"} {
+		var serr *jsonpointer.SyntaxError
+		if _, err := jsonpointer.EvalRelative(doc, nil, ptr); !errors.As(err, &serr) {
+			t.Errorf("EvalRelative(%q): got %v, want SyntaxError", ptr, err)
+		}
+	}
+}
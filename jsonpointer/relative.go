@@ -0,0 +1,124 @@
+package jsonpointer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EvalRelative evaluates relative json-pointer ptr in doc, starting
+// from location loc. The loc is given as unescaped reference tokens,
+// such as InstanceLocation of validation error.
+//
+// Index manipulation like "0+1/name" is supported. If ptr ends with
+// '#', it returns index (int) or property name (string) of the value
+// reached, instead of the value.
+//
+// see https://datatracker.ietf.org/doc/html/draft-hha-relative-json-pointer
+func EvalRelative(doc any, loc []string, ptr string) (any, error) {
+	// non-negative-integer
+	numDigits := 0
+	for numDigits < len(ptr) && ptr[numDigits] >= '0' && ptr[numDigits] <= '9' {
+		numDigits++
+	}
+	if numDigits == 0 {
+		return nil, &SyntaxError{ptr, "must start with non-negative integer"}
+	}
+	if numDigits > 1 && ptr[0] == '0' {
+		return nil, &SyntaxError{ptr, "starts with zero"}
+	}
+	up, err := strconv.Atoi(ptr[:numDigits])
+	if err != nil {
+		return nil, &SyntaxError{ptr, "invalid non-negative integer"}
+	}
+	rest := ptr[numDigits:]
+
+	// index-manipulation
+	offset := 0
+	if rest != "" && (rest[0] == '+' || rest[0] == '-') {
+		n := 1
+		for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+		if n == 1 || (n > 2 && rest[1] == '0') {
+			return nil, &SyntaxError{ptr, "invalid index manipulation"}
+		}
+		if offset, err = strconv.Atoi(rest[:n]); err != nil {
+			return nil, &SyntaxError{ptr, "invalid index manipulation"}
+		}
+		rest = rest[n:]
+	}
+	if rest != "" && rest != "#" && !strings.HasPrefix(rest, "/") {
+		return nil, &SyntaxError{ptr, "must be followed by json-pointer or #"}
+	}
+
+	notFound := &NotFoundError{Pointer: ptr, Parent: Format(loc)}
+	if up > len(loc) {
+		return nil, notFound
+	}
+	loc = loc[:len(loc)-up]
+
+	// value and index/name at loc
+	var index any
+	if len(loc) > 0 {
+		parent, err := evalTokens(doc, loc[:len(loc)-1])
+		if err != nil {
+			return nil, notFound
+		}
+		tok := loc[len(loc)-1]
+		if _, ok := parent.([]any); ok {
+			i, err := strconv.Atoi(tok)
+			if err != nil {
+				return nil, notFound
+			}
+			index = i + offset
+			tok = strconv.Itoa(i + offset)
+		} else {
+			if offset != 0 {
+				return nil, notFound
+			}
+			index = tok
+		}
+		loc = append(loc[:len(loc)-1:len(loc)-1], tok)
+	} else if offset != 0 {
+		return nil, notFound
+	}
+
+	v, err := evalTokens(doc, loc)
+	if err != nil {
+		return nil, notFound
+	}
+	if rest == "#" {
+		if index == nil {
+			// root has no index or name
+			return nil, notFound
+		}
+		return index, nil
+	}
+	v, err = Eval(v, rest)
+	if err != nil {
+		return nil, notFound
+	}
+	return v, nil
+}
+
+// evalTokens returns value in v referred by given unescaped tokens.
+func evalTokens(v any, tokens []string) (any, error) {
+	for i, tok := range tokens {
+		switch val := v.(type) {
+		case map[string]any:
+			if pvalue, ok := val[tok]; ok {
+				v = pvalue
+				continue
+			}
+		case []any:
+			if index, err := strconv.Atoi(tok); err == nil {
+				if index >= 0 && index < len(val) {
+					v = val[index]
+					continue
+				}
+			}
+		}
+		return nil, &NotFoundError{Pointer: Format(tokens), Parent: Format(tokens[:i])}
+	}
+	return v, nil
+}
@@ -121,6 +121,7 @@ type RefCycle struct {
 	URL              string
 	KeywordLocation1 string
 	KeywordLocation2 string
+	InstanceLocation string // json-pointer of value being validated
 }
 
 func (*RefCycle) KeywordPath() []string {
@@ -128,7 +129,7 @@ func (*RefCycle) KeywordPath() []string {
 }
 
 func (k *RefCycle) LocalizedString(p *message.Printer) string {
-	return p.Sprintf("both %s and %s resolve to %q causing reference cycle", k.KeywordLocation1, k.KeywordLocation2, k.URL)
+	return p.Sprintf("both %s and %s resolve to %q causing reference cycle, while validating %s", k.KeywordLocation1, k.KeywordLocation2, k.URL, quote(k.InstanceLocation))
 }
 
 // --
@@ -220,6 +221,22 @@ func (k *Format) LocalizedString(p *message.Printer) string {
 
 // --
 
+type DataRef struct {
+	Keyword string // keyword whose value is given by $data
+	Ref     string // relative json-pointer
+	Got     any    // value $data resolved to
+}
+
+func (k *DataRef) KeywordPath() []string {
+	return []string{k.Keyword, "$data"}
+}
+
+func (k *DataRef) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("$data %s resolved to invalid %s value %s", quote(k.Ref), k.Keyword, display(k.Got))
+}
+
+// --
+
 type Reference struct {
 	Keyword string
 	URL     string
@@ -368,20 +385,42 @@ func (k *AdditionalProperties) LocalizedString(p *message.Printer) string {
 
 type PropertyNames struct {
 	Property string
+
+	// Cause is the failed assertion, such as *MinLength, *MaxLength
+	// or *Pattern, if `propertyNames` has only such assertions.
+	// Otherwise it is nil, and failures are reported as causes
+	// of the validation error.
+	Cause interface {
+		KeywordPath() []string
+		LocalizedString(*message.Printer) string
+	}
 }
 
-func (*PropertyNames) KeywordPath() []string {
+func (k *PropertyNames) KeywordPath() []string {
+	if k.Cause != nil {
+		return append([]string{"propertyNames"}, k.Cause.KeywordPath()...)
+	}
 	return []string{"propertyNames"}
 }
 
 func (k *PropertyNames) LocalizedString(p *message.Printer) string {
+	if k.Cause != nil {
+		return p.Sprintf("invalid propertyName %s: %s", quote(k.Property), k.Cause.LocalizedString(p))
+	}
 	return p.Sprintf("invalid propertyName %s", quote(k.Property))
 }
 
 // --
 
 type UniqueItems struct {
+	// Duplicates is the first pair of equal items.
 	Duplicates [2]int
+
+	// Pairs is the pairs of equal items reported, as limited by
+	// ValidateOptions.MaxDuplicates. Each item, equal to some
+	// earlier item, is paired with the first such item. It always
+	// starts with Duplicates.
+	Pairs [][2]int
 }
 
 func (*UniqueItems) KeywordPath() []string {
@@ -389,12 +428,22 @@ func (*UniqueItems) KeywordPath() []string {
 }
 
 func (k *UniqueItems) LocalizedString(p *message.Printer) string {
+	if len(k.Pairs) > 1 {
+		pairs := make([]string, len(k.Pairs))
+		for i, pair := range k.Pairs {
+			pairs[i] = p.Sprintf("%d and %d", pair[0], pair[1])
+		}
+		return p.Sprintf("items at %s are equal", strings.Join(pairs, ", "))
+	}
 	return p.Sprintf("items at %d and %d are equal", k.Duplicates[0], k.Duplicates[1])
 }
 
 // --
 
-type Contains struct{}
+type Contains struct {
+	// Failed is indexes of items, which do not match contains schema.
+	Failed []int
+}
 
 func (*Contains) KeywordPath() []string {
 	return []string{"contains"}
@@ -409,6 +458,9 @@ func (*Contains) LocalizedString(p *message.Printer) string {
 type MinContains struct {
 	Got  []int
 	Want int
+
+	// Failed is indexes of items, which do not match contains schema.
+	Failed []int
 }
 
 func (*MinContains) KeywordPath() []string {
@@ -538,8 +590,7 @@ func (*Minimum) KeywordPath() []string {
 }
 
 func (k *Minimum) LocalizedString(p *message.Printer) string {
-	got, _ := k.Got.Float64()
-	want, _ := k.Want.Float64()
+	got, want := numbers(k.Got, k.Want)
 	return p.Sprintf("minimum: got %v, want %v", got, want)
 }
 
@@ -555,8 +606,7 @@ func (*Maximum) KeywordPath() []string {
 }
 
 func (k *Maximum) LocalizedString(p *message.Printer) string {
-	got, _ := k.Got.Float64()
-	want, _ := k.Want.Float64()
+	got, want := numbers(k.Got, k.Want)
 	return p.Sprintf("maximum: got %v, want %v", got, want)
 }
 
@@ -572,8 +622,7 @@ func (*ExclusiveMinimum) KeywordPath() []string {
 }
 
 func (k *ExclusiveMinimum) LocalizedString(p *message.Printer) string {
-	got, _ := k.Got.Float64()
-	want, _ := k.Want.Float64()
+	got, want := numbers(k.Got, k.Want)
 	return p.Sprintf("exclusiveMinimum: got %v, want %v", got, want)
 }
 
@@ -589,8 +638,7 @@ func (*ExclusiveMaximum) KeywordPath() []string {
 }
 
 func (k *ExclusiveMaximum) LocalizedString(p *message.Printer) string {
-	got, _ := k.Got.Float64()
-	want, _ := k.Want.Float64()
+	got, want := numbers(k.Got, k.Want)
 	return p.Sprintf("exclusiveMaximum: got %v, want %v", got, want)
 }
 
@@ -606,13 +654,51 @@ func (*MultipleOf) KeywordPath() []string {
 }
 
 func (k *MultipleOf) LocalizedString(p *message.Printer) string {
-	got, _ := k.Got.Float64()
-	want, _ := k.Want.Float64()
+	got, want := numbers(k.Got, k.Want)
 	return p.Sprintf("multipleOf: got %v, want %v", got, want)
 }
 
 // --
 
+// SeeAbove is used in output, in place of causes which are
+// same as the ones reported at KeywordLocation.
+type SeeAbove struct {
+	KeywordLocation string
+}
+
+func (*SeeAbove) KeywordPath() []string {
+	return nil
+}
+
+func (k *SeeAbove) LocalizedString(p *message.Printer) string {
+	return p.Sprintf("same as at %s, see above", quote(k.KeywordLocation))
+}
+
+// --
+
+// Custom is for errors of custom keywords, which do not need
+// a dedicated kind. Like built-in kinds, the message is Format
+// formatted with Args using [message.Printer], so that Format
+// can be translated using message catalog.
+type Custom struct {
+	// Keyword is path of the failed keyword relative
+	// to the schema, as unescaped tokens.
+	Keyword []string
+
+	Format string
+	Args   []any
+}
+
+func (k *Custom) KeywordPath() []string {
+	return k.Keyword
+}
+
+func (k *Custom) LocalizedString(p *message.Printer) string {
+	return p.Sprintf(k.Format, k.Args...)
+}
+
+// --
+
 func quote(s string) string {
 	s = fmt.Sprintf("%q", s)
 	s = strings.ReplaceAll(s, `\"`, `"`)
@@ -643,6 +729,46 @@ func display(v any) string {
 	}
 }
 
+// numbers returns got and want as float64, if both are exactly
+// representable. Otherwise returns their exact decimal strings, so
+// that large integers and high precision decimals are not reported
+// rounded.
+func numbers(got, want *big.Rat) (any, any) {
+	f1, exact1 := got.Float64()
+	f2, exact2 := want.Float64()
+	if exact1 && exact2 {
+		return f1, f2
+	}
+	s1, ok1 := decimal(got)
+	s2, ok2 := decimal(want)
+	if !ok1 || !ok2 {
+		return f1, f2
+	}
+	return s1, s2
+}
+
+// decimal returns exact decimal string of r, if
+// r has terminating decimal representation.
+func decimal(r *big.Rat) (string, bool) {
+	// r has terminating decimal, only if denominator
+	// has no prime factors other than 2 and 5.
+	d := new(big.Int).Set(r.Denom())
+	n2 := d.TrailingZeroBits()
+	d.Rsh(d, n2)
+	n5, five, m := uint(0), big.NewInt(5), new(big.Int)
+	for {
+		q, rem := new(big.Int).QuoRem(d, five, m)
+		if rem.Sign() != 0 {
+			break
+		}
+		d, n5 = q, n5+1
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+	return r.FloatString(int(max(n2, n5))), true
+}
+
 func localizedError(err error, p *message.Printer) string {
 	if err, ok := err.(interface{ LocalizedError(*message.Printer) string }); ok {
 		return err.LocalizedError(p)
@@ -0,0 +1,306 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ValidateOptions are options used by [Schema.ValidateWithOptions].
+//
+// These guard against untrusted instances triggering excessive
+// work during validation. Zero value of a limit means no limit.
+type ValidateOptions struct {
+	// MaxDepth is maximum nesting depth of instance.
+	MaxDepth int
+
+	// MaxTotalErrors is maximum number of errors created
+	// during validation. note that errors from subschemas
+	// which are tried and discarded, like in anyOf, are also
+	// counted.
+	MaxTotalErrors int
+
+	// MaxPatternMatchesPerString is maximum number of times
+	// any single string is matched against regular expressions
+	// i.e. with `pattern` and `patternProperties`.
+	MaxPatternMatchesPerString int
+
+	// Parallelism is maximum number of goroutines used to
+	// validate items of arrays against `items`, `prefixItems`
+	// and `additionalItems`. The errors are reported in same
+	// order as sequential validation. Values less than 2 mean
+	// no parallelism.
+	//
+	// Extensions and formats used by the schema must be safe
+	// for concurrent use.
+	Parallelism int
+
+	// MaxReentries is maximum number of times a schema can be
+	// re-entered via references, while validating same value.
+	// Some `$dynamicRef` patterns legitimately re-enter schemas.
+	// Exceeding it fails validation with reference cycle error.
+	// Unlike other limits, zero means no re-entry is allowed.
+	MaxReentries int
+
+	// FailFast stops validation as soon as the instance is
+	// known to be invalid, without building error tree. The
+	// returned [*ValidationError] has no causes. Use this
+	// when only validity matters.
+	FailFast bool
+
+	// MaxDuplicates is maximum number of pairs of equal items,
+	// reported by `uniqueItems` for an array. Zero reports only
+	// the first pair, and negative value reports all pairs,
+	// in a single pass. With FailFast, only the first pair is
+	// found, stopping as soon as it is seen.
+	MaxDuplicates int
+
+	// SortedProperties validates properties of objects in sorted
+	// order of their names, instead of random map iteration order.
+	// This makes outcomes which depend on validation order, like
+	// location in [LimitExceededError] and the order in which
+	// extensions see values, reproducible across runs.
+	SortedProperties bool
+
+	// Formatter overrides formatter installed using
+	// [Compiler.UseFormatter], for the returned error.
+	Formatter Formatter
+}
+
+// limits tracks usage against ValidateOptions during validation.
+// it is shared by all validators of an instance.
+type limits struct {
+	opts           ValidateOptions
+	mu             sync.Mutex // guards numErrors and patternMatches
+	numErrors      int
+	patternMatches map[string]int
+	err            atomic.Pointer[LimitExceededError]
+	workers        chan struct{} // nil if no parallelism
+
+	// boolOnly tells that only validity is needed, so
+	// the error returned need not be wrapped with schema
+	// error. see Schema.IsValid
+	boolOnly bool
+}
+
+// failFastLimits is used by Schema.IsValid. It is safe
+// to share, as it has no limits which track usage.
+var failFastLimits = &limits{opts: ValidateOptions{FailFast: true}, boolOnly: true}
+
+func newLimits(opts *ValidateOptions) *limits {
+	if opts == nil {
+		return nil
+	}
+	o := *opts
+	o.Formatter = nil // not a limit
+	if reflect.ValueOf(o).IsZero() {
+		return nil
+	}
+	l := &limits{opts: o}
+	if opts.MaxPatternMatchesPerString > 0 {
+		l.patternMatches = map[string]int{}
+	}
+	if opts.Parallelism > 1 {
+		// validating goroutine is one of the workers
+		l.workers = make(chan struct{}, opts.Parallelism-1)
+	}
+	return l
+}
+
+func (l *limits) maxReentries() int {
+	if l == nil {
+		return 0
+	}
+	return l.opts.MaxReentries
+}
+
+func (l *limits) failFast() bool {
+	return l != nil && l.opts.FailFast
+}
+
+func (l *limits) maxDuplicates() int {
+	if l == nil || l.opts.FailFast || l.opts.MaxDuplicates == 0 {
+		return 1
+	}
+	return l.opts.MaxDuplicates
+}
+
+func (l *limits) sortedProperties() bool {
+	return l != nil && l.opts.SortedProperties
+}
+
+func (l *limits) exceeded() bool {
+	return l != nil && l.err.Load() != nil
+}
+
+func (l *limits) exceed(limit string, value int, vloc []string) {
+	l.err.CompareAndSwap(nil, &LimitExceededError{
+		Limit:            limit,
+		Value:            value,
+		InstanceLocation: append([]string{}, vloc...),
+	})
+}
+
+// acquireWorker tells whether a new goroutine can
+// be started. If so, releaseWorker must be called
+// when it is done.
+func (l *limits) acquireWorker() bool {
+	if l == nil || l.workers == nil {
+		return false
+	}
+	select {
+	case l.workers <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *limits) releaseWorker() {
+	<-l.workers
+}
+
+func (l *limits) checkDepth(vloc []string) bool {
+	if l == nil || l.opts.MaxDepth <= 0 || len(vloc) <= l.opts.MaxDepth {
+		return true
+	}
+	l.exceed("MaxDepth", l.opts.MaxDepth, vloc)
+	return false
+}
+
+func (l *limits) countError(vloc []string) {
+	if l == nil || l.opts.MaxTotalErrors <= 0 {
+		return
+	}
+	l.mu.Lock()
+	l.numErrors++
+	n := l.numErrors
+	l.mu.Unlock()
+	if n > l.opts.MaxTotalErrors {
+		l.exceed("MaxTotalErrors", l.opts.MaxTotalErrors, vloc)
+	}
+}
+
+func (l *limits) countPatternMatch(s string, vloc []string) bool {
+	if l == nil || l.patternMatches == nil {
+		return true
+	}
+	l.mu.Lock()
+	n := l.patternMatches[s] + 1
+	l.patternMatches[s] = n
+	l.mu.Unlock()
+	if n > l.opts.MaxPatternMatchesPerString {
+		l.exceed("MaxPatternMatchesPerString", l.opts.MaxPatternMatchesPerString, vloc)
+		return false
+	}
+	return true
+}
+
+// --
+
+// LimitExceededError is returned by [Schema.ValidateWithOptions],
+// when one of the limits in [ValidateOptions] is exceeded.
+type LimitExceededError struct {
+	// name of the field in ValidateOptions.
+	Limit string
+
+	// value of the limit.
+	Value int
+
+	// location of the JSON value being validated,
+	// when limit is exceeded.
+	InstanceLocation []string
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("validation limit %s=%d exceeded at %q", e.Limit, e.Value, jsonPtr(e.InstanceLocation))
+}
+
+// --
+
+// CompileLimits are limits used by [Compiler.UseLimits].
+//
+// These guard against untrusted schemas triggering excessive
+// work during compilation. Zero value of a limit means no limit.
+// Usage is counted across all Compile calls of a Compiler.
+type CompileLimits struct {
+	// MaxRefs is maximum number of references resolved
+	// i.e. `$ref`, `$recursiveRef` and `$dynamicRef`.
+	MaxRefs int
+
+	// MaxRemoteLoads is maximum number of resources
+	// loaded using URLLoader.
+	MaxRemoteLoads int
+
+	// MaxSchemas is maximum number of schemas compiled.
+	MaxSchemas int
+
+	// MaxRegexps is maximum number of regular expressions
+	// compiled i.e. with `pattern` and `patternProperties`.
+	MaxRegexps int
+
+	// MaxRegexpLength is maximum length of a regular expression.
+	MaxRegexpLength int
+}
+
+// compileLimits tracks usage against CompileLimits.
+type compileLimits struct {
+	opts       CompileLimits
+	numRefs    int
+	numLoads   int
+	numRegexps int
+}
+
+func (l *compileLimits) check(limit string, value, got int, url string) error {
+	if l == nil || value <= 0 || got <= value {
+		return nil
+	}
+	return &CompileLimitError{Limit: limit, Value: value, URL: url}
+}
+
+func (l *compileLimits) countRef(url string) error {
+	if l == nil {
+		return nil
+	}
+	l.numRefs++
+	return l.check("MaxRefs", l.opts.MaxRefs, l.numRefs, url)
+}
+
+func (l *compileLimits) countLoad(url string) error {
+	if l == nil {
+		return nil
+	}
+	l.numLoads++
+	return l.check("MaxRemoteLoads", l.opts.MaxRemoteLoads, l.numLoads, url)
+}
+
+func (l *compileLimits) countRegexp(pattern string, url string) error {
+	if l == nil {
+		return nil
+	}
+	if err := l.check("MaxRegexpLength", l.opts.MaxRegexpLength, len(pattern), url); err != nil {
+		return err
+	}
+	l.numRegexps++
+	return l.check("MaxRegexps", l.opts.MaxRegexps, l.numRegexps, url)
+}
+
+// --
+
+// CompileLimitError is returned by [Compiler.Compile],
+// when one of the limits in [CompileLimits] is exceeded.
+type CompileLimitError struct {
+	// name of the field in CompileLimits.
+	Limit string
+
+	// value of the limit.
+	Value int
+
+	// location where limit is exceeded.
+	URL string
+}
+
+func (e *CompileLimitError) Error() string {
+	return fmt.Sprintf("compile limit %s=%d exceeded at %q", e.Limit, e.Value, e.URL)
+}
@@ -0,0 +1,408 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+func TestValidateOptions(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"$defs": {
+			"node": {
+				"type": ["object", "string"],
+				"pattern": "^[a-z]+$",
+				"patternProperties": {
+					"^a": true,
+					"^b": true
+				},
+				"additionalProperties": { "$ref": "#/$defs/node" }
+			}
+		},
+		"$ref": "#/$defs/node"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		data  string
+		opts  jsonschema.ValidateOptions
+		limit string // empty if no limit exceeded
+		valid bool
+	}{
+		{"noLimits", `{"x": {"y": {"z": "abc"}}}`, jsonschema.ValidateOptions{}, "", true},
+		{"depthOK", `{"x": {"y": {"z": "abc"}}}`, jsonschema.ValidateOptions{MaxDepth: 3}, "", true},
+		{"depth", `{"x": {"y": {"z": "abc"}}}`, jsonschema.ValidateOptions{MaxDepth: 2}, "MaxDepth", false},
+		{"errorsOK", `{"x": "1", "y": "2"}`, jsonschema.ValidateOptions{MaxTotalErrors: 100}, "", false},
+		{"errors", `{"x": "1", "y": "2", "z": "3"}`, jsonschema.ValidateOptions{MaxTotalErrors: 2}, "MaxTotalErrors", false},
+		{"patternOK", `{"x": "abc"}`, jsonschema.ValidateOptions{MaxPatternMatchesPerString: 2}, "", true},
+		{"pattern", `{"x": "x"}`, jsonschema.ValidateOptions{MaxPatternMatchesPerString: 2}, "MaxPatternMatchesPerString", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sch.ValidateWithOptions(inst, &test.opts)
+			var lerr *jsonschema.LimitExceededError
+			if errors.As(err, &lerr) {
+				if lerr.Limit != test.limit {
+					t.Fatalf("limit got %q, want %q", lerr.Limit, test.limit)
+				}
+				return
+			}
+			if test.limit != "" {
+				t.Fatalf("want LimitExceededError, got %v", err)
+			}
+			if got := err == nil; got != test.valid {
+				t.Fatalf("valid got %v, want %v: %v", got, test.valid, err)
+			}
+		})
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	schema := `{
+		"prefixItems": [{"type": "integer"}],
+		"items": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer"},
+				"tags": {"items": {"type": "string"}}
+			},
+			"unevaluatedProperties": false
+		}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := []any{1}
+	for i := 0; i < 1000; i++ {
+		tags := make([]any, 100)
+		for j := range tags {
+			tags[j] = "tag"
+		}
+		item := map[string]any{"id": i, "tags": tags}
+		switch i % 100 {
+		case 10:
+			item["id"] = "x"
+		case 20:
+			tags[50] = 1
+		case 30:
+			item["extra"] = true
+		}
+		arr = append(arr, item)
+	}
+
+	want := sch.Validate(arr)
+	if want == nil {
+		t.Fatal("want validation error")
+	}
+	if !strings.Contains(want.Error(), "at '/11/id'") {
+		t.Fatalf("want error at correct location:\n%v", want)
+	}
+	for _, n := range []int{2, 4, 16} {
+		got := sch.ValidateWithOptions(arr, &jsonschema.ValidateOptions{Parallelism: n})
+		if got == nil || got.Error() != want.Error() {
+			t.Fatalf("parallelism %d: got\n%v\nwant\n%v", n, got, want)
+		}
+	}
+	if err := sch.ValidateWithOptions([]any{1, map[string]any{}}, &jsonschema.ValidateOptions{Parallelism: 4}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	schema := `{
+		"properties": {
+			"a": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"b": {"contains": {"type": "integer"}, "minContains": 2},
+			"c": {"contains": {"type": "integer"}, "maxContains": 1},
+			"d": {"prefixItems": [true], "contains": {"const": 1}, "unevaluatedItems": false}
+		}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []string{
+		`{"a": "x"}`,
+		`{"a": 1}`,
+		`{"a": true}`,
+		`{"b": [1, "x", 2, 3]}`,
+		`{"b": ["x", 1]}`,
+		`{"c": [1, "x"]}`,
+		`{"c": [1, 2]}`,
+		`{"d": [0, 1, 1]}`,
+		`{"d": [0, 1, 2]}`,
+	}
+	opts := &jsonschema.ValidateOptions{FailFast: true}
+	for _, test := range tests {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := sch.Validate(inst) == nil
+		err = sch.ValidateWithOptions(inst, opts)
+		if got := err == nil; got != want {
+			t.Errorf("%s: got valid %v, want %v", test, got, want)
+		}
+		if verr, ok := err.(*jsonschema.ValidationError); ok && len(verr.Causes) != 0 {
+			t.Errorf("%s: want no causes, got %v", test, verr)
+		}
+		if got := sch.IsValid(inst); got != want {
+			t.Errorf("%s: IsValid got %v, want %v", test, got, want)
+		}
+	}
+}
+
+func TestSortedProperties(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(`{"additionalProperties": {"type": "string"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := map[string]any{}
+	for _, pname := range strings.Split("qwertyuiopasdfghjklzxcvbnm", "") {
+		inst[pname] = 1
+	}
+	opts := &jsonschema.ValidateOptions{MaxTotalErrors: 1, SortedProperties: true}
+	for i := 0; i < 10; i++ {
+		err := sch.ValidateWithOptions(inst, opts)
+		lerr, ok := err.(*jsonschema.LimitExceededError)
+		if !ok {
+			t.Fatalf("want LimitExceededError, got %v", err)
+		}
+		if got := lerr.InstanceLocation; !slices.Equal(got, []string{"b"}) {
+			t.Fatalf("got %q, want %q", got, []string{"b"})
+		}
+	}
+}
+
+func TestMaxDuplicates(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(`{"uniqueItems": true}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := func(inst any, opts *jsonschema.ValidateOptions) [][2]int {
+		t.Helper()
+		err := sch.ValidateWithOptions(inst, opts)
+		verr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			t.Fatalf("want ValidationError, got %v", err)
+		}
+		if len(verr.Causes) == 0 {
+			return nil
+		}
+		k, ok := verr.Causes[0].ErrorKind.(*kind.UniqueItems)
+		if !ok {
+			t.Fatalf("want kind.UniqueItems, got %T", verr.Causes[0].ErrorKind)
+		}
+		if len(k.Pairs) == 0 || k.Pairs[0] != k.Duplicates {
+			t.Fatalf("Pairs %v must start with Duplicates %v", k.Pairs, k.Duplicates)
+		}
+		return k.Pairs
+	}
+
+	small := []any{1, "a", 1.0, "b", "a", 1, true}
+	all := [][2]int{{0, 2}, {1, 4}, {0, 5}}
+	// more than 20 items, to use hashing
+	large := make([]any, 0, 30)
+	for i := 0; i < 10; i++ {
+		large = append(large, i, i+100, i)
+	}
+	largeAll := make([][2]int, 10)
+	for i := range largeAll {
+		largeAll[i] = [2]int{i * 3, i*3 + 2}
+	}
+
+	tests := []struct {
+		inst any
+		opts *jsonschema.ValidateOptions
+		want [][2]int
+	}{
+		{small, nil, all[:1]},
+		{small, &jsonschema.ValidateOptions{MaxDuplicates: 2}, all[:2]},
+		{small, &jsonschema.ValidateOptions{MaxDuplicates: 10}, all},
+		{small, &jsonschema.ValidateOptions{MaxDuplicates: -1}, all},
+		{large, &jsonschema.ValidateOptions{MaxDuplicates: -1}, largeAll},
+		{large, &jsonschema.ValidateOptions{MaxDuplicates: 4}, largeAll[:4]},
+		{small, &jsonschema.ValidateOptions{MaxDuplicates: -1, FailFast: true}, nil},
+	}
+	for i, test := range tests {
+		if got := pairs(test.inst, test.opts); !slices.Equal(got, test.want) {
+			t.Errorf("%d: got %v, want %v", i, got, test.want)
+		}
+	}
+
+	err = sch.ValidateWithOptions(small, &jsonschema.ValidateOptions{MaxDuplicates: -1})
+	if got := err.(*jsonschema.ValidationError).Causes[0].Error(); !strings.Contains(got, "items at 0 and 2, 1 and 4, 0 and 5 are equal") {
+		t.Errorf("got %q", got)
+	}
+	if err := sch.ValidateWithOptions([]any{1, 2}, &jsonschema.ValidateOptions{MaxDuplicates: -1}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaxReentries(t *testing.T) {
+	schema := `{
+		"properties": {
+			"a": {"$ref": "#/$defs/loop"}
+		},
+		"$defs": {
+			"loop": {"allOf": [{"$ref": "#/$defs/loop"}]}
+		}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := map[string]any{"a": 1}
+
+	refCycle := func(err error) *kind.RefCycle {
+		t.Helper()
+		for verr, ok := err.(*jsonschema.ValidationError); ok; verr = verr.Causes[0] {
+			if k, ok := verr.ErrorKind.(*kind.RefCycle); ok {
+				return k
+			}
+			if len(verr.Causes) == 0 {
+				break
+			}
+		}
+		t.Fatalf("want RefCycle error, got %v", err)
+		return nil
+	}
+	k0 := refCycle(sch.Validate(inst))
+	if k0.InstanceLocation != "/a" {
+		t.Fatalf("got instance location %q, want %q", k0.InstanceLocation, "/a")
+	}
+	k3 := refCycle(sch.ValidateWithOptions(inst, &jsonschema.ValidateOptions{MaxReentries: 3}))
+	if n0, n3 := strings.Count(k0.KeywordLocation1, "allOf"), strings.Count(k3.KeywordLocation1, "allOf"); n3 != n0+3 {
+		t.Fatalf("got %d re-entries, want %d", n3, n0+3)
+	}
+}
+
+func TestCompileLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		limits jsonschema.CompileLimits
+		limit  string // empty if no limit exceeded
+	}{
+		{"noLimits", `{"properties": {"a": {"$ref": "#/$defs/a"}}, "$defs": {"a": {"pattern": "^a"}}}`, jsonschema.CompileLimits{}, ""},
+		{"refsOK", `{"allOf": [{"$ref": "#/$defs/a"}, {"$ref": "#/$defs/a"}], "$defs": {"a": true}}`, jsonschema.CompileLimits{MaxRefs: 2}, ""},
+		{"refs", `{"allOf": [{"$ref": "#/$defs/a"}, {"$ref": "#/$defs/a"}], "$defs": {"a": true}}`, jsonschema.CompileLimits{MaxRefs: 1}, "MaxRefs"},
+		{"schemasOK", `{"properties": {"a": true, "b": true}}`, jsonschema.CompileLimits{MaxSchemas: 3}, ""},
+		{"schemas", `{"properties": {"a": true, "b": true}}`, jsonschema.CompileLimits{MaxSchemas: 2}, "MaxSchemas"},
+		{"regexps", `{"pattern": "^a", "patternProperties": {"^b": true}}`, jsonschema.CompileLimits{MaxRegexps: 1}, "MaxRegexps"},
+		{"regexpLength", `{"pattern": "^abcdef$"}`, jsonschema.CompileLimits{MaxRegexpLength: 4}, "MaxRegexpLength"},
+		{"remoteLoadsOK", `{"$ref": "other.json"}`, jsonschema.CompileLimits{MaxRemoteLoads: 2}, ""},
+		{"remoteLoads", `{"$ref": "other.json"}`, jsonschema.CompileLimits{MaxRemoteLoads: 1}, "MaxRemoteLoads"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := jsonschema.NewCompiler()
+			c.UseLimits(test.limits)
+			c.UseLoader(jsonschema.SchemeURLLoader{
+				"http": invalidRemotes{
+					"http://limits.com/other.json":   map[string]any{"$ref": "another.json"},
+					"http://limits.com/another.json": true,
+				},
+			})
+			if err := c.AddResource("http://limits.com/schema.json", schema); err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.Compile("http://limits.com/schema.json")
+			var lerr *jsonschema.CompileLimitError
+			if errors.As(err, &lerr) {
+				if lerr.Limit != test.limit {
+					t.Fatalf("limit got %q, want %q", lerr.Limit, test.limit)
+				}
+				return
+			}
+			if test.limit != "" {
+				t.Fatalf("want CompileLimitError, got %v", err)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestItemsInstanceLocation(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{`{"$schema": "https://json-schema.org/draft/2020-12/schema", "prefixItems": [true, true], "items": {"type": "string"}}`, "/40"},
+		{`{"$schema": "http://json-schema.org/draft-07/schema#", "items": [true, true], "additionalItems": {"type": "string"}}`, "/40"},
+	}
+	arr := []any{1, 2}
+	for i := 2; i < 50; i++ {
+		arr = append(arr, "x")
+	}
+	arr[40] = 1
+	for i, test := range tests {
+		c := jsonschema.NewCompiler()
+		if err := c.AddResourceJSON("schema.json", []byte(test.schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, opts := range []*jsonschema.ValidateOptions{nil, {Parallelism: 4}} {
+			err := sch.ValidateWithOptions(arr, opts)
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("%d: want ValidationError, got %v", i, err)
+			}
+			for len(verr.Causes) > 0 {
+				verr = verr.Causes[0]
+			}
+			if got := "/" + strings.Join(verr.InstanceLocation, "/"); got != test.want {
+				t.Errorf("%d: got %q, want %q", i, got, test.want)
+			}
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package jsonschema
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +14,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 // URLLoader knows how to load json from given url.
@@ -20,6 +23,19 @@ type URLLoader interface {
 	Load(url string) (any, error)
 }
 
+// URLLoaderFunc is an adapter to allow the use of
+// ordinary functions as [URLLoader].
+type URLLoaderFunc func(url string) (any, error)
+
+func (f URLLoaderFunc) Load(url string) (any, error) {
+	return f(url)
+}
+
+// LoaderMiddleware wraps a [URLLoader], to add cross-cutting
+// behavior such as logging, metrics or url rewriting.
+// see [Compiler.UseLoaderMiddleware].
+type LoaderMiddleware func(URLLoader) URLLoader
+
 // --
 
 // FileLoader loads json file url.
@@ -75,6 +91,64 @@ func (l SchemeURLLoader) Load(url string) (any, error) {
 
 // --
 
+var defaultLoaders = struct {
+	mu      sync.RWMutex
+	loaders SchemeURLLoader
+}{loaders: SchemeURLLoader{"file": FileLoader{}}}
+
+// RegisterDefaultLoader registers loader for given url scheme,
+// in the package-level registry used by compilers which did not
+// call [Compiler.UseLoader]. If loader is nil, the scheme is
+// unregistered. Only "file" scheme is registered by default.
+//
+// It is safe to call from init functions, so that packages can
+// register loaders when imported, like database/sql drivers:
+//
+//	import _ "example.com/jsonschema/httploader"
+func RegisterDefaultLoader(scheme string, loader URLLoader) {
+	defaultLoaders.mu.Lock()
+	defer defaultLoaders.mu.Unlock()
+	if loader == nil {
+		delete(defaultLoaders.loaders, scheme)
+	} else {
+		defaultLoaders.loaders[scheme] = loader
+	}
+}
+
+// DefaultLoaders returns copy of the loaders registered
+// using [RegisterDefaultLoader].
+func DefaultLoaders() SchemeURLLoader {
+	defaultLoaders.mu.RLock()
+	defer defaultLoaders.mu.RUnlock()
+	loaders := make(SchemeURLLoader, len(defaultLoaders.loaders))
+	for scheme, l := range defaultLoaders.loaders {
+		loaders[scheme] = l
+	}
+	return loaders
+}
+
+// registeredLoader is the default [URLLoader] of compiler,
+// which delegates to the loaders in package-level registry.
+// The registry is consulted on each load, so that loaders
+// registered after compiler creation are also used.
+type registeredLoader struct{}
+
+func (registeredLoader) Load(url string) (any, error) {
+	u, err := gourl.Parse(url)
+	if err != nil {
+		return nil, err
+	}
+	defaultLoaders.mu.RLock()
+	l, ok := defaultLoaders.loaders[u.Scheme]
+	defaultLoaders.mu.RUnlock()
+	if !ok {
+		return nil, &UnsupportedURLSchemeError{u.String()}
+	}
+	return l.Load(url)
+}
+
+// --
+
 //go:embed metaschemas
 var metaFS embed.FS
 
@@ -129,6 +203,13 @@ func loadMeta(url string) (any, error) {
 type defaultLoader struct {
 	docs   map[url]any // docs loaded so far
 	loader URLLoader
+	mws    []LoaderMiddleware
+	cache  *ResourceCache // nil if not shared
+	limits *compileLimits // nil if no limits
+	policy func(url string) error
+	loaded []url // urls loaded using loader
+
+	dialects map[url]*Dialect // custom dialects registered
 }
 
 func (l *defaultLoader) add(url url, doc any) bool {
@@ -143,7 +224,11 @@ func (l *defaultLoader) load(url url) (any, error) {
 	if doc, ok := l.docs[url]; ok {
 		return doc, nil
 	}
-	doc, err := loadMeta(url.String())
+	// embedded metaschemas are cached with different key, so that
+	// documents cached from loader are not taken as metaschemas
+	doc, err := l.cache.load("embedded:"+url.String(), func() (any, error) {
+		return loadMeta(url.String())
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -154,14 +239,58 @@ func (l *defaultLoader) load(url url) (any, error) {
 	if l.loader == nil {
 		return nil, &LoadURLError{url.String(), errors.New("no URLLoader set")}
 	}
-	doc, err = l.loader.Load(url.String())
+	if l.policy != nil {
+		if err := l.policy(url.String()); err != nil {
+			return nil, &PolicyError{URL: url.String(), Err: err}
+		}
+	}
+	if err := l.limits.countLoad(url.String()); err != nil {
+		return nil, err
+	}
+	loader := l.loader
+	for i := len(l.mws) - 1; i >= 0; i-- {
+		loader = l.mws[i](loader)
+	}
+	doc, err = l.cache.load(url.String(), func() (any, error) {
+		return loader.Load(url.String())
+	})
 	if err != nil {
 		return nil, &LoadURLError{URL: url.String(), Err: err}
 	}
 	l.add(url, doc)
+	l.loaded = append(l.loaded, url)
 	return doc, nil
 }
 
+// LoadedResource describes a resource loaded using [URLLoader].
+// see [Compiler.LoadedResources].
+type LoadedResource struct {
+	// absolute url of the resource.
+	URL string
+
+	// hex encoded sha256 hash of the canonical json
+	// encoding of the resource.
+	SHA256 string
+
+	// size in bytes of the canonical json encoding of the resource.
+	Size int
+}
+
+// canonical json encoding is the output of [json.Marshal],
+// which sorts object keys and does not indent.
+func newLoadedResource(u url, doc any) (LoadedResource, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return LoadedResource{}, err
+	}
+	sum := sha256.Sum256(b)
+	return LoadedResource{
+		URL:    u.String(),
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   len(b),
+	}, nil
+}
+
 func (l *defaultLoader) getDraft(up urlPtr, doc any, defaultDraft *Draft, cycle map[url]struct{}) (*Draft, error) {
 	obj, ok := doc.(map[string]any)
 	if !ok {
@@ -179,6 +308,9 @@ func (l *defaultLoader) getDraft(up urlPtr, doc any, defaultDraft *Draft, cycle
 		return nil, &InvalidMetaSchemaURLError{up.String(), err}
 	}
 	schUrl := url(sch)
+	if d, ok := l.dialects[schUrl]; ok {
+		return d.Draft, nil
+	}
 	if up.ptr.isEmpty() && schUrl == up.url {
 		return nil, &UnsupportedDraftError{schUrl.String()}
 	}
@@ -210,6 +342,9 @@ func (l *defaultLoader) getMetaVocabs(doc any, draft *Draft, vocabularies map[st
 		return nil, &ParseURLError{sch, err}
 	}
 	schUrl := url(sch)
+	if d, ok := l.dialects[schUrl]; ok {
+		return d.vocabNames(vocabularies)
+	}
 	doc, err := l.load(schUrl)
 	if err != nil {
 		return nil, err
@@ -230,6 +365,23 @@ func (e *LoadURLError) Error() string {
 
 // --
 
+// PolicyError is returned when load policy
+// rejects loading of a url. see [Compiler.UseLoadPolicy].
+type PolicyError struct {
+	URL string
+	Err error
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("loading %q denied by policy: %v", e.URL, e.Err)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// --
+
 type UnsupportedURLSchemeError struct {
 	url string
 }
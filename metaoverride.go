@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OverrideMetaschema replaces the embedded metaschema with given url,
+// by doc, in validating schemas compiled by c. This can be used to
+// enforce house rules on every schema compiled, for example to
+// require `title` in all schemas:
+//
+//	c.OverrideMetaschema("https://json-schema.org/draft/2020-12/schema", map[string]any{
+//		"$schema":        "https://json-schema.org/draft/2020-12/schema",
+//		"$id":            "https://json-schema.org/draft/2020-12/schema",
+//		"$dynamicAnchor": "meta",
+//		"allOf": []any{
+//			map[string]any{"$ref": "meta/core"},
+//			map[string]any{"$ref": "meta/applicator"},
+//			map[string]any{"$ref": "meta/validation"},
+//		},
+//		"required": []any{"title"},
+//	})
+//
+// The url can also be the metaschema of a vocabulary, such as
+// "https://json-schema.org/draft/2020-12/meta/validation".
+// The doc can refer to other embedded metaschemas, but references to
+// url refer to doc itself. It returns [*MetaschemaOverrideError],
+// if doc fails to compile, or if it applies itself to same value
+// in cycle, which would never terminate.
+//
+// Only validation of schemas is affected. The vocabularies are
+// still decided by the embedded metaschema.
+//
+// NOTE: must be called before compiling any schemas.
+func (c *Compiler) OverrideMetaschema(url string, doc any) error {
+	uf, err := absolute(url)
+	if err != nil {
+		return err
+	}
+	if !isMeta(string(uf.url)) {
+		return &MetaschemaOverrideError{URL: url, Err: errors.New("not an embedded metaschema")}
+	}
+
+	// compiled separately, so that doc is validated
+	// against the embedded metaschemas
+	mc := NewCompiler()
+	mc.AssertFormat()
+	mc.roots.loader.add(uf.url, doc)
+	sch, err := mc.Compile(string(uf.url))
+	if err != nil {
+		return &MetaschemaOverrideError{URL: url, Err: err}
+	}
+	if loc := inPlaceCycle(sch); loc != "" {
+		return &MetaschemaOverrideError{URL: url, Err: fmt.Errorf("schema %q applies itself to same value", loc)}
+	}
+	c.roots.metaschemas[uf.url] = sch
+	return nil
+}
+
+// inPlaceCycle returns location of a schema, which is applied to
+// same value again via in-place applicators such as `$ref` and `allOf`,
+// without consuming any part of the value. It returns "", if none.
+func inPlaceCycle(sch *Schema) string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[*Schema]int{}
+	var visit func(s *Schema) string
+	visit = func(s *Schema) string {
+		if s == nil {
+			return ""
+		}
+		switch state[s] {
+		case visiting:
+			return s.Location
+		case visited:
+			return ""
+		}
+		state[s] = visiting
+		next := []*Schema{s.Ref, s.RecursiveRef, s.Not, s.If, s.Then, s.Else}
+		if s.DynamicRef != nil {
+			next = append(next, s.DynamicRef.Ref)
+		}
+		next = append(next, s.AllOf...)
+		next = append(next, s.AnyOf...)
+		next = append(next, s.OneOf...)
+		for _, ds := range s.DependentSchemas {
+			next = append(next, ds)
+		}
+		for _, n := range next {
+			if loc := visit(n); loc != "" {
+				return loc
+			}
+		}
+		state[s] = visited
+		return ""
+	}
+	return visit(sch)
+}
+
+// --
+
+// MetaschemaOverrideError is returned by [Compiler.OverrideMetaschema],
+// if the metaschema cannot be overridden.
+type MetaschemaOverrideError struct {
+	URL string
+	Err error
+}
+
+func (e *MetaschemaOverrideError) Error() string {
+	return fmt.Sprintf("cannot override metaschema %q: %v", e.URL, e.Err)
+}
+
+func (e *MetaschemaOverrideError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,472 @@
+// Package migrate rewrites json-schema documents from older drafts
+// to draft 2020-12.
+//
+// Following are migrated mechanically:
+//   - `$schema` of standard drafts
+//   - `id` to `$id`, and fragment only ids to `$anchor`
+//   - boolean `exclusiveMinimum` and `exclusiveMaximum` to numbers
+//   - `definitions` to `$defs`
+//   - `dependencies` to `dependentRequired` and `dependentSchemas`
+//   - array form of `items` to `prefixItems`, `additionalItems` to `items`
+//   - local `$ref` pointing into renamed keywords
+//
+// Unknown keywords are preserved as is. Changes that cannot be done
+// mechanically, or which need review are reported as [Issue].
+package migrate
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+)
+
+// Issue is a change which could not be done mechanically
+// or which needs review.
+type Issue struct {
+	// Location is json-pointer of the value in source document.
+	Location string
+
+	// Message describes the issue.
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("at %q: %s", i.Location, i.Message)
+}
+
+// Migrate rewrites schema document doc to draft to.
+// Schemas without `$schema` are assumed to be in draft from.
+// Currently only [jsonschema.Draft2020] is supported as target.
+//
+// The doc is not modified.
+func Migrate(doc any, from, to *jsonschema.Draft) (any, []Issue, error) {
+	if to != jsonschema.Draft2020 {
+		return nil, nil, &UnsupportedTargetError{to}
+	}
+	if from == nil {
+		from = jsonschema.Draft2020
+	}
+	m := &migrator{}
+	v := m.schema(doc, "", from, doc)
+	slices.SortStableFunc(m.issues, func(a, b Issue) int {
+		return strings.Compare(a.Location, b.Location)
+	})
+	return v, m.issues, nil
+}
+
+// --
+
+// UnsupportedTargetError is returned by [Migrate],
+// if migration to given draft is not supported.
+type UnsupportedTargetError struct {
+	Draft *jsonschema.Draft
+}
+
+func (e *UnsupportedTargetError) Error() string {
+	return fmt.Sprintf("migration to %v is not supported", e.Draft)
+}
+
+// --
+
+var (
+	schemaKeywords = []string{
+		"not", "additionalProperties", "additionalItems", "contains", "propertyNames",
+		"if", "then", "else", "unevaluatedItems", "unevaluatedProperties", "contentSchema",
+	}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaMapKeywords   = []string{"properties", "patternProperties", "definitions", "$defs", "dependentSchemas"}
+
+	// keywords which are ignored by old drafts, when used along with $ref.
+	refSiblings = []string{
+		"$ref", "$schema", "definitions", "$defs",
+		"title", "description", "$comment", "default", "examples",
+	}
+)
+
+type migrator struct {
+	issues []Issue
+}
+
+func (m *migrator) report(ptr, format string, args ...any) {
+	m.issues = append(m.issues, Issue{Location: ptr, Message: fmt.Sprintf(format, args...)})
+}
+
+// schema migrates schema v at ptr in draft d.
+// res is the resource containing v, in source document.
+func (m *migrator) schema(v any, ptr string, d *jsonschema.Draft, res any) any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return clone(v)
+	}
+
+	if s, ok := obj["$schema"].(string); ok {
+		if sd := draftFromURL(s); sd != nil {
+			d = sd
+		} else {
+			m.report(ptr+"/$schema", "custom $schema %q is not migrated", s)
+		}
+	}
+	if hasID(obj, d) {
+		res = obj
+	}
+
+	// migrate subschemas --
+	out := make(map[string]any, len(obj))
+	for _, k := range sortedKeys(obj) {
+		v, kptr := obj[k], jsonpointer.Append(ptr, k)
+		switch {
+		case slices.Contains(schemaKeywords, k):
+			out[k] = m.schema(v, kptr, d, res)
+		case slices.Contains(schemaArrayKeywords, k):
+			out[k] = m.schemaArray(v, kptr, d, res)
+		case slices.Contains(schemaMapKeywords, k):
+			out[k] = m.schemaMap(v, kptr, d, res)
+		case k == "items":
+			if _, ok := v.([]any); ok {
+				out[k] = m.schemaArray(v, kptr, d, res)
+			} else {
+				out[k] = m.schema(v, kptr, d, res)
+			}
+		case k == "dependencies":
+			deps, ok := v.(map[string]any)
+			if !ok {
+				out[k] = clone(v)
+				break
+			}
+			outDeps := make(map[string]any, len(deps))
+			for pname, dep := range deps {
+				if _, ok := dep.([]any); ok {
+					outDeps[pname] = clone(dep)
+				} else {
+					outDeps[pname] = m.schema(dep, jsonpointer.Append(kptr, pname), d, res)
+				}
+			}
+			out[k] = outDeps
+		default:
+			out[k] = clone(v)
+		}
+	}
+
+	if d == jsonschema.Draft2020 {
+		return out
+	}
+	if _, ok := obj["$schema"].(string); ok {
+		out["$schema"] = jsonschema.Draft2020.String()
+	}
+
+	// migrate keywords --
+	if d.Version() < 2019 {
+		if _, ok := obj["$ref"]; ok {
+			var ignored []string
+			for _, k := range sortedKeys(obj) {
+				if !slices.Contains(refSiblings, k) {
+					ignored = append(ignored, k)
+				}
+			}
+			if len(ignored) > 0 {
+				m.report(ptr, "keywords %s alongside $ref are ignored in %v, but apply in %v",
+					strings.Join(ignored, ", "), d, jsonschema.Draft2020)
+			}
+		}
+		m.migrateID(out, ptr, d)
+		if d.Version() == 4 {
+			m.migrateExclusive(out, ptr, "exclusiveMinimum", "minimum")
+			m.migrateExclusive(out, ptr, "exclusiveMaximum", "maximum")
+		}
+		m.rename(out, ptr, "definitions", "$defs")
+		m.migrateDependencies(out, ptr)
+	}
+	if items, ok := out["items"]; ok {
+		if _, ok := items.([]any); ok {
+			m.rename(out, ptr, "items", "prefixItems")
+			if additional, ok := out["additionalItems"]; ok {
+				delete(out, "additionalItems")
+				out["items"] = additional
+			}
+		}
+	}
+	if _, ok := obj["items"].([]any); !ok {
+		// additionalItems has no effect, if items is not array
+		delete(out, "additionalItems")
+	}
+	if d.Version() == 2019 {
+		m.migrateRecursive(out, ptr)
+	}
+	if ref, ok := out["$ref"].(string); ok {
+		out["$ref"] = m.migrateRef(ref, ptr+"/$ref", d, res)
+	}
+	return out
+}
+
+func (m *migrator) schemaArray(v any, ptr string, d *jsonschema.Draft, res any) any {
+	arr, ok := v.([]any)
+	if !ok {
+		return clone(v)
+	}
+	out := make([]any, len(arr))
+	for i, item := range arr {
+		out[i] = m.schema(item, ptr+"/"+strconv.Itoa(i), d, res)
+	}
+	return out
+}
+
+func (m *migrator) schemaMap(v any, ptr string, d *jsonschema.Draft, res any) any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return clone(v)
+	}
+	out := make(map[string]any, len(obj))
+	for k, v := range obj {
+		out[k] = m.schema(v, jsonpointer.Append(ptr, k), d, res)
+	}
+	return out
+}
+
+// rename renames keyword from to keyword to.
+func (m *migrator) rename(obj map[string]any, ptr, from, to string) {
+	v, ok := obj[from]
+	if !ok {
+		return
+	}
+	if _, ok := obj[to]; ok {
+		m.report(ptr, "both %s and %s are present, %s is not migrated", from, to, from)
+		return
+	}
+	delete(obj, from)
+	obj[to] = v
+}
+
+func (m *migrator) migrateID(obj map[string]any, ptr string, d *jsonschema.Draft) {
+	idKw := "$id"
+	if d.Version() == 4 {
+		idKw = "id"
+	}
+	id, ok := obj[idKw].(string)
+	if !ok {
+		return
+	}
+	delete(obj, idKw)
+	base, frag, _ := strings.Cut(id, "#")
+	if base != "" {
+		obj["$id"] = base
+	}
+	if frag != "" {
+		if strings.HasPrefix(frag, "/") {
+			m.report(jsonpointer.Append(ptr, idKw), "json-pointer fragment in %s %q is dropped", idKw, id)
+		} else {
+			obj["$anchor"] = frag
+		}
+	}
+}
+
+func (m *migrator) migrateExclusive(obj map[string]any, ptr, kw, limitKw string) {
+	b, ok := obj[kw].(bool)
+	if !ok {
+		return
+	}
+	delete(obj, kw)
+	if !b {
+		return
+	}
+	limit, ok := obj[limitKw]
+	if !ok {
+		m.report(ptr+"/"+kw, "%s is removed, because %s is missing", kw, limitKw)
+		return
+	}
+	delete(obj, limitKw)
+	obj[kw] = limit
+}
+
+func (m *migrator) migrateDependencies(obj map[string]any, ptr string) {
+	deps, ok := obj["dependencies"].(map[string]any)
+	if !ok {
+		return
+	}
+	_, hasReqd := obj["dependentRequired"]
+	_, hasSchemas := obj["dependentSchemas"]
+	if hasReqd || hasSchemas {
+		m.report(ptr, "dependencies is not migrated, because dependentRequired or dependentSchemas is present")
+		return
+	}
+	delete(obj, "dependencies")
+	reqd, schemas := map[string]any{}, map[string]any{}
+	for pname, dep := range deps {
+		if _, ok := dep.([]any); ok {
+			reqd[pname] = dep
+		} else {
+			schemas[pname] = dep
+		}
+	}
+	if len(reqd) > 0 {
+		obj["dependentRequired"] = reqd
+	}
+	if len(schemas) > 0 {
+		obj["dependentSchemas"] = schemas
+	}
+}
+
+func (m *migrator) migrateRecursive(obj map[string]any, ptr string) {
+	if anchor, ok := obj["$recursiveAnchor"].(bool); ok {
+		delete(obj, "$recursiveAnchor")
+		if anchor {
+			obj["$dynamicAnchor"] = "meta"
+			m.report(ptr+"/$recursiveAnchor", "$recursiveAnchor is replaced with $dynamicAnchor %q, review its usage", "meta")
+		}
+	}
+	if ref, ok := obj["$recursiveRef"].(string); ok {
+		delete(obj, "$recursiveRef")
+		if ref == "#" {
+			obj["$dynamicRef"] = "#meta"
+			m.report(ptr+"/$recursiveRef", "$recursiveRef is replaced with $dynamicRef %q, review its usage", "#meta")
+		} else {
+			obj["$dynamicRef"] = ref
+			m.report(ptr+"/$recursiveRef", "$recursiveRef %q is replaced with $dynamicRef", ref)
+		}
+	}
+}
+
+// migrateRef rewrites json-pointer in ref, if it points into renamed keywords.
+func (m *migrator) migrateRef(ref, ptr string, d *jsonschema.Draft, res any) string {
+	base, frag, ok := strings.Cut(ref, "#")
+	if !ok || !strings.HasPrefix(frag, "/") {
+		return ref
+	}
+	if base != "" {
+		if frag != migratePtr(nil, frag, d) {
+			m.report(ptr, "$ref %q into other document is not migrated", ref)
+		}
+		return ref
+	}
+	return "#" + migratePtr(res, frag, d)
+}
+
+// migratePtr returns json-pointer ptr migrated, by walking the
+// source document starting from resource res. If res is nil,
+// renamed keywords are mapped without looking at document.
+func migratePtr(res any, ptr string, d *jsonschema.Draft) string {
+	tokens, err := jsonpointer.Parse(ptr)
+	if err != nil {
+		return ptr
+	}
+	var out []string
+	node, atSchema := res, true
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if !atSchema {
+			// inside array or map of schemas
+			out = append(out, tok)
+			node, atSchema = child(node, tok), true
+			continue
+		}
+		obj, _ := node.(map[string]any)
+		next := child(node, tok)
+		switch tok {
+		case "definitions":
+			if d.Version() < 2019 {
+				tok = "$defs"
+			}
+			atSchema = false
+		case "dependencies":
+			if d.Version() < 2019 && i+1 < len(tokens) {
+				if _, ok := child(next, tokens[i+1]).([]any); ok {
+					tok = "dependentRequired"
+				} else {
+					tok = "dependentSchemas"
+				}
+			}
+			atSchema = false
+		case "items":
+			if _, ok := next.([]any); ok || (res == nil && i+1 < len(tokens) && isIndex(tokens[i+1])) {
+				tok = "prefixItems"
+				atSchema = false
+			}
+		case "additionalItems":
+			if _, ok := obj["items"].([]any); ok || res == nil {
+				tok = "items"
+			}
+		case "properties", "patternProperties", "$defs", "dependentSchemas",
+			"allOf", "anyOf", "oneOf", "prefixItems":
+			atSchema = false
+		default:
+			if !slices.Contains(schemaKeywords, tok) {
+				// not a subschema, copy rest as is
+				return jsonpointer.Format(append(out, tokens[i:]...))
+			}
+		}
+		out = append(out, tok)
+		node = next
+	}
+	return jsonpointer.Format(out)
+}
+
+// --
+
+func draftFromURL(s string) *jsonschema.Draft {
+	s = strings.TrimSuffix(s, "#")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "http://"), "https://")
+	for _, d := range []*jsonschema.Draft{jsonschema.Draft4, jsonschema.Draft6, jsonschema.Draft7, jsonschema.Draft2019, jsonschema.Draft2020} {
+		u := strings.TrimPrefix(strings.TrimPrefix(d.String(), "http://"), "https://")
+		if s == u {
+			return d
+		}
+	}
+	return nil
+}
+
+func hasID(obj map[string]any, d *jsonschema.Draft) bool {
+	idKw := "$id"
+	if d.Version() == 4 {
+		idKw = "id"
+	}
+	id, ok := obj[idKw].(string)
+	return ok && !strings.HasPrefix(id, "#")
+}
+
+func child(v any, tok string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return v[tok]
+	case []any:
+		if i, err := strconv.Atoi(tok); err == nil && i >= 0 && i < len(v) {
+			return v[i]
+		}
+	}
+	return nil
+}
+
+func isIndex(tok string) bool {
+	_, err := strconv.Atoi(tok)
+	return err == nil
+}
+
+func clone(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, v := range v {
+			out[k] = clone(v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = clone(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
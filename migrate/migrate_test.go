@@ -0,0 +1,128 @@
+package migrate_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/migrate"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   *jsonschema.Draft
+		schema string
+		want   string
+		issues []string // locations of issues
+	}{
+		{
+			name: "draft4",
+			from: jsonschema.Draft4,
+			schema: `{
+				"$schema": "http://json-schema.org/draft-04/schema#",
+				"id": "http://example.com/schema.json",
+				"properties": {
+					"age": { "minimum": 0, "exclusiveMinimum": true, "maximum": 150, "exclusiveMaximum": false },
+					"tags": { "items": [{ "$ref": "#/definitions/tag" }], "additionalItems": false },
+					"name": { "$ref": "#/properties/tags/items/0" }
+				},
+				"dependencies": { "a": ["b"], "c": { "required": ["d"] } },
+				"definitions": { "tag": { "id": "#tag", "type": "string" } },
+				"x-unknown": { "definitions": 1 }
+			}`,
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "http://example.com/schema.json",
+				"properties": {
+					"age": { "exclusiveMinimum": 0, "maximum": 150 },
+					"tags": { "prefixItems": [{ "$ref": "#/$defs/tag" }], "items": false },
+					"name": { "$ref": "#/properties/tags/prefixItems/0" }
+				},
+				"dependentRequired": { "a": ["b"] },
+				"dependentSchemas": { "c": { "required": ["d"] } },
+				"$defs": { "tag": { "$anchor": "tag", "type": "string" } },
+				"x-unknown": { "definitions": 1 }
+			}`,
+		},
+		{
+			name:   "draft7RefSiblings",
+			from:   jsonschema.Draft7,
+			schema: `{"$ref": "other.json#/definitions/a", "type": "string", "title": "t"}`,
+			want:   `{"$ref": "other.json#/definitions/a", "type": "string", "title": "t"}`,
+			issues: []string{"", "/$ref"},
+		},
+		{
+			name: "draft2019",
+			from: jsonschema.Draft2019,
+			schema: `{
+				"$schema": "https://json-schema.org/draft/2019-09/schema",
+				"$recursiveAnchor": true,
+				"items": { "$recursiveRef": "#" },
+				"additionalItems": false,
+				"$defs": { "a": true }
+			}`,
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$dynamicAnchor": "meta",
+				"items": { "$dynamicRef": "#meta" },
+				"$defs": { "a": true }
+			}`,
+			issues: []string{"/$recursiveAnchor", "/items/$recursiveRef"},
+		},
+		{
+			name:   "draft2020",
+			from:   jsonschema.Draft2020,
+			schema: `{"prefixItems": [true], "items": false, "definitions": {}}`,
+			want:   `{"prefixItems": [true], "items": false, "definitions": {}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := jsonschema.UnmarshalJSON(strings.NewReader(test.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, issues, err := migrate.Migrate(schema, test.from, jsonschema.Draft2020)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				b, _ := json.MarshalIndent(got, "", "  ")
+				t.Fatalf("got:\n%s", b)
+			}
+			var locs []string
+			for _, issue := range issues {
+				locs = append(locs, issue.Location)
+			}
+			if !reflect.DeepEqual(locs, test.issues) {
+				t.Fatalf("issues: got %v, want %v", issues, test.issues)
+			}
+
+			// migrated schema must compile
+			c := jsonschema.NewCompiler()
+			if err := c.AddResource("http://example.com/schema.json", got); err != nil {
+				t.Fatal(err)
+			}
+			c.UseLoader(jsonschema.SchemeURLLoader{})
+			if test.name != "draft7RefSiblings" {
+				if _, err := c.Compile("http://example.com/schema.json"); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateUnsupportedTarget(t *testing.T) {
+	_, _, err := migrate.Migrate(true, jsonschema.Draft4, jsonschema.Draft7)
+	if _, ok := err.(*migrate.UnsupportedTargetError); !ok {
+		t.Fatalf("want UnsupportedTargetError, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package jsonschema
+
+import (
+	"strconv"
+)
+
+// MinimalFailing returns the minimal part of instance v, with which
+// sch reports errors at same instance and keyword locations, as with
+// v. This is useful to store or share the offending fragment of a
+// large instance, instead of the whole instance.
+//
+// The values at instance locations of errors are kept, with only those
+// properties and items which are needed to reproduce the errors. The
+// objects on path to them keep only the properties on that path. The
+// arrays on that path are truncated after the last item needed, and
+// other items are replaced with null. If this reproduces different
+// errors, the values at locations of differing errors are kept as is,
+// until the errors are same.
+//
+// It returns the minimal instance and its validation error. If v is
+// valid, it returns nil, nil. The v is not modified, but the returned
+// instance shares values with v.
+func (sch *Schema) MinimalFailing(v any) (any, error) {
+	err := sch.Validate(v)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		return nil, err
+	}
+	want := leafErrors(verr)
+	n := &minNode{}
+	for _, loc := range want {
+		n.add(loc, false)
+	}
+	for {
+		mv := n.prune(v)
+		err := sch.Validate(mv)
+		var got map[string][]string
+		switch err := err.(type) {
+		case nil:
+		case *ValidationError:
+			got = leafErrors(err)
+		default:
+			return nil, err
+		}
+		changed := false
+		for key, loc := range want {
+			if _, ok := got[key]; !ok {
+				changed = n.add(loc, true) || changed
+			}
+		}
+		for key, loc := range got {
+			if _, ok := want[key]; !ok {
+				changed = n.add(loc, true) || changed
+			}
+		}
+		if !changed {
+			return mv, err
+		}
+	}
+}
+
+// leafErrors returns instance locations of leaf errors in e,
+// keyed by their instance and keyword location.
+func leafErrors(e *ValidationError) map[string][]string {
+	leaves := map[string][]string{}
+	var collect func(e *ValidationError)
+	collect = func(e *ValidationError) {
+		if len(e.Causes) == 0 {
+			key := jsonPtr(e.InstanceLocation) + " " + e.AbsoluteKeywordLocation()
+			leaves[key] = e.InstanceLocation
+		}
+		for _, cause := range e.Causes {
+			collect(cause)
+		}
+	}
+	collect(e)
+	return leaves
+}
+
+// minNode is a trie of instance locations to be kept.
+type minNode struct {
+	whole    bool // keep value as is
+	children map[string]*minNode
+}
+
+// add adds loc to trie. If whole is true, the value at loc is
+// marked to be kept as is, or its parent if it is already marked.
+// It returns false, if nothing changed.
+func (n *minNode) add(loc []string, whole bool) bool {
+	path := []*minNode{n}
+	changed := false
+	for _, tok := range loc {
+		if n.children == nil {
+			n.children = map[string]*minNode{}
+		}
+		child, ok := n.children[tok]
+		if !ok {
+			child = &minNode{}
+			n.children[tok] = child
+			changed = true
+		}
+		n = child
+		path = append(path, n)
+	}
+	if whole {
+		for i := len(path) - 1; i >= 0; i-- {
+			if !path[i].whole {
+				path[i].whole = true
+				return true
+			}
+		}
+	}
+	return changed
+}
+
+func (n *minNode) prune(v any) any {
+	if n.whole {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		obj := map[string]any{}
+		for pname, child := range n.children {
+			if pvalue, ok := v[pname]; ok {
+				obj[pname] = child.prune(pvalue)
+			}
+		}
+		return obj
+	case []any:
+		size := 0
+		for tok := range n.children {
+			if i, err := strconv.Atoi(tok); err == nil && i < len(v) && i >= size {
+				size = i + 1
+			}
+		}
+		arr := make([]any, size)
+		for tok, child := range n.children {
+			if i, err := strconv.Atoi(tok); err == nil && i < size {
+				arr[i] = child.prune(v[i])
+			}
+		}
+		return arr
+	}
+	return v
+}
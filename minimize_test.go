@@ -0,0 +1,82 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestMinimalFailing(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"users": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"name": { "type": "string" },
+						"age": { "type": "integer" }
+					},
+					"required": ["name"]
+				}
+			},
+			"tags": { "type": "array", "uniqueItems": true },
+			"meta": { "type": "object", "maxProperties": 2 }
+		}
+	}`
+	tests := []struct {
+		instance string
+		want     string
+	}{
+		{
+			`{"users": [{"name": "a", "age": 1}, {"name": "b", "age": "x"}, {"name": "c"}], "tags": ["x"]}`,
+			// null in place of first user is not valid, so it is kept.
+			// name of second user is kept, to avoid required error
+			`{"users": [{"name": "a", "age": 1}, {"name": "b", "age": "x"}]}`,
+		},
+		{
+			`{"users": [{"name": "a"}, {"age": 2}], "tags": ["x"]}`,
+			`{"users": [{"name": "a"}, {}]}`,
+		},
+		{
+			`{"users": [], "tags": ["x", "y", "x"]}`,
+			`{"tags": ["x", "y", "x"]}`,
+		},
+		{
+			`{"users": [], "meta": {"a": 1, "b": 2, "c": 3}}`,
+			`{"meta": {"a": 1, "b": 2, "c": 3}}`,
+		},
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", mustUnmarshal(t, schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		inst := mustUnmarshal(t, test.instance)
+		got, err := sch.MinimalFailing(inst)
+		if err == nil {
+			t.Fatalf("%s: want error", test.instance)
+		}
+		want := mustUnmarshal(t, test.want)
+		if !reflect.DeepEqual(got, want) {
+			b, _ := json.Marshal(got)
+			t.Errorf("%s:\n got %s\nwant %s", test.instance, b, test.want)
+		}
+		if !strings.Contains(err.Error(), "jsonschema validation failed") {
+			t.Errorf("%s: unexpected error %v", test.instance, err)
+		}
+	}
+
+	got, err := sch.MinimalFailing(mustUnmarshal(t, `{"users": [{"name": "a"}]}`))
+	if got != nil || err != nil {
+		t.Errorf("valid instance: got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,193 @@
+package jsonschema
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Normalize returns canonical form of schema document doc,
+// so that semantically same documents from different producers
+// compare equal, and produce same bytes when marshaled using
+// encoding/json, which sorts object keys.
+//
+// The draft of doc is determined by its `$schema`, defaulting
+// to latest draft. Following are normalized:
+//   - trivial schemas `{}` and `{"not": {}}` are replaced with
+//     booleans, for draft >= 6
+//   - `type` with single item is replaced with string, otherwise sorted
+//   - `required` is sorted and deduplicated
+//   - fragment of `$ref` is consistently percent-encoded, and
+//     empty fragment is removed
+//   - keywords with default value, which have no effect, such as
+//     `minContains: 1`, `minLength: 0` are removed
+//
+// The doc is not modified.
+func Normalize(doc any) any {
+	return normalizeSchema(deepClone(doc), draftLatest, true)
+}
+
+// normalizeSchema normalizes schema v in place
+// and returns its replacement.
+func normalizeSchema(v any, d *Draft, isRoot bool) any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	if s, ok := strVal(obj, "$schema"); ok && (isRoot || d.getID(obj) != "") {
+		if sd := draftFromURL(s); sd != nil {
+			d = sd
+		}
+	}
+
+	// normalize subschemas --
+	for _, sp := range d.subschemas {
+		for ptr, sch := range sp.collect(obj, "") {
+			setAt(obj, ptr, normalizeSchema(sch, d, false))
+		}
+	}
+
+	// normalize keywords --
+	if t, ok := obj["type"].([]any); ok {
+		if len(t) == 1 {
+			obj["type"] = t[0]
+		} else {
+			obj["type"] = sortedStrings(t)
+		}
+	}
+	if reqd, ok := obj["required"].([]any); ok {
+		obj["required"] = sortedStrings(reqd)
+	}
+	if ref, ok := strVal(obj, "$ref"); ok {
+		obj["$ref"] = normalizeRef(ref)
+	}
+	for kw, def := range redundantKeywords {
+		if v, ok := obj[kw]; ok {
+			if eq, _ := equals(v, def); eq {
+				delete(obj, kw)
+			}
+		}
+	}
+	if d.version == 4 {
+		for _, kw := range []string{"exclusiveMinimum", "exclusiveMaximum"} {
+			if b, ok := obj[kw].(bool); ok && !b {
+				delete(obj, kw)
+			}
+		}
+	} else if reqd, ok := obj["required"].([]any); ok && len(reqd) == 0 {
+		// draft4 does not allow empty required
+		delete(obj, "required")
+	}
+
+	// trivial schemas --
+	if d.version >= 6 {
+		switch len(obj) {
+		case 0:
+			return true
+		case 1:
+			if not, ok := obj["not"]; ok && not == true {
+				return false
+			}
+		}
+	}
+	return obj
+}
+
+// keywords with default value, which have no effect.
+var redundantKeywords = map[string]any{
+	"minContains":       1,
+	"minLength":         0,
+	"minItems":          0,
+	"minProperties":     0,
+	"uniqueItems":       false,
+	"properties":        map[string]any{},
+	"patternProperties": map[string]any{},
+	"dependentRequired": map[string]any{},
+	"dependentSchemas":  map[string]any{},
+}
+
+func normalizeRef(ref string) string {
+	u, frag := split(ref)
+	if frag == "" {
+		if u == "" {
+			return ref
+		}
+		return u
+	}
+	if !strings.HasPrefix(frag, "/") {
+		return ref
+	}
+	f, err := decode(frag)
+	if err != nil {
+		return ref
+	}
+	return u + "#" + encode(f)
+}
+
+// setAt sets value at ptr in v.
+func setAt(v any, ptr jsonPointer, value any) {
+	tokens := strings.Split(string(ptr), "/")[1:]
+	for i, tok := range tokens {
+		tok, _ = unescape(tok)
+		last := i == len(tokens)-1
+		switch t := v.(type) {
+		case map[string]any:
+			if last {
+				t[tok] = value
+			} else {
+				v = t[tok]
+			}
+		case []any:
+			index, err := strconv.Atoi(tok)
+			if err != nil || index < 0 || index >= len(t) {
+				return
+			}
+			if last {
+				t[index] = value
+			} else {
+				v = t[index]
+			}
+		default:
+			return
+		}
+	}
+}
+
+// sortedStrings returns arr sorted and deduplicated,
+// if all its items are strings.
+func sortedStrings(arr []any) []any {
+	strs := make([]string, 0, len(arr))
+	for _, item := range arr {
+		s, ok := item.(string)
+		if !ok {
+			return arr
+		}
+		strs = append(strs, s)
+	}
+	slices.Sort(strs)
+	strs = slices.Compact(strs)
+	out := make([]any, len(strs))
+	for i, s := range strs {
+		out[i] = s
+	}
+	return out
+}
+
+func deepClone(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, v := range v {
+			out[k] = deepClone(v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = deepClone(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,56 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"trivial", `{"properties": {"a": {}, "b": {"not": {}}}}`, `{"properties": {"a": true, "b": false}}`},
+		{"trivialRoot", `{}`, `true`},
+		{"type", `{"type": ["string"], "items": {"type": ["string", "null", "string"]}}`, `{"type": "string", "items": {"type": ["null", "string"]}}`},
+		{"required", `{"required": ["b", "a", "b"]}`, `{"required": ["a", "b"]}`},
+		{"emptyRequired", `{"required": [], "title": "x"}`, `{"title": "x"}`},
+		{"ref", `{"allOf": [{"$ref": "other.json#"}, {"$ref": "#/$defs/a%7Eb"}, {"$ref": "#/$defs/a b"}, {"$ref": "#"}]}`, `{"allOf": [{"$ref": "other.json"}, {"$ref": "#/$defs/a~b"}, {"$ref": "#/$defs/a%20b"}, {"$ref": "#"}]}`},
+		{"defaults", `{"contains": {"type": "string"}, "minContains": 1, "minLength": 0, "uniqueItems": false, "maxLength": 5}`, `{"contains": {"type": "string"}, "maxLength": 5}`},
+		{"unknown", `{"x-custom": {}, "const": {}}`, `{"x-custom": {}, "const": {}}`},
+		{
+			"draft4",
+			`{"$schema": "http://json-schema.org/draft-04/schema#", "properties": {"a": {}}, "required": ["a"], "exclusiveMinimum": false, "minimum": 1}`,
+			`{"$schema": "http://json-schema.org/draft-04/schema#", "properties": {"a": {}}, "required": ["a"], "minimum": 1}`,
+		},
+		{
+			"draft4Items",
+			`{"$schema": "http://json-schema.org/draft-04/schema#", "items": [{"type": ["string"]}]}`,
+			`{"$schema": "http://json-schema.org/draft-04/schema#", "items": [{"type": "string"}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := jsonschema.UnmarshalJSON(strings.NewReader(test.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := jsonschema.UnmarshalJSON(strings.NewReader(test.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			orig, _ := jsonschema.UnmarshalJSON(strings.NewReader(test.doc))
+			got := jsonschema.Normalize(doc)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(doc, orig) {
+				t.Fatal("doc is modified")
+			}
+		})
+	}
+}
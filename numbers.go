@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// NormalizeNumbers returns copy of instance v, in which go numeric
+// values such as float64 and int are replaced with [json.Number].
+// This is useful when v is decoded using [json.Unmarshal] rather
+// than [UnmarshalJSON].
+//
+// Floats are formatted exactly as encoding/json marshals them,
+// so 0.1 becomes "0.1", not its binary approximation. NaN and
+// infinities are left as is. Note that precision already lost
+// while decoding, such as in integers beyond 2^53, cannot be
+// recovered.
+//
+// The v is not modified.
+func NormalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for pname, pvalue := range v {
+			obj[pname] = NormalizeNumbers(pvalue)
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i] = NormalizeNumbers(item)
+		}
+		return arr
+	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		return json.Number(b)
+	default:
+		return v
+	}
+}
+
+// WarnFloatNumbers makes validation call fn, for each float32 or
+// float64 value found in the instance, with its json-pointer. Such
+// values indicate that the instance is not decoded using [UnmarshalJSON]
+// or [json.Decoder.UseNumber], and may have lost precision.
+//
+// This is meant to find such callers during development, without
+// changing validation results. see [NormalizeNumbers].
+func (c *Compiler) WarnFloatNumbers(fn func(instanceLocation string, v any)) {
+	c.warnFloat = fn
+}
+
+// warnFloats calls fn for each float in v.
+func warnFloats(v any, vloc string, fn func(string, any)) {
+	switch v := v.(type) {
+	case map[string]any:
+		for pname, pvalue := range v {
+			warnFloats(pvalue, vloc+"/"+escape(pname), fn)
+		}
+	case []any:
+		for i, item := range v {
+			warnFloats(item, vloc+"/"+strconv.Itoa(i), fn)
+		}
+	case float32, float64:
+		fn(vloc, v)
+	}
+}
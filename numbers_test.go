@@ -0,0 +1,53 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestNormalizeNumbers(t *testing.T) {
+	var v any
+	if err := json.Unmarshal([]byte(`{"a": [0.1, 1e21, 1e-7, 100], "b": "1.5", "c": true}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	got := jsonschema.NormalizeNumbers(v)
+	want := map[string]any{
+		"a": []any{json.Number("0.1"), json.Number("1e+21"), json.Number("1e-7"), json.Number("100")},
+		"b": "1.5",
+		"c": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if _, ok := v.(map[string]any)["a"].([]any)[0].(float64); !ok {
+		t.Fatal("argument must not be modified")
+	}
+	if got := jsonschema.NormalizeNumbers(int64(5)); got != json.Number("5") {
+		t.Fatalf("got %#v, want 5", got)
+	}
+}
+
+func TestWarnFloatNumbers(t *testing.T) {
+	var locs []string
+	c := jsonschema.NewCompiler()
+	c.WarnFloatNumbers(func(instanceLocation string, v any) {
+		locs = append(locs, instanceLocation)
+	})
+	if err := c.AddResource("schema.json", map[string]any{"items": map[string]any{"type": "integer"}}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate([]any{1.0, json.Number("2"), 3}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/0"}; !slices.Equal(locs, want) {
+		t.Fatalf("got %v, want %v", locs, want)
+	}
+}
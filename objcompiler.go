@@ -17,6 +17,19 @@ type objCompiler struct {
 }
 
 func (c *objCompiler) compile(s *Schema) error {
+	if c.c.dataRef {
+		var refs []dataRef
+		if c.obj, refs = collectDataRefs(c.obj); refs != nil {
+			s.dataRefs = &dataRefs{
+				refs:          refs,
+				regexpEngine:  c.c.roots.regexpEngine,
+				formats:       c.c.formats,
+				assertFormat:  c.assertFormat(s.DraftVersion),
+				assertFormats: c.c.assertFormats,
+			}
+		}
+	}
+
 	// id --
 	if id := c.res.dialect.draft.getID(c.obj); id != "" {
 		s.ID = id
@@ -80,6 +93,16 @@ func (c *objCompiler) compile(s *Schema) error {
 		}
 	}
 
+	if c.c.warnUnknownKeywords {
+		c.warnUnknownKeywords()
+	}
+	if c.c.warnDraftKeywords {
+		c.warnDraftKeywords()
+	}
+	if c.c.strict {
+		c.warnStrict()
+	}
+
 	return nil
 }
 
@@ -117,11 +140,13 @@ func (c *objCompiler) compileDraft4(s *Schema) error {
 		if m := c.enqueueMap("patternProperties"); m != nil {
 			s.PatternProperties = map[Regexp]*Schema{}
 			for pname, sch := range m {
-				re, err := c.c.roots.regexpEngine(pname)
+				re, err := c.compileRegexp("patternProperties", pname)
 				if err != nil {
-					return &InvalidRegexError{c.up.format("patternProperties"), pname, err}
+					return err
+				}
+				if re != nil {
+					s.PatternProperties[re] = sch
 				}
-				s.PatternProperties[re] = sch
 			}
 		}
 		s.AdditionalProperties = c.enqueueAdditional("additionalProperties")
@@ -142,12 +167,21 @@ func (c *objCompiler) compileDraft4(s *Schema) error {
 	if c.hasVocab("validation") {
 		if t, ok := c.obj["type"]; ok {
 			s.Types = newTypes(t)
+			s.strictIntegers = c.c.strictIntegers
+			if c.c.nullable && s.Types != nil && c.boolean("nullable") {
+				s.Types.add(nullType)
+				c.c.warn(c.up.format("nullable"), "nullable", &Nullable{})
+			}
 		}
 		if arr := c.arrVal("enum"); arr != nil {
 			s.Enum = newEnum(arr)
 		}
 		s.MultipleOf = c.numVal("multipleOf")
 		s.Maximum = c.numVal("maximum")
+		if s.DraftVersion == 4 {
+			s.ExclusiveMaximumBool = c.boolVal("exclusiveMaximum")
+			s.ExclusiveMinimumBool = c.boolVal("exclusiveMinimum")
+		}
 		if c.boolean("exclusiveMaximum") {
 			s.ExclusiveMaximum = s.Maximum
 			s.Maximum = nil
@@ -164,10 +198,11 @@ func (c *objCompiler) compileDraft4(s *Schema) error {
 
 		s.MinLength = c.intVal("minLength")
 		s.MaxLength = c.intVal("maxLength")
+		s.lengthUnit = c.c.lengthUnit
 		if pat := c.strVal("pattern"); pat != nil {
-			s.Pattern, err = c.c.roots.regexpEngine(*pat)
+			s.Pattern, err = c.compileRegexp("pattern", *pat)
 			if err != nil {
-				return &InvalidRegexError{c.up.format("pattern"), *pat, err}
+				return err
 			}
 		}
 
@@ -183,8 +218,8 @@ func (c *objCompiler) compileDraft4(s *Schema) error {
 	}
 
 	// format --
-	if c.assertFormat(s.DraftVersion) {
-		if f := c.strVal("format"); f != nil {
+	if f := c.strVal("format"); f != nil {
+		if c.assertFormat(s.DraftVersion) || c.c.assertFormats[*f] {
 			if *f == "regex" {
 				s.Format = &Format{
 					Name:     "regex",
@@ -213,6 +248,9 @@ func (c *objCompiler) compileDraft6(s *Schema) error {
 	if c.hasVocab("applicator") {
 		s.Contains = c.enqueueProp("contains")
 		s.PropertyNames = c.enqueueProp("propertyNames")
+		if obj, ok := c.obj["propertyNames"].(map[string]any); ok {
+			s.stringPropertyNames = stringAssertionsOnly(obj)
+		}
 	}
 	if c.hasVocab("validation") {
 		if v, ok := c.obj["const"]; ok {
@@ -355,12 +393,24 @@ func (c *objCompiler) enqueueRef(pname string) (*Schema, error) {
 	if ref == nil {
 		return nil, nil
 	}
+	return c.enqueueRefStr(c.up.format(pname), *ref)
+}
+
+// enqueueRefStr enqueues schema referenced by ref,
+// which is found at location loc.
+func (c *objCompiler) enqueueRefStr(loc, ref string) (*Schema, error) {
+	if err := c.c.limits.countRef(loc); err != nil {
+		return nil, err
+	}
 	baseURL := c.res.id
 	// baseURL := c.r.baseURL(c.up.ptr)
-	uf, err := baseURL.join(*ref)
+	uf, err := baseURL.join(ref)
 	if err != nil {
 		return nil, err
 	}
+	if uf, err = c.c.interceptRef(loc, uf); err != nil {
+		return nil, err
+	}
 
 	up, err := c.r.resolve(*uf)
 	if err != nil {
@@ -425,11 +475,33 @@ func (c *objCompiler) enqueueAdditional(pname string) any {
 
 // --
 
+func (c *objCompiler) compileRegexp(pname, pattern string) (Regexp, error) {
+	if err := c.c.limits.countRegexp(pattern, c.up.format(pname)); err != nil {
+		return nil, err
+	}
+	re, err := c.c.roots.regexpEngine(pattern)
+	if err != nil {
+		if c.c.roots.ignoreUnsupportedPatterns {
+			return nil, nil
+		}
+		if c.c.roots.regexpChain {
+			return nil, &UnsupportedPatternError{c.up.format(pname), pattern, err}
+		}
+		return nil, &InvalidRegexError{c.up.format(pname), pattern, err}
+	}
+	return re, nil
+}
+
 func (c *objCompiler) hasVocab(name string) bool {
 	return c.res.dialect.hasVocab(name)
 }
 
+// assertFormat tells whether all formats are asserted.
 func (c *objCompiler) assertFormat(draftVersion int) bool {
+	if c.c.assertFormats != nil {
+		// only formats listed are asserted
+		return false
+	}
 	if c.c.assertFormat || draftVersion < 2019 {
 		return true
 	}
@@ -547,3 +619,21 @@ func toStrings(arr []any) []string {
 	}
 	return strings
 }
+
+// stringAssertionsOnly tells whether schema obj has no assertions
+// other than `minLength`, `maxLength` and `pattern`, so that
+// property names can be checked without nested validation.
+func stringAssertionsOnly(obj map[string]any) bool {
+	for kw, v := range obj {
+		switch kw {
+		case "minLength", "maxLength", "pattern":
+			if _, ok := v.(map[string]any); ok {
+				return false // $data reference
+			}
+		case "title", "description", "$comment", "default", "examples", "deprecated", "readOnly", "writeOnly":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// oneOfIndex maps value of a property to the only oneOf
+// subschema that can match an object with that value.
+//
+// It is built when every oneOf subschema requires the property,
+// and restricts it using `const` or `enum` with values distinct
+// from other subschemas. Such subschemas are commonly used to
+// model tagged unions.
+type oneOfIndex struct {
+	pname    string
+	branches map[string]int // dispatchKey of value -> subschema index
+}
+
+// newOneOfIndex returns index for oneOf of sch,
+// nil if oneOf is not dispatchable.
+func newOneOfIndex(sch *Schema) *oneOfIndex {
+	if len(sch.OneOf) < 2 {
+		return nil
+	}
+	var pnames []string
+	for pname := range constrainedProps(sch.OneOf[0]) {
+		pnames = append(pnames, pname)
+	}
+	slices.Sort(pnames)
+
+nextProp:
+	for _, pname := range pnames {
+		idx := &oneOfIndex{pname, map[string]int{}}
+		for i, branch := range sch.OneOf {
+			values, ok := constrainedProps(branch)[pname]
+			if !ok {
+				continue nextProp
+			}
+			for _, v := range values {
+				key, ok := dispatchKey(v)
+				if !ok {
+					continue nextProp
+				}
+				if j, ok := idx.branches[key]; ok && j != i {
+					continue nextProp
+				}
+				idx.branches[key] = i
+			}
+		}
+		return idx
+	}
+	return nil
+}
+
+// lookup returns index of the only subschema that can match v.
+func (idx *oneOfIndex) lookup(v any) (int, bool) {
+	if idx == nil {
+		return 0, false
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+	pvalue, ok := obj[idx.pname]
+	if !ok {
+		return 0, false
+	}
+	key, ok := dispatchKey(pvalue)
+	if !ok {
+		return 0, false
+	}
+	i, ok := idx.branches[key]
+	return i, ok
+}
+
+// constrainedProps returns the properties which are required by
+// sch, and restricted to the returned values by `const` or `enum`.
+// Constraints are also collected from `$ref` chain of sch, as they
+// all apply to same value.
+func constrainedProps(sch *Schema) map[string][]any {
+	var required []string
+	values := map[string][]any{}
+	for seen := map[*Schema]bool{}; sch != nil && !seen[sch]; sch = sch.Ref {
+		seen[sch] = true
+		required = append(required, sch.Required...)
+		for pname, psch := range sch.Properties {
+			if _, ok := values[pname]; ok {
+				continue
+			}
+			if psch.Const != nil {
+				values[pname] = []any{*psch.Const}
+			} else if psch.Enum != nil {
+				values[pname] = psch.Enum.Values
+			}
+		}
+	}
+	for pname := range values {
+		if !slices.Contains(required, pname) {
+			delete(values, pname)
+		}
+	}
+	return values
+}
+
+// dispatchKey returns key for scalar json value v, such that
+// values equal as per json schema, have same key.
+func dispatchKey(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "null", true
+	case bool:
+		return fmt.Sprint(v), true
+	case string:
+		return "s:" + v, true
+	}
+	if typeOf(v) != numberType {
+		return "", false
+	}
+	num, ok := new(big.Rat).SetString(fmt.Sprint(v))
+	if !ok {
+		return "", false
+	}
+	return "n:" + num.RatString(), true
+}
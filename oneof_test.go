@@ -0,0 +1,84 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestOneOfDispatch(t *testing.T) {
+	schema := `{
+		"oneOf": [
+			{"$ref": "#/$defs/cat"},
+			{
+				"properties": {"kind": {"enum": ["dog", "puppy"]}, "barks": {"type": "boolean"}},
+				"required": ["kind", "barks"]
+			},
+			{
+				"properties": {"kind": {"const": 1}},
+				"required": ["kind"]
+			}
+		],
+		"$defs": {
+			"cat": {
+				"properties": {"kind": {"const": "cat"}, "meows": {"type": "boolean"}},
+				"required": ["kind", "meows"]
+			}
+		}
+	}`
+	compile := func(dispatch bool) *jsonschema.Schema {
+		c := jsonschema.NewCompiler()
+		if !dispatch {
+			c.DisableOneOfDispatch()
+		}
+		if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+	sch, exact := compile(true), compile(false)
+
+	tests := []struct {
+		instance string
+		valid    bool
+		unwanted string // must not be in error with dispatch
+	}{
+		{`{"kind": "cat", "meows": true}`, true, ""},
+		{`{"kind": "puppy", "barks": true}`, true, ""},
+		{`{"kind": 1.0}`, true, ""},
+		{`{"kind": "cat", "meows": 1}`, false, "'puppy'"},
+		{`{"kind": "dog"}`, false, "'cat'"},
+		{`{"kind": "cow"}`, false, ""},
+		{`{"meows": true}`, false, ""},
+		{`"cat"`, false, ""},
+	}
+	for _, test := range tests {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.instance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sch.Validate(inst)
+		if got := err == nil; got != test.valid {
+			t.Errorf("%s: got valid %v, want %v", test.instance, got, test.valid)
+			continue
+		}
+		exactErr := exact.Validate(inst)
+		if got := exactErr == nil; got != test.valid {
+			t.Errorf("%s: without dispatch, got valid %v, want %v", test.instance, got, test.valid)
+			continue
+		}
+		if test.unwanted != "" {
+			if strings.Contains(err.Error(), test.unwanted) {
+				t.Errorf("%s: got errors of other subschemas\n%v", test.instance, err)
+			}
+			if !strings.Contains(exactErr.Error(), test.unwanted) {
+				t.Errorf("%s: want errors of all subschemas without dispatch\n%v", test.instance, exactErr)
+			}
+		}
+	}
+}
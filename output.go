@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
 	"github.com/santhosh-tekuri/jsonschema/v6/kind"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -22,7 +23,11 @@ func (e *ValidationError) schemaURL() string {
 	}
 }
 
-func (e *ValidationError) absoluteKeywordLocation() string {
+// AbsoluteKeywordLocation returns absolute url of the failed
+// keyword, i.e. SchemaURL with [ValidationError.KeywordPath]
+// appended to its fragment. For errors of kind [kind.Reference],
+// it is url of the referenced schema.
+func (e *ValidationError) AbsoluteKeywordLocation() string {
 	var schemaURL string
 	var keywordPath []string
 	if ref, ok := e.ErrorKind.(*kind.Reference); ok {
@@ -55,7 +60,7 @@ func (e *ValidationError) display(sb *strings.Builder, verbose bool, indent int,
 		indent = indent + 1
 
 		prevAbsKwLoc := absKwLoc
-		absKwLoc = e.absoluteKeywordLocation()
+		absKwLoc = e.AbsoluteKeywordLocation()
 
 		if _, ok := e.ErrorKind.(*kind.Schema); ok {
 			sb.WriteString(e.ErrorKind.LocalizedString(p))
@@ -81,6 +86,9 @@ func (e *ValidationError) display(sb *strings.Builder, verbose bool, indent int,
 }
 
 func (e *ValidationError) Error() string {
+	if e.formatter != nil {
+		return e.formatter(e)
+	}
 	return e.LocalizedError(defaultPrinter)
 }
 
@@ -101,12 +109,7 @@ func (e *ValidationError) LocalizedGoString(p *message.Printer) string {
 }
 
 func jsonPtr(tokens []string) string {
-	var sb strings.Builder
-	for _, tok := range tokens {
-		sb.WriteByte('/')
-		sb.WriteString(escape(tok))
-	}
-	return sb.String()
+	return jsonpointer.Format(tokens)
 }
 
 // --
@@ -128,17 +131,24 @@ type OutputUnit struct {
 	KeywordLocation         string       `json:"keywordLocation"`
 	AbsoluteKeywordLocation string       `json:"AbsoluteKeywordLocation,omitempty"`
 	InstanceLocation        string       `json:"instanceLocation"`
+	Title                   string       `json:"title,omitempty"`
+	Description             string       `json:"description,omitempty"`
 	Error                   *OutputError `json:"error,omitempty"`
 	Errors                  []OutputUnit `json:"errors,omitempty"`
 }
 
 type OutputError struct {
-	Kind ErrorKind
-	p    *message.Printer
+	Kind  ErrorKind
+	p     *message.Printer
+	title string // prefixed to message, if not empty
 }
 
 func (k OutputError) MarshalJSON() ([]byte, error) {
-	return json.Marshal(k.Kind.LocalizedString(k.p))
+	msg := k.Kind.LocalizedString(k.p)
+	if k.title != "" {
+		msg = k.title + ": " + msg
+	}
+	return json.Marshal(msg)
 }
 
 // The `Basic` structure, a flat list of output units.
@@ -147,7 +157,7 @@ func (e *ValidationError) BasicOutput() *OutputUnit {
 }
 
 func (e *ValidationError) LocalizedBasicOutput(p *message.Printer) *OutputUnit {
-	out := e.output(true, false, "", "", p)
+	out := e.output(true, false, "", "", p, nil, 1)
 	return &out
 }
 
@@ -157,11 +167,93 @@ func (e *ValidationError) DetailedOutput() *OutputUnit {
 }
 
 func (e *ValidationError) LocalizedDetailedOutput(p *message.Printer) *OutputUnit {
-	out := e.output(false, false, "", "", p)
+	out := e.output(false, false, "", "", p, nil, 1)
 	return &out
 }
 
-func (e *ValidationError) output(flatten, inRef bool, schemaURL, kwLoc string, p *message.Printer) OutputUnit {
+// OutputOptions keeps output bounded, for errors
+// from recursive schemas and deeply nested instances.
+type OutputOptions struct {
+	// Dedupe replaces causes of an output unit, with
+	// [kind.SeeAbove], if they are already reported for
+	// same keyword and instance location, for example
+	// via another path in recursive schema.
+	Dedupe bool
+
+	// MaxDepth omits causes of output units at given
+	// depth. Depth of top-level unit is 1.
+	// Zero means no limit.
+	MaxDepth int
+
+	// Printer is used to localize errors. Defaults
+	// to english.
+	Printer *message.Printer
+
+	// Titles includes `title` and `description` of the failed
+	// schema in output units, and prefixes error messages with
+	// the title, for example "Address: missing property 'city'".
+	Titles bool
+}
+
+// BasicOutputWithOptions is same as [ValidationError.BasicOutput],
+// but uses opts.
+func (e *ValidationError) BasicOutputWithOptions(opts *OutputOptions) *OutputUnit {
+	out := e.output(true, false, "", "", opts.printer(), newOutputState(opts), 1)
+	return &out
+}
+
+// DetailedOutputWithOptions is same as [ValidationError.DetailedOutput],
+// but uses opts.
+func (e *ValidationError) DetailedOutputWithOptions(opts *OutputOptions) *OutputUnit {
+	out := e.output(false, false, "", "", opts.printer(), newOutputState(opts), 1)
+	return &out
+}
+
+func (opts *OutputOptions) printer() *message.Printer {
+	if opts.Printer != nil {
+		return opts.Printer
+	}
+	return defaultPrinter
+}
+
+func (e *ValidationError) outputError(p *message.Printer, st *outputState) *OutputError {
+	if st != nil && st.opts.Titles {
+		return &OutputError{e.ErrorKind, p, e.Title}
+	}
+	return &OutputError{e.ErrorKind, p, ""}
+}
+
+type outputState struct {
+	opts *OutputOptions
+	seen map[string]string // absolute keyword location and instance location -> keyword location
+}
+
+func newOutputState(opts *OutputOptions) *outputState {
+	return &outputState{opts, map[string]string{}}
+}
+
+// truncate tells whether causes of e must be omitted.
+// If so, out is updated accordingly.
+func (st *outputState) truncate(e *ValidationError, out *OutputUnit, depth int, p *message.Printer) bool {
+	if st == nil || e.skip() || len(e.Causes) == 0 {
+		return false
+	}
+	if st.opts.Dedupe {
+		key := e.AbsoluteKeywordLocation() + " " + out.InstanceLocation
+		if kwLoc, ok := st.seen[key]; ok {
+			out.Error = &OutputError{&kind.SeeAbove{KeywordLocation: kwLoc}, p, ""}
+			return true
+		}
+		st.seen[key] = out.KeywordLocation
+	}
+	if st.opts.MaxDepth > 0 && depth >= st.opts.MaxDepth {
+		out.Error = e.outputError(p, st)
+		return true
+	}
+	return false
+}
+
+func (e *ValidationError) output(flatten, inRef bool, schemaURL, kwLoc string, p *message.Printer, st *outputState, depth int) OutputUnit {
 	if !inRef {
 		if _, ok := e.ErrorKind.(*kind.Reference); ok {
 			inRef = true
@@ -186,17 +278,28 @@ func (e *ValidationError) output(flatten, inRef bool, schemaURL, kwLoc string, p
 		KeywordLocation:  keywordLocation,
 	}
 	if inRef {
-		out.AbsoluteKeywordLocation = e.absoluteKeywordLocation()
+		out.AbsoluteKeywordLocation = e.AbsoluteKeywordLocation()
+	}
+	if st != nil && st.opts.Titles {
+		out.Title, out.Description = e.Title, e.Description
+	}
+	if st.truncate(e, &out, depth, p) {
+		return out
+	}
+	if !e.skip() {
+		depth++
 	}
 	for _, cause := range e.Causes {
-		causeOut := cause.output(flatten, inRef, schemaURL, kwLoc, p)
+		causeOut := cause.output(flatten, inRef, schemaURL, kwLoc, p, st, depth)
 		if cause.skip() {
 			causeOut = causeOut.Errors[0]
 		}
 		if flatten {
 			errors := causeOut.Errors
 			causeOut.Errors = nil
-			causeOut.Error = &OutputError{cause.ErrorKind, p}
+			if causeOut.Error == nil {
+				causeOut.Error = cause.outputError(p, st)
+			}
 			out.Errors = append(out.Errors, causeOut)
 			if len(errors) > 0 {
 				out.Errors = append(out.Errors, errors...)
@@ -206,7 +309,7 @@ func (e *ValidationError) output(flatten, inRef bool, schemaURL, kwLoc string, p
 		}
 	}
 	if len(out.Errors) == 0 {
-		out.Error = &OutputError{e.ErrorKind, p}
+		out.Error = e.outputError(p, st)
 	}
 	return out
 }
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
 )
 
 func testOutputDir(t *testing.T, suite, dir string, draft *jsonschema.Draft) {
@@ -143,3 +145,294 @@ func TestOutputSuites(t *testing.T) {
 	testOuputSuite(t, "./testdata/JSON-Schema-Test-Suite")
 	testOuputSuite(t, "./testdata/Extra-Test-Suite")
 }
+
+func TestExactNumbers(t *testing.T) {
+	tests := []struct {
+		schema, instance, want string
+	}{
+		{`{"minimum": 9007199254740993}`, `9007199254740992`, "minimum: got 9007199254740992, want 9007199254740993"},
+		{`{"maximum": 1}`, `1.000000000000000000001`, "maximum: got 1.000000000000000000001, want 1"},
+		{`{"multipleOf": 0.1}`, `0.15`, "multipleOf: got 0.15, want 0.1"},
+		{`{"multipleOf": 3}`, `12345678901234567891`, "multipleOf: got 12345678901234567891, want 3"},
+	}
+	for _, test := range tests {
+		schema, err := jsonschema.UnmarshalJSON(strings.NewReader(test.schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource("schema.json", schema); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.instance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sch.Validate(inst)
+		if err == nil {
+			t.Fatalf("%s: want validation error for %s", test.schema, test.instance)
+		}
+		if got := err.(*jsonschema.ValidationError).Causes[0].Error(); !strings.HasSuffix(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.schema, got, test.want)
+		}
+	}
+}
+
+func TestOutputOptions(t *testing.T) {
+	schema := `{
+		"allOf": [{"$ref": "#/$defs/a"}, {"$ref": "#/$defs/a"}],
+		"$defs": {
+			"a": {
+				"properties": {
+					"x": {"type": "string"},
+					"y": {"type": "string"}
+				}
+			}
+		}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr, ok := sch.Validate(map[string]any{"x": 1, "y": 1}).(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatal("want validation error")
+	}
+
+	count := func(out *jsonschema.OutputUnit) int {
+		b, err := json.Marshal(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(b), `"keywordLocation"`)
+	}
+	full := count(verr.DetailedOutput())
+	if got := count(verr.DetailedOutputWithOptions(&jsonschema.OutputOptions{})); got != full {
+		t.Fatalf("got %d units, want %d", got, full)
+	}
+
+	out := verr.DetailedOutputWithOptions(&jsonschema.OutputOptions{Dedupe: true})
+	if got := count(out); got >= full {
+		t.Fatalf("got %d units, want less than %d", got, full)
+	}
+	second := out.Errors[0].Errors[1]
+	b, err := json.Marshal(second.Error)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"same as at '/allOf/0/$ref', see above"`; string(b) != want {
+		t.Fatalf("got %s, want %s", b, want)
+	}
+	if basic := verr.BasicOutputWithOptions(&jsonschema.OutputOptions{Dedupe: true}); count(basic) >= count(verr.BasicOutput()) {
+		t.Fatal("want less units in basic output")
+	}
+
+	out = verr.DetailedOutputWithOptions(&jsonschema.OutputOptions{MaxDepth: 2})
+	if got := count(out); got != 2 {
+		t.Fatalf("got %d units, want 2", got)
+	}
+	if out.Errors[0].Error == nil {
+		t.Fatal("want error for truncated unit")
+	}
+}
+
+func TestKeywordPath(t *testing.T) {
+	schema := `{
+		"properties": {
+			"a/b": {"$ref": "#/$defs/c~0d"}
+		},
+		"$defs": {
+			"c~d": {"type": "string"}
+		}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("http://example.com/schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("http://example.com/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr, ok := sch.Validate(map[string]any{"a/b": 1}).(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatal("want validation error")
+	}
+
+	// find leaf error
+	e := verr
+	for len(e.Causes) > 0 {
+		e = e.Causes[0]
+	}
+	if got, want := e.KeywordPath().String(), "/type"; got != want {
+		t.Errorf("KeywordPath: got %q, want %q", got, want)
+	}
+	if got, want := e.AbsoluteKeywordLocation(), "http://example.com/schema.json#/$defs/c~0d/type"; got != want {
+		t.Errorf("AbsoluteKeywordLocation: got %q, want %q", got, want)
+	}
+
+	path := jsonschema.KeywordPath{"properties", "a/b", "$ref"}
+	if got, want := path.String(), "/properties/a~1b/$ref"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+}
+
+func TestErrorOrder(t *testing.T) {
+	schema := `{
+		"properties": {
+			"b": {"type": "string"},
+			"a": {"type": "string"}
+		},
+		"patternProperties": {
+			"^x": {"type": "string"},
+			"^c": {"type": "string"}
+		},
+		"propertyNames": {"maxLength": 2},
+		"dependentRequired": {
+			"b": ["q"],
+			"a": ["p"]
+		},
+		"additionalProperties": false
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := map[string]any{"a": 1, "b": 1, "c1": 1, "x1": 1, "long": 1, "yyy": 1, "zzz": 1}
+	output := func() string {
+		b, err := json.Marshal(sch.Validate(inst).(*jsonschema.ValidationError).BasicOutput())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	want := output()
+	for i := 0; i < 20; i++ {
+		if got := output(); got != want {
+			t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+
+	var locs []string
+	for _, cause := range sch.Validate(inst).(*jsonschema.ValidationError).Causes {
+		locs = append(locs, jsonschema.KeywordPath(cause.InstanceLocation).String()+" "+cause.KeywordPath().String())
+	}
+	wantLocs := []string{
+		"/a /type",
+		"/b /type",
+		"/c1 /type",
+		"/x1 /type",
+		" /additionalProperties",
+		" /propertyNames/maxLength",
+		" /propertyNames/maxLength",
+		" /propertyNames/maxLength",
+		" /dependentRequired/a",
+		" /dependentRequired/b",
+	}
+	if !slices.Equal(locs, wantLocs) {
+		t.Fatalf("got %q, want %q", locs, wantLocs)
+	}
+}
+
+func TestOutputTitles(t *testing.T) {
+	schema := `{
+		"properties": {
+			"address": {
+				"title": "Address",
+				"description": "postal address",
+				"required": ["city"]
+			}
+		}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr, ok := sch.Validate(map[string]any{"address": map[string]any{}}).(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatal("want validation error")
+	}
+
+	marshal := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	out := verr.BasicOutputWithOptions(&jsonschema.OutputOptions{Titles: true})
+	unit := out.Errors[len(out.Errors)-1]
+	if unit.Title != "Address" || unit.Description != "postal address" {
+		t.Errorf("got title %q, description %q", unit.Title, unit.Description)
+	}
+	if got, want := marshal(unit.Error), `"Address: missing property 'city'"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// disabled by default
+	got := marshal(verr.BasicOutput())
+	if strings.Contains(got, "Address") {
+		t.Errorf("title must not be included: %s", got)
+	}
+}
+
+func TestPropertyNamesCause(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	schema := `{
+		"properties": {
+			"simple": {"propertyNames": {"maxLength": 2, "pattern": "^a"}},
+			"nested": {"propertyNames": {"maxLength": 2, "not": {"const": "ab"}}}
+		}
+	}`
+	if err := c.AddResourceJSON("schema.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sch.Validate(map[string]any{"simple": map[string]any{"abc": 1}})
+	causes := err.(*jsonschema.ValidationError).Causes
+	if len(causes) != 1 {
+		t.Fatalf("got %d causes, want 1: %v", len(causes), err)
+	}
+	verr := causes[0]
+	k, ok := verr.ErrorKind.(*kind.PropertyNames)
+	if !ok || k.Property != "abc" {
+		t.Fatalf("got %#v, want PropertyNames of abc", verr.ErrorKind)
+	}
+	if _, ok := k.Cause.(*kind.MaxLength); !ok {
+		t.Fatalf("got cause %#v, want MaxLength", k.Cause)
+	}
+	if len(verr.Causes) != 0 {
+		t.Errorf("got %d nested causes, want 0", len(verr.Causes))
+	}
+	if got, want := jsonschema.KeywordPath(verr.InstanceLocation).String(), "/simple"; got != want {
+		t.Errorf("InstanceLocation: got %q, want %q", got, want)
+	}
+	if got, want := verr.KeywordPath().String(), "/propertyNames/maxLength"; got != want {
+		t.Errorf("KeywordPath: got %q, want %q", got, want)
+	}
+
+	err = sch.Validate(map[string]any{"nested": map[string]any{"ab": 1}})
+	verr = err.(*jsonschema.ValidationError).Causes[0]
+	if k, ok := verr.ErrorKind.(*kind.PropertyNames); !ok || k.Cause != nil || len(verr.Causes) != 1 {
+		t.Fatalf("got %#v with %d causes, want PropertyNames with nested cause", verr.ErrorKind, len(verr.Causes))
+	}
+}
@@ -0,0 +1,156 @@
+package jsonschema
+
+import (
+	"regexp/syntax"
+	"slices"
+)
+
+// PatternPropertyMatch tells that a property is matched by
+// regex of `patternProperties` during validation.
+type PatternPropertyMatch struct {
+	// location of the property value within the instance.
+	InstanceLocation []string
+
+	// absolute, dereferenced location of schema,
+	// which contains the keyword.
+	SchemaURL string
+
+	// Pattern is the regex, which matched the property name.
+	Pattern string
+
+	// Target is location of the schema, against which
+	// property value is validated.
+	Target string
+
+	// Valid tells whether property value is valid against Target.
+	Valid bool
+}
+
+// TracePatternProperties validates v, and returns which regex of
+// `patternProperties` matched each property, in the order they are
+// evaluated. Matches of same property are sorted by Pattern.
+// This helps to debug why a property is validated against
+// unexpected subschema.
+//
+// Matches are returned, even if v is not valid.
+func (sch *Schema) TracePatternProperties(v any) ([]*PatternPropertyMatch, error) {
+	var matches []*PatternPropertyMatch
+	_, err := sch.validateWith(v, runOpts{patMatches: &matches})
+	return matches, err
+}
+
+func (vd *validator) tracePatternProperty(pname string, regex Regexp, sch *Schema, valid bool) {
+	if vd.opts.patMatches == nil {
+		return
+	}
+	*vd.opts.patMatches = append(*vd.opts.patMatches, &PatternPropertyMatch{
+		InstanceLocation: append(vd.instanceLocation(), pname),
+		SchemaURL:        vd.sch.Location,
+		Pattern:          regex.String(),
+		Target:           sch.Location,
+		Valid:            valid,
+	})
+}
+
+// --
+
+// overlappingPatterns returns pairs of patterns in sch.PatternProperties,
+// which match a common property name, along with such name. Overlap is
+// detected using names generated from the patterns and names in
+// sch.Properties, so not all overlaps are found.
+func overlappingPatterns(sch *Schema) [][3]string {
+	if len(sch.PatternProperties) < 2 {
+		return nil
+	}
+	regexes := sortedPatterns(sch.PatternProperties)
+
+	var names []string
+	for pname := range sch.Properties {
+		names = append(names, pname)
+	}
+	slices.Sort(names)
+	for _, re := range regexes {
+		for _, s := range regexSamples(re.String()) {
+			if re.MatchString(s) {
+				names = append(names, s)
+			}
+		}
+	}
+
+	var pairs [][3]string
+	for i, re1 := range regexes {
+		for _, re2 := range regexes[i+1:] {
+			for _, name := range names {
+				if re1.MatchString(name) && re2.MatchString(name) {
+					pairs = append(pairs, [3]string{re1.String(), re2.String(), name})
+					break
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// maxRegexSamples is maximum number of samples
+// generated for a regex, by regexSamples.
+const maxRegexSamples = 16
+
+// regexSamples returns few strings, which are likely to be matched by
+// regex pattern. Returns nil, if pattern is not valid go regex.
+func regexSamples(pattern string) []string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	return samples(re.Simplify())
+}
+
+func samples(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return nil // matches nothing
+		}
+		return []string{string(re.Rune[0])}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return []string{"x"}
+	case syntax.OpCapture:
+		return samples(re.Sub[0])
+	case syntax.OpStar, syntax.OpQuest:
+		return append([]string{""}, samples(re.Sub[0])...)
+	case syntax.OpPlus:
+		return samples(re.Sub[0])
+	case syntax.OpRepeat:
+		// simplified regex, has no OpRepeat
+		return nil
+	case syntax.OpConcat:
+		result := []string{""}
+		for _, sub := range re.Sub {
+			subSamples := samples(sub)
+			var next []string
+			for _, prefix := range result {
+				for _, s := range subSamples {
+					if len(next) < maxRegexSamples {
+						next = append(next, prefix+s)
+					}
+				}
+			}
+			result = next
+		}
+		return result
+	case syntax.OpAlternate:
+		var result []string
+		for _, sub := range re.Sub {
+			result = append(result, samples(sub)...)
+		}
+		if len(result) > maxRegexSamples {
+			result = result[:maxRegexSamples]
+		}
+		return result
+	default:
+		// empty match, or assertions like ^, $, \b
+		return []string{""}
+	}
+}
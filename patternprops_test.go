@@ -0,0 +1,114 @@
+package jsonschema_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestTracePatternProperties(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/schema.json", map[string]any{
+		"properties": map[string]any{"x-id": true},
+		"patternProperties": map[string]any{
+			"^x-":  map[string]any{"type": "string"},
+			"id$":  map[string]any{"type": "integer"},
+			"^obj": map[string]any{"patternProperties": map[string]any{"^a": true}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("http://example.com/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := map[string]any{
+		"x-id": "1",
+		"obj":  map[string]any{"a": 1, "b": 2},
+		"name": "john",
+	}
+	for i := 0; i < 5; i++ {
+		matches, err := sch.TracePatternProperties(inst)
+		if err == nil {
+			t.Fatal("want error")
+		}
+		var got []string
+		for _, m := range matches {
+			got = append(got, strings.Join([]string{
+				strings.Join(m.InstanceLocation, "/"),
+				strings.TrimPrefix(m.SchemaURL, "http://example.com/schema.json"),
+				m.Pattern,
+				strings.TrimPrefix(m.Target, "http://example.com/schema.json"),
+				validity(m.Valid),
+			}, " "))
+		}
+		slices.Sort(got)
+		want := []string{
+			"obj # ^obj #/patternProperties/%5Eobj valid",
+			"obj/a #/patternProperties/%5Eobj ^a #/patternProperties/%5Eobj/patternProperties/%5Ea valid",
+			"x-id # ^x- #/patternProperties/%5Ex- valid",
+			"x-id # id$ #/patternProperties/id$ invalid",
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+
+		// matches of same property are sorted
+		var patterns []string
+		for _, m := range matches {
+			if slices.Equal(m.InstanceLocation, []string{"x-id"}) {
+				patterns = append(patterns, m.Pattern)
+			}
+		}
+		if !slices.Equal(patterns, []string{"^x-", "id$"}) {
+			t.Fatalf("got %q", patterns)
+		}
+	}
+}
+
+func validity(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+func TestOverlappingPatterns(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/schema.json", map[string]any{
+		"properties": map[string]any{"yz": true},
+		"patternProperties": map[string]any{
+			"^x-":      true,
+			"^x-(a|b)": true,
+			"^y":       true,
+			"z$":       true,
+			"^[0-9]+$": true,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.CompileWithWarnings("http://example.com/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, w := range result.Warnings {
+		k, ok := w.Kind.(*jsonschema.OverlappingPatterns)
+		if !ok {
+			t.Fatalf("got %T, want OverlappingPatterns", w.Kind)
+		}
+		if w.SchemaURL != "http://example.com/schema.json#/patternProperties" {
+			t.Errorf("SchemaURL: got %q", w.SchemaURL)
+		}
+		got = append(got, k.String())
+	}
+	want := []string{
+		`patterns "^x-" and "^x-(a|b)" overlap, both match "x-a"`,
+		`patterns "^y" and "z$" overlap, both match "yz"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package jsonschema
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Validator validates instances against a schema, reusing the memory
+// allocated for bookkeeping, such as dynamic scope and evaluated
+// properties, across validations. This reduces allocations and gc
+// pressure, when validating many instances in high-throughput services.
+//
+// Validator is safe for concurrent use. Buffers are pooled using
+// [sync.Pool], so each goroutine uses its own buffers. Memory of
+// errors returned is never reused, so errors can be retained.
+//
+// Note that [SchemaExt] must not retain [ValidatorContext], after
+// returning from Validate.
+type Validator struct {
+	sch  *Schema
+	mu   sync.RWMutex
+	pool *sync.Pool
+}
+
+// NewValidator returns Validator for sch.
+func NewValidator(sch *Schema) *Validator {
+	return &Validator{sch: sch, pool: newScratchPool()}
+}
+
+func newScratchPool() *sync.Pool {
+	return &sync.Pool{New: func() any {
+		return &scratch{vloc: make([]string, 0, 16)}
+	}}
+}
+
+// Schema returns the schema, instances are validated against.
+func (v *Validator) Schema() *Schema {
+	return v.sch
+}
+
+// Validate is same as [Schema.Validate], but reuses memory.
+func (v *Validator) Validate(inst any) error {
+	return v.validate(inst, false, nil)
+}
+
+func (v *Validator) validate(inst any, borrow bool, fn func(err *ValidationError)) error {
+	v.mu.RLock()
+	pool := v.pool
+	v.mu.RUnlock()
+
+	scr := pool.Get().(*scratch)
+	defer func() {
+		scr.reset()
+		pool.Put(scr)
+	}()
+
+	sch := v.sch
+	if sch.warnFloat != nil {
+		warnFloats(inst, "", sch.warnFloat)
+	}
+	vd := scr.validator()
+	*vd = validator{
+		v:      inst,
+		root:   inst,
+		vloc:   scr.vloc[:0],
+		sch:    sch,
+		scp:    scr.scope(nil, sch, "", 0),
+		uneval: scr.uneval(inst, sch, false),
+		abort:  &scr.abort,
+		scr:    scr,
+	}
+	scr.borrow = borrow
+	_, err := sch.run(vd)
+	err = sch.withFormatter(err, nil)
+	if verr, ok := err.(*ValidationError); ok && fn != nil {
+		fn(verr)
+	}
+	return err
+}
+
+// ValidateBorrowed is same as [Validator.Validate], but if inst
+// is not valid, fn is called with the error, whose memory is reused
+// after fn returns. So fn must not retain the error or any part of
+// it, such as Causes and InstanceLocation, but may retain strings
+// it formats from the error. This avoids allocating error trees,
+// when invalid instances are frequent, for example rejected requests.
+//
+// It returns true, if inst is valid. fn is not called, if validation
+// is aborted with [*RegexpMatchError].
+func (v *Validator) ValidateBorrowed(inst any, fn func(err *ValidationError)) bool {
+	return v.validate(inst, true, fn) == nil
+}
+
+// Reset releases the memory pooled so far. This is useful
+// after validating an unusually large instance.
+func (v *Validator) Reset() {
+	v.mu.Lock()
+	v.pool = newScratchPool()
+	v.mu.Unlock()
+}
+
+// --
+
+// scratch holds memory reused across validations.
+// nil scratch allocates afresh.
+type scratch struct {
+	vloc       []string
+	validators slab[validator]
+	scopes     slab[scope]
+	unevals    slab[uneval]
+	abort      atomic.Pointer[RegexpMatchError]
+
+	// memory of errors is reused, only if borrow
+	borrow bool
+	errors slab[ValidationError]
+	locs   []string // backing array of InstanceLocation of errors
+}
+
+func (scr *scratch) validator() *validator {
+	if scr == nil {
+		return &validator{}
+	}
+	return scr.validators.alloc()
+}
+
+func (scr *scratch) scope(parent *scope, sch *Schema, refKeyword string, vid int) *scope {
+	if scr == nil {
+		if parent == nil {
+			return &scope{sch, refKeyword, vid, nil}
+		}
+		return parent.child(sch, refKeyword, vid)
+	}
+	scp := scr.scopes.alloc()
+	*scp = scope{sch, refKeyword, vid, parent}
+	return scp
+}
+
+func (scr *scratch) uneval(v any, sch *Schema, callerNeeds bool) *uneval {
+	if scr == nil {
+		return unevalFrom(v, sch, callerNeeds)
+	}
+	ue := scr.unevals.alloc()
+	initUneval(ue, v, sch, callerNeeds)
+	return ue
+}
+
+func (scr *scratch) error() *ValidationError {
+	if scr == nil || !scr.borrow {
+		return &ValidationError{}
+	}
+	return scr.errors.alloc()
+}
+
+// location returns copy of vloc, to be used as
+// InstanceLocation of error.
+func (scr *scratch) location(vloc []string) []string {
+	if scr == nil || !scr.borrow {
+		return slices.Clone(vloc)
+	}
+	if len(scr.locs)+len(vloc) > cap(scr.locs) {
+		// previous array is dropped, so that memory
+		// settles to one array of sufficient size
+		scr.locs = make([]string, 0, max(2*cap(scr.locs), len(vloc), 256))
+	}
+	start := len(scr.locs)
+	scr.locs = append(scr.locs, vloc...)
+	return scr.locs[start:len(scr.locs):len(scr.locs)]
+}
+
+func (scr *scratch) reset() {
+	clear(scr.vloc[:cap(scr.vloc)])
+	scr.validators.reset()
+	scr.scopes.reset()
+	scr.unevals.reset()
+	scr.abort.Store(nil)
+	scr.errors.reset()
+	clear(scr.locs)
+	scr.locs = scr.locs[:0]
+}
+
+// slab allocates values of T in chunks, which
+// are reused after reset.
+type slab[T any] struct {
+	chunks [][]T
+	c, i   int // next value is chunks[c][i]
+}
+
+const slabChunk = 64
+
+func (s *slab[T]) alloc() *T {
+	if s.c == len(s.chunks) {
+		s.chunks = append(s.chunks, make([]T, slabChunk))
+	}
+	p := &s.chunks[s.c][s.i]
+	if s.i++; s.i == slabChunk {
+		s.c, s.i = s.c+1, 0
+	}
+	return p
+}
+
+// reset zeroes the values allocated, so that they
+// do not keep the instance and schemas reachable.
+func (s *slab[T]) reset() {
+	for c := 0; c < s.c; c++ {
+		clear(s.chunks[c])
+	}
+	if s.c < len(s.chunks) {
+		clear(s.chunks[s.c][:s.i])
+	}
+	s.c, s.i = 0, 0
+}
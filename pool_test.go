@@ -0,0 +1,131 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestValidator(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "minLength": 1},
+			"tags": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"$ref": "#/$defs/tag"},
+			},
+		},
+		"required":              []any{"name"},
+		"unevaluatedProperties": false,
+		"$defs": map[string]any{
+			"tag": map[string]any{"anyOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "integer"},
+			}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := make([]any, 100) // validated in parallel, if enabled
+	for i := range tags {
+		tags[i] = i
+	}
+	insts := []any{
+		map[string]any{"name": "john", "tags": []any{"a", 1}},
+		map[string]any{"name": "", "tags": []any{true}},
+		map[string]any{"tags": tags, "other": 1},
+		map[string]any{"name": "john", "tags": tags},
+	}
+
+	v := jsonschema.NewValidator(sch)
+	if v.Schema() != sch {
+		t.Fatal("Schema() must return sch")
+	}
+	var want, got []error
+	for _, inst := range insts {
+		want = append(want, sch.Validate(inst))
+		got = append(got, v.Validate(inst))
+	}
+	v.Reset()
+	for i, inst := range insts {
+		// errors retained must not be modified by later validations
+		_ = v.Validate(inst)
+		if (want[i] == nil) != (got[i] == nil) {
+			t.Fatalf("%d: got %v, want %v", i, got[i], want[i])
+		}
+		if want[i] != nil && want[i].Error() != got[i].Error() {
+			t.Fatalf("%d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	for i, inst := range insts {
+		var msg string
+		valid := v.ValidateBorrowed(inst, func(err *jsonschema.ValidationError) {
+			msg = err.Error()
+		})
+		if valid != (want[i] == nil) {
+			t.Fatalf("%d: ValidateBorrowed: got %v, want %v", i, valid, want[i] == nil)
+		}
+		if want[i] != nil && msg != want[i].Error() {
+			t.Fatalf("%d: ValidateBorrowed: got %v, want %v", i, msg, want[i])
+		}
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				i := j % len(insts)
+				if err := v.Validate(insts[i]); (err == nil) != (want[i] == nil) {
+					t.Errorf("%d: got %v, want %v", i, err, want[i])
+					return
+				}
+				v.ValidateBorrowed(insts[i], func(err *jsonschema.ValidationError) {
+					if err.Error() != want[i].Error() {
+						t.Errorf("%d: ValidateBorrowed: got %v, want %v", i, err, want[i])
+					}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValidatorFormatter(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.UseFormatter(func(e *jsonschema.ValidationError) string {
+		return "custom: " + e.SchemaURL
+	})
+	if err := c.AddResource("schema.json", map[string]any{"type": "string"}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sch.Validate(1).Error()
+	if !strings.HasPrefix(want, "custom: ") {
+		t.Fatalf("formatter not used: %q", want)
+	}
+	v := jsonschema.NewValidator(sch)
+	if got := v.Validate(1).Error(); got != want {
+		t.Errorf("Validate: got %q, want %q", got, want)
+	}
+	var got string
+	v.ValidateBorrowed(1, func(err *jsonschema.ValidationError) {
+		got = err.Error()
+	})
+	if got != want {
+		t.Errorf("ValidateBorrowed: got %q, want %q", got, want)
+	}
+}
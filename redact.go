@@ -0,0 +1,76 @@
+package jsonschema
+
+// RedactOptions tells how [Schema.Redact] redacts values.
+type RedactOptions struct {
+	// Keywords are custom annotation keywords, such as `x-sensitive`,
+	// which mark values as sensitive, when their value is true.
+	// `writeOnly` is always considered.
+	Keywords []string
+
+	// Mask replaces the sensitive values. If nil, sensitive values
+	// are removed from objects, and replaced with null in arrays.
+	Mask any
+}
+
+// Redact returns copy of instance v, in which the values whose
+// schema is marked `writeOnly: true` or with keywords in opts are
+// masked or removed. This produces response-safe copy of the instance,
+// using the secrets already marked in schema.
+//
+// The schemas applied to a value are found as in [Schema.RemoveAdditional].
+// opts can be nil.
+//
+// The v is not modified.
+func (sch *Schema) Redact(v any, opts *RedactOptions) any {
+	if opts == nil {
+		opts = &RedactOptions{}
+	}
+	v, _ = redact([]*Schema{sch}, v, opts)
+	return v
+}
+
+// redact returns redacted v, and false if v must be removed.
+func redact(schemas []*Schema, v any, opts *RedactOptions) (any, bool) {
+	var applied []*Schema
+	for _, sch := range schemas {
+		applied = appliedSchemas(sch, v, applied)
+	}
+	for _, sch := range applied {
+		if isSensitive(sch, opts.Keywords) {
+			return opts.Mask, opts.Mask != nil
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for pname, pvalue := range v {
+			if pvalue, ok := redact(propSchemas(applied, pname), pvalue, opts); ok {
+				obj[pname] = pvalue
+			}
+		}
+		return obj, true
+	case []any:
+		arr := make([]any, len(v))
+		for i, item := range v {
+			arr[i], _ = redact(itemSchemas(applied, i), item, opts)
+		}
+		return arr, true
+	default:
+		return v, true
+	}
+}
+
+func isSensitive(sch *Schema, keywords []string) bool {
+	if sch.WriteOnly {
+		return true
+	}
+	if obj, ok := sch.doc.(map[string]any); ok {
+		for _, kw := range keywords {
+			if obj[kw] == true {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestRedact(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"user": { "type": "string" },
+			"password": { "type": "string", "writeOnly": true },
+			"card": { "$ref": "#/$defs/card" },
+			"tokens": { "items": { "x-sensitive": true } }
+		},
+		"$defs": {
+			"card": {
+				"properties": {
+					"number": { "x-sensitive": true },
+					"expiry": { "type": "string" }
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst := map[string]any{
+		"user":     "john",
+		"password": "secret",
+		"card":     map[string]any{"number": "1234", "expiry": "12/30"},
+		"tokens":   []any{"t1", "t2"},
+	}
+	tests := []struct {
+		name string
+		opts *jsonschema.RedactOptions
+		want map[string]any
+	}{
+		{"writeOnly", nil, map[string]any{
+			"user":   "john",
+			"card":   map[string]any{"number": "1234", "expiry": "12/30"},
+			"tokens": []any{"t1", "t2"},
+		}},
+		{"keywords", &jsonschema.RedactOptions{Keywords: []string{"x-sensitive"}}, map[string]any{
+			"user":   "john",
+			"card":   map[string]any{"expiry": "12/30"},
+			"tokens": []any{nil, nil},
+		}},
+		{"mask", &jsonschema.RedactOptions{Keywords: []string{"x-sensitive"}, Mask: "***"}, map[string]any{
+			"user":     "john",
+			"password": "***",
+			"card":     map[string]any{"number": "***", "expiry": "12/30"},
+			"tokens":   []any{"***", "***"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := sch.Redact(inst, test.opts)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+	if inst["password"] != "secret" {
+		t.Fatal("instance is modified")
+	}
+}
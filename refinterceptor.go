@@ -0,0 +1,90 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RefInterceptor is called when a reference is about to be resolved.
+// It gets the location of the referring keyword, and the absolute
+// url being referred, with fragment. It returns the url to be used
+// instead, or target itself to resolve as usual. The url returned,
+// if different from target and doc is nil, is intercepted again.
+//
+// If doc is not nil, it is used as the document of returned url,
+// unless a document is already loaded for that url. This can be
+// used to resolve references against a registry of models:
+//
+//	c.UseRefInterceptor(func(loc, target string) (string, any, error) {
+//		name, ok := strings.CutPrefix(target, "internal://model/")
+//		if !ok {
+//			return target, nil, nil
+//		}
+//		doc, err := registry.Lookup(strings.TrimSuffix(name, "#"))
+//		return target, doc, err
+//	})
+//
+// It is called for `$ref`, `$recursiveRef`, `$dynamicRef`, and for
+// references enqueued by vocabularies using [CompilerContext.EnqueueRef].
+type RefInterceptor func(loc, target string) (url string, doc any, err error)
+
+// UseRefInterceptor sets the hook called before resolving each
+// reference, in the schemas compiled after this call.
+func (c *Compiler) UseRefInterceptor(fn RefInterceptor) {
+	c.refInterceptor = fn
+}
+
+// interceptRef returns url to be used for uf, which is
+// referred at loc. The url returned by interceptor is
+// intercepted again, until it is not rewritten.
+func (c *Compiler) interceptRef(loc string, uf *urlFrag) (*urlFrag, error) {
+	if c.refInterceptor == nil {
+		return uf, nil
+	}
+	seen := map[string]bool{}
+	for {
+		target := uf.String()
+		if seen[target] {
+			return nil, &RefInterceptorError{Location: loc, Target: target, Err: errRewriteCycle}
+		}
+		seen[target] = true
+		u, doc, err := c.refInterceptor(loc, target)
+		if err != nil {
+			return nil, &RefInterceptorError{Location: loc, Target: target, Err: err}
+		}
+		if u != target {
+			if uf, err = absolute(u); err != nil {
+				return nil, err
+			}
+		}
+		if doc != nil && !isMeta(string(uf.url)) {
+			c.roots.loader.add(uf.url, doc)
+		}
+		if u == target || doc != nil {
+			return uf, nil
+		}
+	}
+}
+
+var errRewriteCycle = errors.New("reference rewrite cycle")
+
+// --
+
+// RefInterceptorError is returned, if [RefInterceptor] fails.
+type RefInterceptorError struct {
+	// Location is the location of referring keyword.
+	Location string
+
+	// Target is the absolute url, being referred.
+	Target string
+
+	Err error
+}
+
+func (e *RefInterceptorError) Error() string {
+	return fmt.Sprintf("intercepting reference %q at %q: %v", e.Target, e.Location, e.Err)
+}
+
+func (e *RefInterceptorError) Unwrap() error {
+	return e.Err
+}
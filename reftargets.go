@@ -0,0 +1,64 @@
+package jsonschema
+
+// RefTarget is a reference from a keyword in schema,
+// to the schema it is resolved to at compile time.
+type RefTarget struct {
+	// KeywordLocation is absolute location of the keyword,
+	// such as "http://example.com/schema.json#/oneOf/1/$ref".
+	KeywordLocation string
+
+	// Keyword is one of `$ref`, `$recursiveRef`, `$dynamicRef`.
+	Keyword string
+
+	// Source is the schema containing the keyword.
+	Source *Schema
+
+	// Target is the resolved schema. For `$recursiveRef` and
+	// `$dynamicRef`, it is the initial target, which may be
+	// overridden by dynamic scope during validation.
+	Target *Schema
+}
+
+// RefTargets returns references in sch and in all schemas
+// reachable from it, through subschemas and references.
+// Each reference is reported once, in deterministic order.
+func (sch *Schema) RefTargets() []*RefTarget {
+	var targets []*RefTarget
+	walkSchemas(sch, func(s *Schema) {
+		add := func(kw string, target *Schema) {
+			if target == nil {
+				return
+			}
+			targets = append(targets, &RefTarget{
+				KeywordLocation: s.Location + "/" + kw,
+				Keyword:         kw,
+				Source:          s,
+				Target:          target,
+			})
+		}
+		add("$ref", s.Ref)
+		add("$recursiveRef", s.RecursiveRef)
+		if s.DynamicRef != nil {
+			add("$dynamicRef", s.DynamicRef.Ref)
+		}
+	})
+	return targets
+}
+
+// walkSchemas calls fn for sch and all schemas reachable from
+// it through subschemas and references, in breadth-first order.
+func walkSchemas(sch *Schema, fn func(*Schema)) {
+	seen := map[*Schema]bool{sch: true}
+	queue := []*Schema{sch}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		fn(s)
+		for _, next := range append(s.subschemas(), s.refs()...) {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+}
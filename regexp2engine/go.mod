@@ -0,0 +1,12 @@
+module github.com/santhosh-tekuri/jsonschema/regexp2engine
+
+go 1.21.1
+
+require (
+	github.com/dlclark/regexp2 v1.11.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.1
+)
+
+require golang.org/x/text v0.14.0 // indirect
+
+// replace github.com/santhosh-tekuri/jsonschema/v6 v6.0.1 => ../
@@ -0,0 +1,98 @@
+// Package regexp2engine provides [jsonschema.RegexpEngine] implemented
+// using [github.com/dlclark/regexp2].
+//
+// Unlike regexp package from go standard library, regexp2 is a
+// backtracking engine which supports ECMA 262 features like
+// lookarounds and backreferences. Because matching can take
+// exponential time, set a match timeout when validating
+// untrusted instances.
+//
+// By default, a timed out match fails the validation with
+// [jsonschema.RegexpMatchError]. Reporting it as not matched
+// instead, fails open: a value matching a deny-list pattern under
+// `not` is accepted, and a property matching `patternProperties`
+// skips its schema. So use [Options.FailOpen] only if such bypass
+// is acceptable.
+package regexp2engine
+
+import (
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Options are used to configure RegexpEngine.
+type Options struct {
+	// RegexOptions passed to regexp2.
+	// If zero, regexp2.ECMAScript is used.
+	RegexOptions regexp2.RegexOptions
+
+	// MatchTimeout is the maximum duration for single match.
+	// If zero, there is no timeout.
+	MatchTimeout time.Duration
+
+	// FailOpen reports string as not matched on timeout,
+	// rather than failing the validation. see package doc.
+	FailOpen bool
+}
+
+// New returns RegexpEngine using given options.
+// If opts is nil, default options are used.
+func New(opts *Options) jsonschema.RegexpEngine {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.RegexOptions == 0 {
+		o.RegexOptions = regexp2.ECMAScript
+	}
+	return func(s string) (jsonschema.Regexp, error) {
+		re, err := regexp2.Compile(s, o.RegexOptions)
+		if err != nil {
+			return nil, err
+		}
+		if o.MatchTimeout > 0 {
+			re.MatchTimeout = o.MatchTimeout
+		}
+		if o.FailOpen {
+			return (*failOpenRegexp)(re), nil
+		}
+		return (*regexp)(re), nil
+	}
+}
+
+// Compile is RegexpEngine with default options.
+func Compile(s string) (jsonschema.Regexp, error) {
+	return New(nil)(s)
+}
+
+type regexp regexp2.Regexp
+
+// MatchString reports string as not matched on timeout.
+// It is used where the timeout cannot be reported.
+func (re *regexp) MatchString(s string) bool {
+	matched, err := re.TryMatchString(s)
+	return err == nil && matched
+}
+
+// TryMatchString implements [jsonschema.FallibleRegexp].
+func (re *regexp) TryMatchString(s string) (bool, error) {
+	return (*regexp2.Regexp)(re).MatchString(s)
+}
+
+func (re *regexp) String() string {
+	return (*regexp2.Regexp)(re).String()
+}
+
+// failOpenRegexp does not implement jsonschema.FallibleRegexp,
+// so that timeouts are reported as not matched.
+type failOpenRegexp regexp2.Regexp
+
+func (re *failOpenRegexp) MatchString(s string) bool {
+	return (*regexp)(re).MatchString(s)
+}
+
+func (re *failOpenRegexp) String() string {
+	return (*regexp2.Regexp)(re).String()
+}
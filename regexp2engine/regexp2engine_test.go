@@ -0,0 +1,82 @@
+package regexp2engine_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"github.com/santhosh-tekuri/jsonschema/regexp2engine"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestCompile(t *testing.T) {
+	// golang regexp does not support lookarounds
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"type": "string",
+		"pattern": "^(?!admin$)[a-z]+$"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	c.UseRegexpEngine(regexp2engine.Compile)
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate("john"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate("admin"); err == nil {
+		t.Fatal("want validation to fail")
+	}
+}
+
+func TestMatchTimeout(t *testing.T) {
+	engine := regexp2engine.New(&regexp2engine.Options{
+		RegexOptions: regexp2.RE2,
+		MatchTimeout: 10 * time.Millisecond,
+	})
+	re, err := engine(`^(a+)+$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("aaaa") {
+		t.Fatal("want match")
+	}
+	if re.MatchString(strings.Repeat("a", 64) + "!") {
+		t.Fatal("want no match on timeout")
+	}
+}
+
+func TestMatchTimeoutUnderNot(t *testing.T) {
+	compile := func(failOpen bool) *jsonschema.Schema {
+		t.Helper()
+		c := jsonschema.NewCompiler()
+		c.UseRegexpEngine(regexp2engine.New(&regexp2engine.Options{
+			MatchTimeout: 10 * time.Millisecond,
+			FailOpen:     failOpen,
+		}))
+		// deny-list pattern
+		sch, err := c.CompileValue(map[string]any{"not": map[string]any{"pattern": "^(a+)+$"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+	evil := strings.Repeat("a", 64) + "!"
+
+	// fails closed by default
+	if err := compile(false).Validate(evil); err == nil {
+		t.Fatal("want error on timeout")
+	}
+
+	// fail open accepts the value, bypassing the deny-list
+	if err := compile(true).Validate(evil); err != nil {
+		t.Fatalf("want fail open, got %v", err)
+	}
+}
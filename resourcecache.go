@@ -0,0 +1,100 @@
+package jsonschema
+
+import "sync"
+
+// ResourceCache is a cache of loaded schema documents, which can
+// be shared by multiple [Compiler] instances, with different
+// options and vocabularies. This avoids fetching and parsing same
+// remote schemas, such as metaschemas, in every compiler.
+//
+// It is safe for concurrent use. Concurrent loads of same url are
+// coalesced into single load. Failed loads are not cached.
+//
+// Share the cache only between compilers whose loaders return same
+// document for same url. The cached documents must not be modified.
+//
+// The zero value is ready to use.
+type ResourceCache struct {
+	mu      sync.Mutex
+	entries map[string]*resourceEntry
+}
+
+type resourceEntry struct {
+	done chan struct{} // closed when loaded
+	doc  any
+	err  error
+}
+
+// UseResourceCache makes compiler use rc for loading schema
+// documents. The load policy, limits and [Compiler.LoadedResources]
+// still apply to urls found in rc. The loader and its middlewares
+// are not consulted for urls found in rc.
+func (c *Compiler) UseResourceCache(rc *ResourceCache) {
+	c.roots.loader.cache = rc
+}
+
+// Len returns number of documents in cache.
+func (rc *ResourceCache) Len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	n := 0
+	for _, e := range rc.entries {
+		select {
+		case <-e.done:
+			n++
+		default:
+		}
+	}
+	return n
+}
+
+// Delete removes document of given url from cache, so that
+// it is loaded again when needed.
+func (rc *ResourceCache) Delete(url string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.entries, url)
+}
+
+// Clear removes all documents from cache.
+func (rc *ResourceCache) Clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = nil
+}
+
+// load returns document with given key from cache, loading it
+// using fn if not present. If fn returns nil document, it is not
+// cached. If rc is nil, it simply calls fn.
+func (rc *ResourceCache) load(key string, fn func() (any, error)) (any, error) {
+	if rc == nil {
+		return fn()
+	}
+	rc.mu.Lock()
+	if e, ok := rc.entries[key]; ok {
+		rc.mu.Unlock()
+		<-e.done
+		if e.err == nil && e.doc != nil {
+			return e.doc, nil
+		}
+		// failed or not found, load again
+		return fn()
+	}
+	e := &resourceEntry{done: make(chan struct{})}
+	if rc.entries == nil {
+		rc.entries = map[string]*resourceEntry{}
+	}
+	rc.entries[key] = e
+	rc.mu.Unlock()
+
+	e.doc, e.err = fn()
+	close(e.done)
+	if e.err != nil || e.doc == nil {
+		rc.mu.Lock()
+		if rc.entries[key] == e {
+			delete(rc.entries, key)
+		}
+		rc.mu.Unlock()
+	}
+	return e.doc, e.err
+}
@@ -12,6 +12,12 @@ type roots struct {
 	regexpEngine RegexpEngine
 	vocabularies map[string]*Vocabulary
 	assertVocabs bool
+	dataRef      bool
+	draft3       bool            // see Compiler.EnableDraft3
+	metaschemas  map[url]*Schema // see Compiler.OverrideMetaschema
+
+	regexpChain               bool // regexpEngine set using UseRegexpEngines
+	ignoreUnsupportedPatterns bool
 }
 
 func newRoots() *roots {
@@ -19,11 +25,13 @@ func newRoots() *roots {
 		defaultDraft: draftLatest,
 		roots:        map[url]*root{},
 		loader: defaultLoader{
-			docs:   map[url]any{},
-			loader: FileLoader{},
+			docs:     map[url]any{},
+			loader:   registeredLoader{},
+			dialects: map[url]*Dialect{},
 		},
 		regexpEngine: goRegexpCompile,
 		vocabularies: map[string]*Vocabulary{},
+		metaschemas:  map[url]*Schema{},
 	}
 }
 
@@ -39,13 +47,16 @@ func (rr *roots) orLoad(u url) (*root, error) {
 }
 
 func (rr *roots) addRoot(u url, doc any) (*root, error) {
+	if rr.draft3 && isDraft3(doc) {
+		doc, _ = draft3To4(doc)
+	}
 	r := &root{
 		url:                 u,
 		doc:                 doc,
 		resources:           map[jsonPointer]*resource{},
 		subschemasProcessed: map[jsonPointer]struct{}{},
 	}
-	if err := rr.collectResources(r, doc, u, "", dialect{rr.defaultDraft, nil}); err != nil {
+	if err := rr.collectResources(r, doc, u, "", dialect{rr.defaultDraft, nil, "", rr.metaschemas}); err != nil {
 		return nil, err
 	}
 	if !strings.HasPrefix(u.String(), "http://json-schema.org/") &&
@@ -79,19 +90,17 @@ func (rr *roots) collectResources(r *root, sch any, base url, schPtr jsonPointer
 }
 
 func (rr *roots) _collectResources(r *root, sch any, base url, schPtr jsonPointer, fallback dialect) error {
-	if _, ok := sch.(bool); ok {
+	obj, ok := sch.(map[string]any)
+	if !ok {
 		if schPtr.isEmpty() {
-			// root resource
+			// root resource, non-object is reported
+			// during metaschema validation
 			res := newResource(schPtr, base)
 			res.dialect = fallback
 			r.resources[schPtr] = res
 		}
 		return nil
 	}
-	obj, ok := sch.(map[string]any)
-	if !ok {
-		return nil
-	}
 
 	hasSchema := false
 	if sch, ok := obj["$schema"]; ok {
@@ -142,7 +151,9 @@ func (rr *roots) _collectResources(r *root, sch any, base url, schPtr jsonPointe
 				if err != nil {
 					return err
 				}
-				res.dialect = dialect{draft, vocabs}
+				metaURL, _ := strVal(obj, "$schema")
+				metaURL, _ = split(metaURL)
+				res.dialect = dialect{draft, vocabs, metaURL, rr.metaschemas}
 			} else {
 				res.dialect = fallback
 			}
@@ -158,7 +169,8 @@ func (rr *roots) _collectResources(r *root, sch any, base url, schPtr jsonPointe
 		}
 	}
 	if baseRes == nil {
-		panic("baseres is nil")
+		loc := urlPtr{r.url, schPtr}
+		return &MissingBaseResourceError{URL: loc.String(), Base: base.String()}
 	}
 
 	// found base resource
@@ -205,6 +217,11 @@ func (rr *roots) ensureSubschema(up urlPtr) error {
 	if err != nil {
 		return err
 	}
+	switch v.(type) {
+	case bool, map[string]any:
+	default:
+		return &NotASchemaError{URL: up.String(), Got: typeOf(v).String()}
+	}
 	rClone := r.clone()
 	if err := rr.addSubschema(rClone, up.ptr); err != nil {
 		return err
@@ -240,15 +257,53 @@ func (rr *roots) addSubschema(r *root, ptr jsonPointer) error {
 func (rr *roots) validate(r *root, v any, ptr jsonPointer) error {
 	dialect := r.resource(ptr).dialect
 	meta := dialect.getSchema(rr.assertVocabs, rr.vocabularies)
-	if err := meta.validate(v, rr.regexpEngine, meta, r.resources, rr.assertVocabs, rr.vocabularies); err != nil {
+	if rr.dataRef {
+		v = stripDataRefs(v)
+	}
+	regexpEngine := rr.regexpEngine
+	if rr.regexpChain || rr.ignoreUnsupportedPatterns {
+		regexpEngine = acceptAllRegexps
+	}
+	if err := meta.validate(v, regexpEngine, meta, r.resources, rr.assertVocabs, rr.vocabularies, nil); err != nil {
 		up := urlPtr{r.url, ptr}
-		return &SchemaValidationError{URL: up.String(), Err: err}
+		return &SchemaValidationError{URL: up.String(), Err: err, up: up}
 	}
 	return nil
 }
 
 // --
 
+// NotASchemaError is returned by [Compiler.Compile], if a reference
+// points to json value which is neither boolean nor object, such as
+// an item of `examples`.
+//
+// Locations inside custom keywords must be listed in
+// [Vocabulary.Subschemas], to be treated as subschemas.
+type NotASchemaError struct {
+	URL string // location of the value
+	Got string // json type of the value
+}
+
+func (e *NotASchemaError) Error() string {
+	return fmt.Sprintf("%q is not a schema: want boolean or object, but got %s", e.URL, e.Got)
+}
+
+// --
+
+// MissingBaseResourceError is returned by [Compiler.Compile], if the
+// resource, which the schema at URL belongs to, is not found. This
+// indicates a bug in the library, rather than in the schema.
+type MissingBaseResourceError struct {
+	URL  string // location of the schema
+	Base string // url of the missing resource
+}
+
+func (e *MissingBaseResourceError) Error() string {
+	return fmt.Sprintf("base resource %q of schema at %q is missing", e.Base, e.URL)
+}
+
+// --
+
 type InvalidMetaSchemaURLError struct {
 	URL string
 	Err error
@@ -4,17 +4,38 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"slices"
+	"strings"
 )
 
 // Schema is the regpresentation of a compiled
 // jsonschema.
+//
+// Schema is safe for concurrent validation. Its exported fields
+// are for introspection, and must not be modified after compilation.
+// Use [Schema.Freeze] to detect such modification.
 type Schema struct {
 	up                urlPtr
 	resource          *Schema
+	dialect           dialect
 	dynamicAnchors    map[string]*Schema
 	allPropsEvaluated bool
 	allItemsEvaluated bool
 	numItemsEvaluated int
+	dataRefs          *dataRefs
+	strictIntegers    bool // 1.0 is not integer
+	lengthUnit        LengthUnit
+	warnFloat         func(string, any)
+	oneOfIndex        *oneOfIndex // nil if oneOf is not dispatchable
+	formatter         Formatter
+	instrumentation   Instrumentation
+	doc               any // json value, this schema is compiled from
+
+	// propertyNames has no assertions other than
+	// minLength, maxLength and pattern
+	stringPropertyNames bool
+
+	frozen []frozenSchema // state of reachable schemas, see Freeze
 
 	DraftVersion int
 	Location     string
@@ -58,10 +79,10 @@ type Schema struct {
 	MaxItems         *int
 	UniqueItems      bool
 	Contains         *Schema
-	MinContains      *int
-	MaxContains      *int
-	Items            any // nil or []*Schema or *Schema
-	AdditionalItems  any // nil or bool or *Schema
+	MinContains      *int // see ContainsConstraint
+	MaxContains      *int // see ContainsConstraint
+	Items            any  // nil or []*Schema or *Schema
+	AdditionalItems  any  // nil or bool or *Schema
 	PrefixItems      []*Schema
 	Items2020        *Schema
 	UnevaluatedItems *Schema
@@ -81,6 +102,13 @@ type Schema struct {
 	ExclusiveMinimum *big.Rat
 	MultipleOf       *big.Rat
 
+	// ExclusiveMaximumBool and ExclusiveMinimumBool are the values of
+	// boolean `exclusiveMaximum` and `exclusiveMinimum` in draft-04,
+	// nil if not specified. If true, the bound is moved from Maximum
+	// or Minimum to ExclusiveMaximum or ExclusiveMinimum.
+	ExclusiveMaximumBool *bool
+	ExclusiveMinimumBool *bool
+
 	Extensions []SchemaExt
 
 	// annotations --
@@ -94,6 +122,34 @@ type Schema struct {
 	Deprecated  bool
 }
 
+// Draft returns the draft used to compile this schema.
+func (sch *Schema) Draft() *Draft {
+	if sch.dialect.draft == nil {
+		return draftFromVersion(sch.DraftVersion)
+	}
+	return sch.dialect.draft
+}
+
+// DialectURL returns the metaschema url this schema
+// is compiled with. It is value of `$schema` if
+// specified, otherwise url of the draft used.
+func (sch *Schema) DialectURL() string {
+	if sch.dialect.draft == nil {
+		return sch.Draft().url
+	}
+	return sch.dialect.metaURL()
+}
+
+// Vocabularies returns urls of vocabularies enabled
+// for this schema. Returns nil for draft < 2019-09,
+// which do not support vocabularies.
+func (sch *Schema) Vocabularies() []string {
+	if sch.dialect.draft == nil {
+		return nil
+	}
+	return sch.dialect.vocabURLs()
+}
+
 // --
 
 type jsonType int
@@ -170,6 +226,52 @@ func (jt jsonType) String() string {
 
 // --
 
+// Type is the type of json value, as returned by [TypeOf].
+type Type int
+
+const (
+	TypeNull    = Type(nullType)
+	TypeBoolean = Type(booleanType)
+	TypeNumber  = Type(numberType)
+	TypeInteger = Type(integerType) // number with integer value
+	TypeString  = Type(stringType)
+	TypeArray   = Type(arrayType)
+	TypeObject  = Type(objectType)
+)
+
+// TypeOf returns json type of v, which must be in form accepted
+// by [Schema.Validate]. Numbers with integer value, such as 1, 1.0
+// and json.Number("1e2") are reported as [TypeInteger], consistent
+// with `type` keyword of draft6 onwards. Use [Type.IsNumber] to check
+// for any number.
+//
+// It returns [*InvalidJSONValueError] if v is not a json value.
+// Note that only v is checked, not its array items or property values.
+func TypeOf(v any) (Type, error) {
+	t := typeOf(v)
+	switch t {
+	case invalidType:
+		return 0, &InvalidJSONValueError{Value: v}
+	case numberType:
+		if isInteger(v) {
+			return TypeInteger, nil
+		}
+	}
+	return Type(t), nil
+}
+
+// IsNumber tells whether t is [TypeNumber] or [TypeInteger].
+func (t Type) IsNumber() bool {
+	return t == TypeNumber || t == TypeInteger
+}
+
+// String returns name of t, as used in `type` keyword.
+func (t Type) String() string {
+	return jsonType(t).String()
+}
+
+// --
+
 // Types encapsulates list of json value types.
 type Types int
 
@@ -246,3 +348,136 @@ type DynamicRef struct {
 func newSchema(up urlPtr) *Schema {
 	return &Schema{up: up, Location: up.String()}
 }
+
+// refs returns schemas referenced using $ref, $recursiveRef
+// and $dynamicRef.
+func (sch *Schema) refs() []*Schema {
+	var refs []*Schema
+	if sch.Ref != nil {
+		refs = append(refs, sch.Ref)
+	}
+	if sch.RecursiveRef != nil {
+		refs = append(refs, sch.RecursiveRef)
+	}
+	if sch.DynamicRef != nil && sch.DynamicRef.Ref != nil {
+		refs = append(refs, sch.DynamicRef.Ref)
+	}
+	return refs
+}
+
+// subschemas returns immediate subschemas, in deterministic order.
+// It does not include refs and subschemas of extensions.
+func (sch *Schema) subschemas() []*Schema {
+	var subs []*Schema
+	add := func(s ...*Schema) {
+		for _, s := range s {
+			if s != nil {
+				subs = append(subs, s)
+			}
+		}
+	}
+	addAny := func(v any) {
+		switch v := v.(type) {
+		case *Schema:
+			add(v)
+		case []*Schema:
+			add(v...)
+		}
+	}
+	addMap := func(m map[string]*Schema) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			add(m[k])
+		}
+	}
+
+	add(sch.Not)
+	add(sch.AllOf...)
+	add(sch.AnyOf...)
+	add(sch.OneOf...)
+	add(sch.If, sch.Then, sch.Else)
+	add(sch.PropertyNames)
+	addMap(sch.Properties)
+	for _, re := range sortedPatterns(sch.PatternProperties) {
+		add(sch.PatternProperties[re])
+	}
+	addAny(sch.AdditionalProperties)
+	deps := make([]string, 0, len(sch.Dependencies))
+	for pname := range sch.Dependencies {
+		deps = append(deps, pname)
+	}
+	slices.Sort(deps)
+	for _, pname := range deps {
+		addAny(sch.Dependencies[pname])
+	}
+	addMap(sch.DependentSchemas)
+	add(sch.UnevaluatedProperties)
+	add(sch.Contains)
+	addAny(sch.Items)
+	addAny(sch.AdditionalItems)
+	add(sch.PrefixItems...)
+	add(sch.Items2020)
+	add(sch.UnevaluatedItems)
+	add(sch.ContentSchema)
+	return subs
+}
+
+// sortedPatterns returns keys of m, sorted by their string.
+func sortedPatterns(m map[Regexp]*Schema) []Regexp {
+	patterns := make([]Regexp, 0, len(m))
+	for re := range m {
+		patterns = append(patterns, re)
+	}
+	slices.SortFunc(patterns, func(a, b Regexp) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return patterns
+}
+
+// --
+
+// ContainsConstraint is the effective constraint of `contains`,
+// along with `minContains` and `maxContains`, as per the draft
+// of schema. see [Schema.ContainsConstraint].
+type ContainsConstraint struct {
+	// Schema is the schema, which items are matched against.
+	Schema *Schema
+
+	// Min is minimum number of items, which must match Schema.
+	// It is 1, unless `minContains` is specified in draft >= 2019-09.
+	// If it is 0, `contains` never fails on its own, but still
+	// evaluates the items for `maxContains` and `unevaluatedItems`.
+	Min int
+
+	// Max is maximum number of items, which may match Schema.
+	// It is -1 if unbounded.
+	Max int
+}
+
+// ContainsConstraint returns the effective `contains` constraint
+// of sch, nil if sch has no `contains`. Use this instead of
+// interpreting Contains, MinContains and MaxContains fields, whose
+// meaning depends on draft.
+func (sch *Schema) ContainsConstraint() *ContainsConstraint {
+	if sch.Contains == nil {
+		return nil
+	}
+	cc := &ContainsConstraint{Schema: sch.Contains, Min: 1, Max: -1}
+	if sch.MinContains != nil {
+		cc.Min = *sch.MinContains
+	}
+	if sch.MaxContains != nil {
+		cc.Max = *sch.MaxContains
+	}
+	return cc
+}
+
+// Trivial tells whether the constraint is satisfied
+// by any array, i.e. Min is 0 and Max is unbounded.
+func (cc *ContainsConstraint) Trivial() bool {
+	return cc.Min == 0 && cc.Max < 0
+}
@@ -0,0 +1,153 @@
+// Package schemastore resolves schemas for files by name, using
+// catalog in the format published by [schemastore.org], as done
+// by editors for well-known config files.
+//
+// The catalog can be vendored, for example using go:embed, to
+// avoid network access:
+//
+//	//go:embed catalog.json
+//	var catalogJSON []byte
+//
+//	cat, err := schemastore.Load(bytes.NewReader(catalogJSON))
+//	...
+//	sch, err := cat.Compile(c, ".github/workflows/ci.yml")
+//
+// [schemastore.org]: https://www.schemastore.org
+package schemastore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// CatalogURL is the url of catalog published by schemastore.org.
+const CatalogURL = "https://www.schemastore.org/api/json/catalog.json"
+
+// Catalog lists schemas for well-known files.
+type Catalog struct {
+	Version int      `json:"version"`
+	Schemas []*Entry `json:"schemas"`
+}
+
+// Entry describes schema of a file format.
+type Entry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// FileMatch lists glob patterns of the file paths, this
+	// schema applies to. Patterns without '/' match against file
+	// name only. "**" matches any number of directories, and
+	// pattern prefixed with '!' excludes the matching paths.
+	FileMatch []string `json:"fileMatch"`
+
+	// URL is the url of latest version of schema.
+	URL string `json:"url"`
+
+	// Versions maps version names to schema urls.
+	Versions map[string]string `json:"versions"`
+}
+
+// Load decodes catalog from r.
+func Load(r io.Reader) (*Catalog, error) {
+	var cat Catalog
+	if err := json.NewDecoder(r).Decode(&cat); err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// Lookup returns the first entry in catalog, whose FileMatch
+// matches the given file path. It returns nil if none matches.
+func (cat *Catalog) Lookup(file string) *Entry {
+	file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+	for _, e := range cat.Schemas {
+		if e.URL != "" && e.matches(file) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Compile compiles the schema for given file path using c.
+// Loaders of c must support the scheme of schema url.
+// It returns [*NoMatchError] if no entry matches the file.
+func (cat *Catalog) Compile(c *jsonschema.Compiler, file string) (*jsonschema.Schema, error) {
+	e := cat.Lookup(file)
+	if e == nil {
+		return nil, &NoMatchError{file}
+	}
+	return c.Compile(e.URL)
+}
+
+func (e *Entry) matches(file string) bool {
+	matched := false
+	for _, pattern := range e.FileMatch {
+		if neg, ok := strings.CutPrefix(pattern, "!"); ok {
+			if matchGlob(neg, file) {
+				return false
+			}
+		} else if !matched {
+			matched = matchGlob(pattern, file)
+		}
+	}
+	return matched
+}
+
+// matchGlob tells whether file matches glob pattern.
+func matchGlob(pattern, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		file = path.Base(file)
+	} else {
+		// patterns match at any depth
+		pattern = strings.TrimPrefix(pattern, "/")
+		if !strings.HasPrefix(pattern, "**/") {
+			pattern = "**/" + pattern
+		}
+	}
+	re, err := regexp.Compile(globRegexp(pattern))
+	return err == nil && re.MatchString(file)
+}
+
+// globRegexp converts glob pattern into regexp.
+func globRegexp(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**/") {
+				sb.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(pattern[i:], "**") {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// --
+
+// NoMatchError is returned by [Catalog.Compile],
+// if no catalog entry matches the file.
+type NoMatchError struct {
+	File string
+}
+
+func (e *NoMatchError) Error() string {
+	return fmt.Sprintf("no schema found in catalog for %q", e.File)
+}
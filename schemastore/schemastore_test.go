@@ -0,0 +1,82 @@
+package schemastore_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/schemastore"
+)
+
+const catalog = `{
+	"version": 1,
+	"schemas": [
+		{
+			"name": "GitHub Workflow",
+			"fileMatch": ["**/.github/workflows/*.yml", "**/.github/workflows/*.yaml"],
+			"url": "https://json.schemastore.org/github-workflow.json"
+		},
+		{
+			"name": "package.json",
+			"fileMatch": ["package.json", "!**/node_modules/**/package.json"],
+			"url": "https://json.schemastore.org/package.json"
+		},
+		{
+			"name": "tsconfig",
+			"fileMatch": ["tsconfig.json", "tsconfig.*.json"],
+			"url": "https://json.schemastore.org/tsconfig.json"
+		}
+	]
+}`
+
+func TestLookup(t *testing.T) {
+	cat, err := schemastore.Load(strings.NewReader(catalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file string
+		want string // name of entry
+	}{
+		{".github/workflows/ci.yml", "GitHub Workflow"},
+		{"/home/x/repo/.github/workflows/ci.yaml", "GitHub Workflow"},
+		{".github/ci.yml", ""},
+		{"package.json", "package.json"},
+		{"web/package.json", "package.json"},
+		{"node_modules/a/package.json", ""},
+		{`web\tsconfig.build.json`, "tsconfig"},
+		{"tsconfig.json.bak", ""},
+	}
+	for _, test := range tests {
+		got := ""
+		if e := cat.Lookup(test.file); e != nil {
+			got = e.Name
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.file, got, test.want)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	cat, err := schemastore.Load(strings.NewReader(catalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("https://json.schemastore.org/package.json", map[string]any{"required": []any{"name"}}); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := cat.Compile(c, "package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(map[string]any{}); err == nil {
+		t.Fatal("want validation error")
+	}
+
+	_, err = cat.Compile(c, "config.toml")
+	if _, ok := err.(*schemastore.NoMatchError); !ok {
+		t.Fatalf("got %v, want *NoMatchError", err)
+	}
+}
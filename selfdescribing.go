@@ -0,0 +1,50 @@
+package jsonschema
+
+import "fmt"
+
+// ValidateSelfDescribing validates instance against the schema
+// whose url is given by `$schema` member of instance, as done by
+// editors for config files. The schema is compiled using c, so
+// loaders and options of c apply. The `$schema` member itself is
+// not validated.
+//
+// If instance is not an object with string `$schema`, it returns
+// [*NoInstanceSchemaError].
+//
+// Note that the instance chooses which url is loaded. With default
+// loader, an untrusted instance can make it read local files, and
+// with http loader, fetch arbitrary urls (SSRF). So for such
+// instances, use c with a loader restricted to trusted urls, for
+// example using [Compiler.UseLoadPolicy] or [LoaderMiddleware], or
+// preload the allowed schemas and use a loader which rejects all.
+func ValidateSelfDescribing(c *Compiler, instance any) error {
+	obj, ok := instance.(map[string]any)
+	if !ok {
+		return &NoInstanceSchemaError{}
+	}
+	url, ok := obj["$schema"].(string)
+	if !ok {
+		return &NoInstanceSchemaError{}
+	}
+	sch, err := c.Compile(url)
+	if err != nil {
+		return err
+	}
+	rest := make(map[string]any, len(obj))
+	for pname, pvalue := range obj {
+		if pname != "$schema" {
+			rest[pname] = pvalue
+		}
+	}
+	return sch.Validate(rest)
+}
+
+// --
+
+// NoInstanceSchemaError is returned by [ValidateSelfDescribing],
+// if instance does not specify its schema.
+type NoInstanceSchemaError struct{}
+
+func (e *NoInstanceSchemaError) Error() string {
+	return fmt.Sprintf("instance has no string member %q", "$schema")
+}
@@ -0,0 +1,48 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestValidateSelfDescribing(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	schema := `{
+		"type": "object",
+		"properties": {"port": {"type": "integer"}},
+		"additionalProperties": false
+	}`
+	if err := c.AddResourceJSON("http://example.com/config.json", []byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"$schema": "http://example.com/config.json", "port": 80}`, true},
+		{`{"$schema": "http://example.com/config.json", "port": "80"}`, false},
+		{`{"$schema": "http://example.com/config.json", "host": "x"}`, false},
+	}
+	for _, test := range tests {
+		inst, err := jsonschema.UnmarshalJSON(strings.NewReader(test.instance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = jsonschema.ValidateSelfDescribing(c, inst)
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.instance, err)
+		}
+		if _, ok := err.(*jsonschema.ValidationError); !test.valid && !ok {
+			t.Errorf("%s: got %v, want *ValidationError", test.instance, err)
+		}
+	}
+
+	for _, inst := range []any{[]any{}, map[string]any{"port": 80}, map[string]any{"$schema": 1}} {
+		err := jsonschema.ValidateSelfDescribing(c, inst)
+		if _, ok := err.(*jsonschema.NoInstanceSchemaError); !ok {
+			t.Errorf("%v: got %v, want *NoInstanceSchemaError", inst, err)
+		}
+	}
+}
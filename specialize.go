@@ -0,0 +1,213 @@
+package jsonschema
+
+import "slices"
+
+// Specialize returns schema, which is sch partially evaluated for
+// objects having properties in known, with given values. This is
+// useful when instances of a large polymorphic schema, are known
+// to be of a specific variant, for example with "version": "2".
+//
+// The subschemas applied in-place to the instance, i.e. via `$ref`,
+// `allOf`, `anyOf`, `oneOf` and `if`-`then`-`else`, are specialized:
+//   - `anyOf` and `oneOf` subschemas which reject known properties
+//     are removed
+//   - `if`-`then`-`else` is replaced with `then` or `else`, if the
+//     outcome of `if` is decided by known properties
+//
+// The returned schema gives same result as sch, for objects having
+// known properties with given values. Validating other instances
+// with it gives undefined results. The values in known must be
+// in form, accepted by [Schema.Validate].
+//
+// The sch is not modified. The returned schema shares unchanged
+// subschemas with sch. It returns sch, if nothing is specialized.
+func (sch *Schema) Specialize(known map[string]any) *Schema {
+	sp := &specializer{known: known, copies: map[*Schema]*Schema{}}
+	return sp.schema(sch)
+}
+
+type specializer struct {
+	known  map[string]any
+	copies map[*Schema]*Schema // nil value if not specialized
+}
+
+func (sp *specializer) schema(sch *Schema) *Schema {
+	if sch == nil || sch.Bool != nil {
+		return sch
+	}
+	if c, ok := sp.copies[sch]; ok {
+		if c == nil {
+			return sch
+		}
+		return c
+	}
+
+	// copy is registered before recursion,
+	// so that cyclic references use it.
+	c := *sch
+	c.frozen = nil
+	sp.copies[sch] = &c
+	changed := false
+	sub := func(s *Schema) *Schema {
+		if ss := sp.schema(s); ss != s {
+			changed = true
+			return ss
+		}
+		return s
+	}
+	subs := func(arr []*Schema) []*Schema {
+		var result []*Schema
+		for i, s := range arr {
+			if ss := sp.schema(s); ss != s {
+				if result == nil {
+					result = slices.Clone(arr)
+				}
+				result[i] = ss
+			}
+		}
+		if result == nil {
+			return arr
+		}
+		changed = true
+		return result
+	}
+	prune := func(arr []*Schema) []*Schema {
+		result := slices.DeleteFunc(slices.Clone(arr), sp.rejects)
+		if len(result) == len(arr) || len(result) == 0 {
+			// if no branch can be valid, all are kept, so
+			// that errors reported are same as of sch
+			return subs(arr)
+		}
+		changed = true
+		return subs(result)
+	}
+
+	c.Ref = sub(sch.Ref)
+	c.AllOf = subs(sch.AllOf)
+	c.AnyOf = prune(sch.AnyOf)
+	c.OneOf = prune(sch.OneOf)
+	if len(c.OneOf) != len(sch.OneOf) && sch.oneOfIndex != nil {
+		c.oneOfIndex = newOneOfIndex(&c)
+	}
+	if sch.If != nil {
+		switch {
+		case sp.rejects(sch.If):
+			c.If, c.Then, c.Else = nil, nil, nil
+			if sch.Else != nil {
+				c.AllOf = append(slices.Clip(c.AllOf), sub(sch.Else))
+			}
+			changed = true
+		case sp.accepts(sch.If):
+			// if is kept for its annotations
+			c.If, c.Then, c.Else = nil, nil, nil
+			c.AllOf = append(slices.Clip(c.AllOf), sch.If)
+			if sch.Then != nil {
+				c.AllOf = append(c.AllOf, sub(sch.Then))
+			}
+			changed = true
+		default:
+			c.Then, c.Else = sub(sch.Then), sub(sch.Else)
+		}
+	}
+
+	if !changed {
+		sp.copies[sch] = nil
+		return sch
+	}
+	return &c
+}
+
+// rejects tells whether sch rejects every object,
+// having known properties.
+func (sp *specializer) rejects(sch *Schema) bool {
+	return sp.rejects1(sch, map[*Schema]bool{})
+}
+
+func (sp *specializer) rejects1(sch *Schema, visited map[*Schema]bool) bool {
+	if sch == nil || visited[sch] {
+		return false
+	}
+	visited[sch] = true
+	if sch.Bool != nil {
+		return !*sch.Bool
+	}
+	if sch.Types != nil && !sch.Types.contains(objectType) {
+		return true
+	}
+	for pname, pvalue := range sp.known {
+		if sch.PropertyNames != nil && !sch.PropertyNames.IsValid(pname) {
+			return true
+		}
+		if !matchesProps(sch, pname) {
+			if isFalse(sch.AdditionalProperties) {
+				return true
+			}
+			if s, ok := sch.AdditionalProperties.(*Schema); ok && !s.IsValid(pvalue) {
+				return true
+			}
+			continue
+		}
+		for _, s := range propSchemas([]*Schema{sch}, pname) {
+			if !s.IsValid(pvalue) {
+				return true
+			}
+		}
+	}
+	if sch.Ref != nil && sp.rejects1(sch.Ref, visited) {
+		return true
+	}
+	for _, s := range sch.AllOf {
+		if sp.rejects1(s, visited) {
+			return true
+		}
+	}
+	for _, arr := range [][]*Schema{sch.AnyOf, sch.OneOf} {
+		if len(arr) > 0 && !slices.ContainsFunc(arr, func(s *Schema) bool {
+			return !sp.rejects1(s, visited)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// accepts tells whether sch accepts every object,
+// having known properties.
+func (sp *specializer) accepts(sch *Schema) bool {
+	if sch.Bool != nil {
+		return *sch.Bool
+	}
+	obj, ok := sch.doc.(map[string]any)
+	if !ok {
+		return false
+	}
+	for kw := range obj {
+		switch kw {
+		case "properties", "required", "type", "title", "description", "$comment":
+		default:
+			return false
+		}
+	}
+	if sch.Types != nil && !sch.Types.contains(objectType) {
+		return false
+	}
+	for _, pname := range sch.Required {
+		if _, ok := sp.known[pname]; !ok {
+			return false
+		}
+	}
+	for pname, s := range sch.Properties {
+		pvalue, ok := sp.known[pname]
+		if !ok {
+			// instance may have it with any value
+			if s.Bool == nil || !*s.Bool {
+				return false
+			}
+			continue
+		}
+		if !s.IsValid(pvalue) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestSpecialize(t *testing.T) {
+	schema := `{
+		"$defs": {
+			"v1": {
+				"properties": {"version": {"const": "1"}, "name": {"type": "string"}},
+				"required": ["version", "name"]
+			},
+			"v2": {
+				"properties": {"version": {"const": "2"}, "title": {"type": "string"}},
+				"required": ["version", "title"]
+			}
+		},
+		"oneOf": [{"$ref": "#/$defs/v1"}, {"$ref": "#/$defs/v2"}],
+		"if": {"properties": {"version": {"const": "2"}}, "required": ["version"]},
+		"then": {"properties": {"size": {"maximum": 10}}},
+		"else": {"properties": {"size": {"maximum": 5}}}
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", mustUnmarshal(t, schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2 := sch.Specialize(map[string]any{"version": "2"})
+	if v2 == sch {
+		t.Fatal("not specialized")
+	}
+	if len(v2.OneOf) != 1 || v2.OneOf[0] != sch.OneOf[1] {
+		t.Fatalf("oneOf not pruned: %v", v2.OneOf)
+	}
+	if v2.If != nil || v2.Then != nil || v2.Else != nil {
+		t.Fatal("if-then-else not replaced")
+	}
+	if len(sch.OneOf) != 2 || sch.If == nil {
+		t.Fatal("sch modified")
+	}
+
+	for _, inst := range []string{
+		`{"version": "2", "title": "x", "size": 7}`,
+		`{"version": "2", "title": "x", "size": 11}`,
+		`{"version": "2", "name": "x"}`,
+		`{"version": "2", "title": 1}`,
+	} {
+		v := mustUnmarshal(t, inst)
+		if got, want := v2.IsValid(v), sch.IsValid(v); got != want {
+			t.Errorf("%s: got valid %v, want %v", inst, got, want)
+		}
+	}
+
+	if got := sch.Specialize(map[string]any{"other": 1}); got != sch {
+		t.Fatal("want sch, if nothing is specialized")
+	}
+}
@@ -0,0 +1,47 @@
+package jsonschema
+
+import "unicode/utf8"
+
+// LengthUnit is the unit in which `minLength` and `maxLength`
+// measure length of strings. see [Compiler.UseLengthUnit].
+type LengthUnit int
+
+const (
+	// LengthRunes measures length in unicode code points,
+	// as required by specification. This is the default.
+	LengthRunes LengthUnit = iota
+
+	// LengthBytes measures length in bytes of utf-8 encoding.
+	// This is useful when strings are stored in databases or
+	// protobuf fields, which limit size in bytes.
+	LengthBytes
+
+	// LengthGraphemes measures length in user-perceived characters,
+	// i.e. extended grapheme clusters of Unicode Standard Annex #29.
+	// For example, emoji "👍🏽" and "e" followed by combining acute
+	// accent are of length 1. This is useful to limit length of
+	// fields displayed to users, such as display names.
+	LengthGraphemes
+)
+
+// UseLengthUnit sets the unit in which `minLength` and `maxLength`
+// measure length of strings, in schemas compiled after this call.
+// This also applies to `propertyNames`, and to the lengths reported
+// in [kind.MinLength] and [kind.MaxLength].
+//
+// Note that this deviates from specification, for units other than
+// [LengthRunes].
+func (c *Compiler) UseLengthUnit(u LengthUnit) {
+	c.lengthUnit = u
+}
+
+// length returns length of s in unit u.
+func (u LengthUnit) length(s string) int {
+	switch u {
+	case LengthBytes:
+		return len(s)
+	case LengthGraphemes:
+		return graphemeCount(s)
+	}
+	return utf8.RuneCountInString(s)
+}
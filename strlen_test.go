@@ -0,0 +1,59 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestLengthUnit(t *testing.T) {
+	schema := map[string]any{
+		"maxLength":     3,
+		"propertyNames": map[string]any{"minLength": 4},
+	}
+	tests := []struct {
+		unit  jsonschema.LengthUnit
+		str   string
+		valid bool
+	}{
+		{jsonschema.LengthRunes, "héé", true},
+		{jsonschema.LengthBytes, "héé", false},
+		{jsonschema.LengthBytes, "abc", true},
+		{jsonschema.LengthBytes, "日", true},
+		{jsonschema.LengthBytes, "日本", false},
+		{jsonschema.LengthGraphemes, "👍🏽👍🏽👍🏽", true},
+		{jsonschema.LengthRunes, "👍🏽👍🏽👍🏽", false},
+		{jsonschema.LengthGraphemes, "abcd", false},
+	}
+	for _, test := range tests {
+		c := jsonschema.NewCompiler()
+		c.UseLengthUnit(test.unit)
+		if err := c.AddResource("schema.json", schema); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sch.Validate(test.str) == nil; got != test.valid {
+			t.Errorf("%d %q: valid=%v, want %v", test.unit, test.str, got, test.valid)
+		}
+	}
+
+	// propertyNames
+	c := jsonschema.NewCompiler()
+	c.UseLengthUnit(jsonschema.LengthBytes)
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Validate(map[string]any{"日本": 1}); err != nil {
+		t.Errorf("want valid: %v", err)
+	}
+	if err := sch.Validate(map[string]any{"abc": 1}); err == nil {
+		t.Error("want invalid")
+	}
+}
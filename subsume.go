@@ -0,0 +1,691 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// Subsumption is the result of [Subsumes].
+type Subsumption int
+
+const (
+	// SubsumptionUnknown means that subsumption
+	// could neither be proved nor disproved.
+	SubsumptionUnknown Subsumption = iota
+
+	// Subsumed means that subsumption is proved.
+	Subsumed
+
+	// NotSubsumed means that an instance is found,
+	// which is valid against b, but not against a.
+	NotSubsumed
+)
+
+func (s Subsumption) String() string {
+	switch s {
+	case Subsumed:
+		return "subsumed"
+	case NotSubsumed:
+		return "not subsumed"
+	}
+	return "unknown"
+}
+
+// Subsumes tells whether a subsumes b, i.e. every instance valid
+// against b is also valid against a. This can be used to check that
+// a new version of schema accepts all data accepted by old one.
+//
+// It is best-effort: subsumption is proved by comparing keywords,
+// and disproved by finding an instance valid against b, but not
+// against a. Keywords like `not`, `if`, `oneOf`, `contains`,
+// `unevaluatedProperties` and extensions in a, are not analyzed,
+// unless they are same in b.
+func Subsumes(a, b *Schema) Subsumption {
+	p := &prover{
+		assumed: map[string]bool{},
+		split:   map[*Schema]bool{},
+	}
+	if p.implies([]*Schema{b}, a) {
+		return Subsumed
+	}
+	for _, v := range candidates(b, 0) {
+		if b.Validate(v) == nil && a.Validate(v) != nil {
+			return NotSubsumed
+		}
+	}
+	return SubsumptionUnknown
+}
+
+// Equivalent tells whether a and b accept same instances, that is
+// whether they subsume each other. It is best-effort and returns
+// false, when equivalence cannot be proved.
+//
+// Note that schemas compiled from same documents are not necessarily
+// equivalent, as compiler options like [Compiler.AssertFormat] and
+// [Compiler.StrictIntegers] affect validation.
+//
+// see [Subsumes].
+func Equivalent(a, b *Schema) bool {
+	if a == b {
+		return true
+	}
+	return Subsumes(a, b) == Subsumed && Subsumes(b, a) == Subsumed
+}
+
+// --
+
+var falseSchema = func() *Schema {
+	f := false
+	return &Schema{Bool: &f}
+}()
+
+type prover struct {
+	// implications assumed to hold, while proving them.
+	// this makes proofs of recursive schemas terminate.
+	assumed map[string]bool
+
+	// schemas whose anyOf/oneOf are being split
+	split map[*Schema]bool
+}
+
+// implies tells whether it is proved, that every instance
+// valid against all schemas in conj is also valid against a.
+func (p *prover) implies(conj []*Schema, a *Schema) bool {
+	if a == nil {
+		return true
+	}
+	conj = expand(conj)
+	if a.Bool != nil {
+		if *a.Bool {
+			return true
+		}
+		return hasFalse(conj)
+	}
+	if hasFalse(conj) || slices.Contains(conj, a) {
+		return true
+	}
+	key := assumption(conj, a)
+	if p.assumed[key] {
+		return true
+	}
+	p.assumed[key] = true
+	if p.implies1(conj, a) {
+		return true
+	}
+	delete(p.assumed, key)
+	return false
+}
+
+// assumption returns key for p.assumed.
+func assumption(conj []*Schema, a *Schema) string {
+	ptrs := make([]string, 0, len(conj)+1)
+	for _, s := range conj {
+		ptrs = append(ptrs, fmt.Sprintf("%p", s))
+	}
+	slices.Sort(ptrs)
+	ptrs = append(ptrs, fmt.Sprintf("%p", a))
+	return strings.Join(ptrs, " ")
+}
+
+func (p *prover) implies1(conj []*Schema, a *Schema) bool {
+	// finite conj
+	for _, s := range conj {
+		var values []any
+		if s.Const != nil {
+			values = []any{*s.Const}
+		} else if s.Enum != nil {
+			values = s.Enum.Values
+		} else {
+			continue
+		}
+		if allValid(values, conj, a) {
+			return true
+		}
+	}
+
+	// split disjunction in conj
+	for _, s := range conj {
+		branches := append(slices.Clip(s.AnyOf), s.OneOf...)
+		if len(branches) == 0 || p.split[s] {
+			continue
+		}
+		p.split[s] = true
+		proved := true
+		for _, branch := range branches {
+			if !p.implies(append(slices.Clip(conj), branch), a) {
+				proved = false
+				break
+			}
+		}
+		delete(p.split, s)
+		if proved {
+			return true
+		}
+	}
+
+	if !supported(a) {
+		return false
+	}
+	for _, part := range a.AllOf {
+		if !p.implies(conj, part) {
+			return false
+		}
+	}
+	if a.Ref != nil && !p.implies(conj, a.Ref) {
+		return false
+	}
+	if len(a.AnyOf) > 0 && !slices.ContainsFunc(a.AnyOf, func(branch *Schema) bool {
+		return p.implies(conj, branch)
+	}) {
+		return false
+	}
+	return p.impliesTypes(conj, a) && p.impliesNumber(conj, a) &&
+		p.impliesString(conj, a) && p.impliesArray(conj, a) && p.impliesObject(conj, a)
+}
+
+// supported tells whether all keywords of a can be analyzed.
+func supported(a *Schema) bool {
+	return a.Enum == nil && a.Const == nil && a.Not == nil &&
+		a.If == nil && len(a.OneOf) == 0 && a.Contains == nil &&
+		a.PropertyNames == nil && a.Dependencies == nil &&
+		a.DependentRequired == nil && a.DependentSchemas == nil &&
+		a.UnevaluatedProperties == nil && a.UnevaluatedItems == nil &&
+		a.ContentEncoding == nil && a.ContentMediaType == nil &&
+		a.ContentSchema == nil && len(a.Extensions) == 0 &&
+		a.RecursiveRef == nil && a.DynamicRef == nil && a.dataRefs == nil
+}
+
+// expand adds `allOf` and `$ref` of schemas in conj, recursively.
+// Dynamic references are not added, as their target depends on
+// dynamic scope. Dropping a schema from conj is safe, as it makes
+// the conj accept more instances.
+func expand(conj []*Schema) []*Schema {
+	var result []*Schema
+	var add func(s *Schema)
+	add = func(s *Schema) {
+		if s == nil || slices.Contains(result, s) {
+			return
+		}
+		result = append(result, s)
+		for _, part := range s.AllOf {
+			add(part)
+		}
+		add(s.Ref)
+	}
+	for _, s := range conj {
+		add(s)
+	}
+	return result
+}
+
+func hasFalse(conj []*Schema) bool {
+	return slices.ContainsFunc(conj, func(s *Schema) bool { return isFalse(s) })
+}
+
+// allValid tells whether values accepted by all schemas
+// in conj, are also accepted by a.
+func allValid(values []any, conj []*Schema, a *Schema) bool {
+	for _, v := range values {
+		accepted := true
+		for _, s := range conj {
+			if s.Validate(v) != nil {
+				accepted = false
+				break
+			}
+		}
+		if accepted && a.Validate(v) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func anyConj(conj []*Schema, f func(s *Schema) bool) bool {
+	return slices.ContainsFunc(conj, f)
+}
+
+// excludes tells whether conj rejects all values of given types,
+// which makes keywords of those types vacuously implied.
+func excludes(conj []*Schema, types ...jsonType) bool {
+	return anyConj(conj, func(s *Schema) bool {
+		if s.Types == nil {
+			return false
+		}
+		for _, t := range types {
+			if s.Types.contains(t) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (p *prover) impliesTypes(conj []*Schema, a *Schema) bool {
+	if a.Types == nil {
+		return true
+	}
+	want := *a.Types
+	return anyConj(conj, func(s *Schema) bool {
+		types := s.Types
+		if types == nil && s.Enum != nil {
+			types = &s.Enum.types
+		}
+		if types != nil {
+			for _, t := range types.ToStrings() {
+				jt := typeFromString(t)
+				if !want.contains(jt) && !(jt == integerType && want.contains(numberType)) {
+					return false
+				}
+				if jt == integerType && !want.contains(numberType) && a.strictIntegers && s.Types != nil && !s.strictIntegers {
+					// s accepts 1.0, which is not strict integer
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	})
+}
+
+func (p *prover) impliesNumber(conj []*Schema, a *Schema) bool {
+	if excludes(conj, numberType, integerType) {
+		return true
+	}
+	if a.Minimum != nil && !anyConj(conj, func(s *Schema) bool {
+		return (s.Minimum != nil && s.Minimum.Cmp(a.Minimum) >= 0) ||
+			(s.ExclusiveMinimum != nil && s.ExclusiveMinimum.Cmp(a.Minimum) >= 0)
+	}) {
+		return false
+	}
+	if a.ExclusiveMinimum != nil && !anyConj(conj, func(s *Schema) bool {
+		return (s.ExclusiveMinimum != nil && s.ExclusiveMinimum.Cmp(a.ExclusiveMinimum) >= 0) ||
+			(s.Minimum != nil && s.Minimum.Cmp(a.ExclusiveMinimum) > 0)
+	}) {
+		return false
+	}
+	if a.Maximum != nil && !anyConj(conj, func(s *Schema) bool {
+		return (s.Maximum != nil && s.Maximum.Cmp(a.Maximum) <= 0) ||
+			(s.ExclusiveMaximum != nil && s.ExclusiveMaximum.Cmp(a.Maximum) <= 0)
+	}) {
+		return false
+	}
+	if a.ExclusiveMaximum != nil && !anyConj(conj, func(s *Schema) bool {
+		return (s.ExclusiveMaximum != nil && s.ExclusiveMaximum.Cmp(a.ExclusiveMaximum) <= 0) ||
+			(s.Maximum != nil && s.Maximum.Cmp(a.ExclusiveMaximum) < 0)
+	}) {
+		return false
+	}
+	if a.MultipleOf != nil && !anyConj(conj, func(s *Schema) bool {
+		if s.MultipleOf != nil && new(big.Rat).Quo(s.MultipleOf, a.MultipleOf).IsInt() {
+			return true
+		}
+		// integers are multiples of 1
+		return a.MultipleOf.Cmp(big.NewRat(1, 1)) == 0 && s.Types != nil && *s.Types == Types(integerType)
+	}) {
+		return false
+	}
+	return true
+}
+
+func (p *prover) impliesString(conj []*Schema, a *Schema) bool {
+	if excludes(conj, stringType) {
+		return true
+	}
+	if a.MinLength != nil && *a.MinLength > 0 && !anyConj(conj, func(s *Schema) bool {
+		return s.MinLength != nil && *s.MinLength >= *a.MinLength
+	}) {
+		return false
+	}
+	if a.MaxLength != nil && !anyConj(conj, func(s *Schema) bool {
+		return s.MaxLength != nil && *s.MaxLength <= *a.MaxLength
+	}) {
+		return false
+	}
+	if a.Pattern != nil && !anyConj(conj, func(s *Schema) bool {
+		return s.Pattern != nil && s.Pattern.String() == a.Pattern.String()
+	}) {
+		return false
+	}
+	if a.Format != nil && !anyConj(conj, func(s *Schema) bool {
+		return s.Format != nil && s.Format.Name == a.Format.Name
+	}) {
+		return false
+	}
+	return true
+}
+
+// itemsOf returns schemas of items at indexes of prefix,
+// and schema of remaining items, nil if not constrained.
+func itemsOf(s *Schema) (prefix []*Schema, rest *Schema) {
+	switch items := s.Items.(type) {
+	case *Schema:
+		return nil, items
+	case []*Schema:
+		switch additional := s.AdditionalItems.(type) {
+		case bool:
+			if !additional {
+				return items, falseSchema
+			}
+		case *Schema:
+			return items, additional
+		}
+		return items, nil
+	}
+	return s.PrefixItems, s.Items2020
+}
+
+func (p *prover) impliesArray(conj []*Schema, a *Schema) bool {
+	if excludes(conj, arrayType) {
+		return true
+	}
+	if a.MinItems != nil && *a.MinItems > 0 && !anyConj(conj, func(s *Schema) bool {
+		return s.MinItems != nil && *s.MinItems >= *a.MinItems
+	}) {
+		return false
+	}
+	maxItems := func(n int) bool {
+		return anyConj(conj, func(s *Schema) bool {
+			return s.MaxItems != nil && *s.MaxItems <= n
+		})
+	}
+	if a.MaxItems != nil && !maxItems(*a.MaxItems) {
+		return false
+	}
+	if a.UniqueItems && !maxItems(1) && !anyConj(conj, func(s *Schema) bool { return s.UniqueItems }) {
+		return false
+	}
+
+	aprefix, arest := itemsOf(a)
+	n := len(aprefix)
+	for _, s := range conj {
+		prefix, _ := itemsOf(s)
+		n = max(n, len(prefix))
+	}
+	itemAt := func(s *Schema, i int) *Schema {
+		prefix, rest := itemsOf(s)
+		if i < len(prefix) {
+			return prefix[i]
+		}
+		return rest
+	}
+	for i := 0; i < n; i++ {
+		asch := itemAt(a, i)
+		if asch == nil || maxItems(i) {
+			continue
+		}
+		var bs []*Schema
+		for _, s := range conj {
+			if sch := itemAt(s, i); sch != nil {
+				bs = append(bs, sch)
+			}
+		}
+		if !p.implies(bs, asch) {
+			return false
+		}
+	}
+	if arest != nil && !maxItems(n) {
+		var bs []*Schema
+		for _, s := range conj {
+			if sch := itemAt(s, n); sch != nil {
+				bs = append(bs, sch)
+			}
+		}
+		if !p.implies(bs, arest) {
+			return false
+		}
+	}
+	return true
+}
+
+// propConstraints returns schemas in conj, that apply to property
+// with given name. It returns false if conj does not allow it.
+// If not named, it returns schemas that apply to any name not
+// listed in `properties`. In that case, `additionalProperties`
+// with sibling `patternProperties` is dropped, as they may match.
+func propConstraints(conj []*Schema, name string, named bool) ([]*Schema, bool) {
+	var schemas []*Schema
+	for _, s := range conj {
+		if named {
+			if ps, ok := s.Properties[name]; ok {
+				schemas = append(schemas, ps)
+			}
+			for re, ps := range s.PatternProperties {
+				if re.MatchString(name) {
+					schemas = append(schemas, ps)
+				}
+			}
+			if matchesProps(s, name) {
+				continue
+			}
+		} else if len(s.PatternProperties) > 0 {
+			continue
+		}
+		if isFalse(s.AdditionalProperties) {
+			return nil, false
+		}
+		if additional, ok := s.AdditionalProperties.(*Schema); ok {
+			schemas = append(schemas, additional)
+		}
+	}
+	return schemas, true
+}
+
+func (p *prover) impliesObject(conj []*Schema, a *Schema) bool {
+	if excludes(conj, objectType) {
+		return true
+	}
+	if a.MinProperties != nil && *a.MinProperties > 0 && !anyConj(conj, func(s *Schema) bool {
+		return s.MinProperties != nil && *s.MinProperties >= *a.MinProperties
+	}) {
+		return false
+	}
+	if a.MaxProperties != nil && !anyConj(conj, func(s *Schema) bool {
+		return s.MaxProperties != nil && *s.MaxProperties <= *a.MaxProperties
+	}) {
+		return false
+	}
+	for _, pname := range a.Required {
+		if !anyConj(conj, func(s *Schema) bool { return slices.Contains(s.Required, pname) }) {
+			return false
+		}
+	}
+	for pname, psch := range a.Properties {
+		if bs, ok := propConstraints(conj, pname, true); ok && !p.implies(bs, psch) {
+			return false
+		}
+	}
+
+	// names which may appear in instances of conj, if conj is closed
+	var names []string
+	closed := anyConj(conj, func(s *Schema) bool {
+		if additional, ok := s.AdditionalProperties.(bool); ok && !additional && len(s.PatternProperties) == 0 {
+			for pname := range s.Properties {
+				names = append(names, pname)
+			}
+			return true
+		}
+		return false
+	})
+	slices.Sort(names)
+
+	for re, psch := range a.PatternProperties {
+		proved := closed || anyConj(conj, func(s *Schema) bool {
+			for bre, bsch := range s.PatternProperties {
+				if bre.String() == re.String() && p.implies([]*Schema{bsch}, psch) {
+					return true
+				}
+			}
+			return false
+		})
+		if !proved {
+			return false
+		}
+		if closed {
+			for _, pname := range names {
+				if !re.MatchString(pname) {
+					continue
+				}
+				if bs, ok := propConstraints(conj, pname, true); ok && !p.implies(bs, psch) {
+					return false
+				}
+			}
+		}
+	}
+
+	var additional *Schema
+	switch v := a.AdditionalProperties.(type) {
+	case bool:
+		if !v {
+			additional = falseSchema
+		}
+	case *Schema:
+		additional = v
+	}
+	if additional == nil {
+		return true
+	}
+	covered := func(pname string) bool {
+		if _, ok := a.Properties[pname]; ok {
+			return true
+		}
+		for re := range a.PatternProperties {
+			if re.MatchString(pname) {
+				return true
+			}
+		}
+		return false
+	}
+	if !closed {
+		bs, ok := propConstraints(conj, "", false)
+		if ok && !p.implies(bs, additional) {
+			return false
+		}
+		for _, s := range conj {
+			for pname := range s.Properties {
+				names = append(names, pname)
+			}
+		}
+	}
+	for _, pname := range names {
+		if covered(pname) {
+			continue
+		}
+		if bs, ok := propConstraints(conj, pname, true); ok && !p.implies(bs, additional) {
+			return false
+		}
+	}
+	return true
+}
+
+// --
+
+// candidates returns instances, which are likely to be valid
+// against sch, for finding counterexamples.
+func candidates(sch *Schema, depth int) []any {
+	values := []any{
+		nil, true, false,
+		json.Number("0"), json.Number("1"), json.Number("-1"), json.Number("0.5"),
+		"", "a",
+		[]any{}, map[string]any{},
+	}
+	if depth > 2 {
+		return values
+	}
+	for _, s := range expand([]*Schema{sch}) {
+		if s.Const != nil {
+			values = append(values, *s.Const)
+		}
+		if s.Enum != nil {
+			values = append(values, s.Enum.Values...)
+		}
+		values = append(values, s.Examples...)
+		if s.Default != nil {
+			values = append(values, *s.Default)
+		}
+		for _, r := range []*big.Rat{s.Minimum, s.Maximum, s.ExclusiveMinimum, s.ExclusiveMaximum} {
+			if r == nil {
+				continue
+			}
+			for _, d := range []int64{-1, 0, 1} {
+				n := new(big.Rat).Add(r, big.NewRat(d, 2))
+				values = append(values, json.Number(n.FloatString(10)))
+				n = new(big.Rat).Add(r, big.NewRat(d, 1))
+				values = append(values, json.Number(strings.TrimSuffix(n.FloatString(1), ".0")))
+			}
+		}
+		for _, n := range []*int{s.MinLength, s.MaxLength} {
+			if n != nil {
+				values = append(values, strings.Repeat("a", *n), strings.Repeat("a", *n+1))
+			}
+		}
+
+		// objects with required properties, and
+		// with each property set to its candidates
+		if len(s.Required) > 0 || len(s.Properties) > 0 {
+			obj := map[string]any{}
+			for _, pname := range s.Required {
+				obj[pname] = nil
+				if ps, ok := s.Properties[pname]; ok {
+					if v, ok := firstValid(ps, depth+1); ok {
+						obj[pname] = v
+					}
+				}
+			}
+			values = append(values, obj)
+			var pnames []string
+			for pname := range s.Properties {
+				pnames = append(pnames, pname)
+			}
+			slices.Sort(pnames)
+			for _, pname := range pnames {
+				ps := s.Properties[pname]
+				for _, v := range candidates(ps, depth+1) {
+					if ps.Validate(v) != nil {
+						continue
+					}
+					with := make(map[string]any, len(obj)+1)
+					for k, v := range obj {
+						with[k] = v
+					}
+					with[pname] = v
+					values = append(values, with)
+				}
+			}
+		}
+
+		// array with items
+		prefix, rest := itemsOf(s)
+		var arr []any
+		for _, ps := range prefix {
+			v, _ := firstValid(ps, depth+1)
+			arr = append(arr, v)
+		}
+		if rest != nil {
+			if v, ok := firstValid(rest, depth+1); ok {
+				n := 1
+				if s.MinItems != nil {
+					n = max(n, *s.MinItems)
+				}
+				for i := 0; i < n; i++ {
+					arr = append(arr, v)
+				}
+			}
+		}
+		if arr != nil {
+			values = append(values, arr)
+		}
+	}
+	return values
+}
+
+func firstValid(sch *Schema, depth int) (any, bool) {
+	for _, v := range candidates(sch, depth) {
+		if sch.Validate(v) == nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
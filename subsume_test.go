@@ -0,0 +1,175 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestSubsumes(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want jsonschema.Subsumption
+	}{
+		{"true", `true`, `{"type": "string"}`, jsonschema.Subsumed},
+		{"false", `{"type": "string"}`, `false`, jsonschema.Subsumed},
+		{"integerInNumber", `{"type": "number"}`, `{"type": "integer"}`, jsonschema.Subsumed},
+		{"numberInInteger", `{"type": "integer"}`, `{"type": "number"}`, jsonschema.NotSubsumed},
+		{"types", `{"type": ["string", "null"]}`, `{"type": "string"}`, jsonschema.Subsumed},
+		{"enum", `{"type": "string"}`, `{"enum": ["a", "b"]}`, jsonschema.Subsumed},
+		{"enumSubset", `{"enum": ["a", "b", "c"]}`, `{"enum": ["a", "b"]}`, jsonschema.Subsumed},
+		{"enumSuperset", `{"enum": ["a", "b"]}`, `{"enum": ["a", "b", "c"]}`, jsonschema.NotSubsumed},
+		{"minimum", `{"minimum": 1}`, `{"exclusiveMinimum": 1}`, jsonschema.Subsumed},
+		{"maximum", `{"maximum": 5}`, `{"maximum": 10}`, jsonschema.NotSubsumed},
+		{"multipleOf", `{"multipleOf": 2}`, `{"multipleOf": 6}`, jsonschema.Subsumed},
+		{"maxLength", `{"maxLength": 10}`, `{"type": "string", "maxLength": 5}`, jsonschema.Subsumed},
+		{"minLengthVacuous", `{"minLength": 3}`, `{"type": "integer"}`, jsonschema.Subsumed},
+		{"minLength", `{"minLength": 3}`, `{"type": "string"}`, jsonschema.NotSubsumed},
+		{"required", `{"required": ["a"]}`, `{"required": ["a", "b"]}`, jsonschema.Subsumed},
+		{"newRequired", `{"required": ["a", "b"]}`, `{"required": ["a"]}`, jsonschema.NotSubsumed},
+		{
+			"properties",
+			`{"properties": {"a": {"type": "number"}}}`,
+			`{"properties": {"a": {"type": "integer"}, "b": true}}`,
+			jsonschema.Subsumed,
+		},
+		{
+			"propertyNarrowed",
+			`{"properties": {"a": {"type": "integer"}}, "required": ["a"]}`,
+			`{"properties": {"a": {"type": "number"}}, "required": ["a"]}`,
+			jsonschema.NotSubsumed,
+		},
+		{
+			"additionalProperties",
+			`{"properties": {"a": true, "b": true}, "additionalProperties": false}`,
+			`{"properties": {"a": true}, "additionalProperties": false}`,
+			jsonschema.Subsumed,
+		},
+		{
+			"closed",
+			`{"properties": {"a": true}, "additionalProperties": false}`,
+			`{"properties": {"a": true}}`,
+			jsonschema.SubsumptionUnknown,
+		},
+		{
+			"items",
+			`{"type": "array", "items": {"type": "number"}}`,
+			`{"type": "array", "items": {"type": "integer"}, "maxItems": 3}`,
+			jsonschema.Subsumed,
+		},
+		{
+			"prefixItems",
+			`{"prefixItems": [{"type": "string"}], "items": false}`,
+			`{"prefixItems": [{"type": "string"}, {"type": "string"}], "items": false}`,
+			jsonschema.NotSubsumed,
+		},
+		{
+			"anyOf",
+			`{"anyOf": [{"type": "string"}, {"type": "number"}]}`,
+			`{"type": "integer"}`,
+			jsonschema.Subsumed,
+		},
+		{
+			"splitAnyOf",
+			`{"type": ["string", "number"]}`,
+			`{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			jsonschema.Subsumed,
+		},
+		{
+			"allOf",
+			`{"allOf": [{"type": "string"}, {"maxLength": 5}]}`,
+			`{"$ref": "#/$defs/s", "$defs": {"s": {"type": "string", "maxLength": 3}}}`,
+			jsonschema.Subsumed,
+		},
+		{
+			"recursive",
+			`{"$defs": {"n": {"type": "object", "properties": {"next": {"$ref": "#/$defs/n"}}}}, "$ref": "#/$defs/n"}`,
+			`{"$defs": {"m": {"type": "object", "properties": {"next": {"$ref": "#/$defs/m"}}, "required": ["v"]}}, "$ref": "#/$defs/m"}`,
+			jsonschema.Subsumed,
+		},
+		{"not", `{"not": {"type": "string"}}`, `{"type": "number"}`, jsonschema.SubsumptionUnknown},
+		{"notCounterexample", `{"not": {"type": "string"}}`, `true`, jsonschema.NotSubsumed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := compileString(t, test.a), compileString(t, test.b)
+			if got := jsonschema.Subsumes(a, b); got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"keywordOrder", `{"type": "string", "maxLength": 5}`, `{"maxLength": 5, "type": "string"}`, true},
+		{"numbers", `{"maximum": 1.0}`, `{"maximum": 1}`, true},
+		{"defaults", `{"type": "string", "minLength": 0}`, `{"type": "string"}`, true},
+		{"annotations", `{"type": "integer", "title": "count"}`, `{"type": "integer", "description": "count"}`, true},
+		{"typeOrder", `{"type": ["string", "null"]}`, `{"type": ["null", "string"]}`, true},
+		{"different", `{"type": "string"}`, `{"type": ["string", "null"]}`, false},
+		{"unknown", `{"not": {"type": "string"}}`, `{"not": {"type": "string"}, "title": "x"}`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := compileString(t, test.a), compileString(t, test.b)
+			if got := jsonschema.Equivalent(a, b); got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEquivalentCompilerOptions(t *testing.T) {
+	compile := func(schema string, opt func(c *jsonschema.Compiler)) *jsonschema.Schema {
+		t.Helper()
+		doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := jsonschema.NewCompiler()
+		if opt != nil {
+			opt(c)
+		}
+		sch, err := c.CompileValue(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+	tests := []struct {
+		name   string
+		schema string
+		opt    func(c *jsonschema.Compiler)
+	}{
+		{"assertFormat", `{"type": "string", "format": "email"}`, (*jsonschema.Compiler).AssertFormat},
+		{"strictIntegers", `{"type": "integer"}`, (*jsonschema.Compiler).StrictIntegers},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := compile(test.schema, test.opt), compile(test.schema, nil)
+			if jsonschema.Equivalent(a, b) {
+				t.Fatal("got true, want false")
+			}
+		})
+	}
+}
+
+func compileString(t *testing.T, schema string) *jsonschema.Schema {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := jsonschema.NewCompiler().CompileValue(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
@@ -0,0 +1,109 @@
+package suitetest
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+const modulePath = "github.com/santhosh-tekuri/jsonschema/v6"
+
+// Report summarizes conformance of a configured compiler to
+// the suite, in the shape of implementation reports listed by
+// the JSON Schema organization. It can be encoded as json.
+type Report struct {
+	Implementation *Implementation   `json:"implementation"`
+	Results        []*DialectResults `json:"results"`
+}
+
+// Implementation describes the implementation under test.
+type Implementation struct {
+	Language        string   `json:"language"`
+	LanguageVersion string   `json:"language_version"`
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	Homepage        string   `json:"homepage"`
+	Dialects        []string `json:"dialects"`
+}
+
+// DefaultImplementation describes this library. Version is
+// taken from build information of the running binary, and
+// is empty if not available.
+func DefaultImplementation() *Implementation {
+	impl := &Implementation{
+		Language:        "go",
+		LanguageVersion: runtime.Version(),
+		Name:            "jsonschema",
+		Homepage:        "https://github.com/santhosh-tekuri/jsonschema",
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range append([]*debug.Module{&bi.Main}, bi.Deps...) {
+			if dep.Path == modulePath {
+				impl.Version = dep.Version
+			}
+		}
+	}
+	for _, d := range Dirs {
+		impl.Dialects = append(impl.Dialects, d.Draft.String())
+	}
+	return impl
+}
+
+// DialectResults summarizes results of tests of a dialect.
+type DialectResults struct {
+	Dialect string `json:"dialect"`
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+
+	// Errored is number of tests, whose schema failed to compile.
+	Errored int `json:"errored"`
+
+	Failures []*Failure `json:"failures,omitempty"`
+}
+
+// Failure identifies a test which failed or errored.
+type Failure struct {
+	File  string `json:"file"`
+	Group string `json:"case"`
+	Test  string `json:"test"`
+	Error string `json:"error,omitempty"`
+}
+
+// Report runs tests of all directories in [Dirs], and returns
+// the report for impl. If impl is nil, [DefaultImplementation]
+// is used. Directories missing in suite are not reported.
+func (s *Suite) Report(impl *Implementation) (*Report, error) {
+	if impl == nil {
+		impl = DefaultImplementation()
+	}
+	report := &Report{Implementation: impl}
+	for _, d := range Dirs {
+		results, err := s.Run(d.Dir, d.Draft)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			continue
+		}
+		dr := &DialectResults{Dialect: d.Draft.String()}
+		for _, r := range results {
+			dr.Total++
+			switch {
+			case r.Passed():
+				dr.Passed++
+				continue
+			case r.CompileFailed:
+				dr.Errored++
+			default:
+				dr.Failed++
+			}
+			f := &Failure{File: r.File, Group: r.Group, Test: r.Test}
+			if r.CompileFailed {
+				f.Error = r.Err.Error()
+			}
+			dr.Failures = append(dr.Failures, f)
+		}
+		report.Results = append(report.Results, dr)
+	}
+	return report, nil
+}
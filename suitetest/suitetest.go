@@ -0,0 +1,266 @@
+// Package suitetest runs tests in the format of the official
+// [JSON-Schema-Test-Suite] against a configured compiler.
+//
+// This can be used to run the official suite with custom
+// vocabularies and formats, or to certify custom keywords
+// using a suite of your own in the same format:
+//
+//	func TestSuite(t *testing.T) {
+//		s := &suitetest.Suite{
+//			FS: os.DirFS("testdata/my-suite"),
+//			Configure: func(c *jsonschema.Compiler, file string) {
+//				c.RegisterVocabulary(myVocab())
+//				c.AssertVocabs()
+//			},
+//		}
+//		s.Test(t, "draft2020-12", jsonschema.Draft2020)
+//	}
+//
+// [JSON-Schema-Test-Suite]: https://github.com/json-schema-org/JSON-Schema-Test-Suite
+package suitetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// RemotesURL is the url prefix, at which tests refer to
+// files in "remotes" directory of suite.
+const RemotesURL = "http://localhost:1234/"
+
+// Dirs lists directories of the official suite,
+// along with the drafts they test.
+var Dirs = []struct {
+	Dir   string
+	Draft *jsonschema.Draft
+}{
+	{"draft4", jsonschema.Draft4},
+	{"draft6", jsonschema.Draft6},
+	{"draft7", jsonschema.Draft7},
+	{"draft2019-09", jsonschema.Draft2019},
+	{"draft2020-12", jsonschema.Draft2020},
+}
+
+// Suite is a test suite, in the layout of official suite.
+type Suite struct {
+	// FS contains directories "tests" and "remotes".
+	FS fs.FS
+
+	// Configure is called to configure compiler, created for
+	// each group of tests in file, which is path relative to
+	// "tests" directory. It is optional.
+	//
+	// The compiler is already configured with the draft, with
+	// loader for [RemotesURL], and with format and content
+	// assertions for tests in "optional" directories.
+	Configure func(c *jsonschema.Compiler, file string)
+
+	// Skip tells whether to skip given test file, which is
+	// path relative to "tests" directory. It is optional.
+	Skip func(file string) bool
+}
+
+// Result is the outcome of a single test.
+type Result struct {
+	// File is path of test file, relative to "tests" directory.
+	File string
+
+	// Group is description of test group in file.
+	Group string
+
+	// Test is description of test in group.
+	Test string
+
+	// Schema is the schema of the group.
+	Schema any
+
+	// Data is the instance of the test.
+	Data any
+
+	// Valid is the expected validity of Data.
+	Valid bool
+
+	// Err is error returned by validation, or by compilation
+	// of Schema. It is nil if Data is valid.
+	Err error
+
+	// CompileFailed tells whether Err is from compilation.
+	CompileFailed bool
+}
+
+// Passed tells whether the test passed.
+func (r *Result) Passed() bool {
+	return !r.CompileFailed && (r.Err == nil) == r.Valid
+}
+
+type group struct {
+	Description string
+	Schema      any
+	Tests       []struct {
+		Description string
+		Data        any
+		Valid       bool
+	}
+}
+
+// Run runs tests in given directory of "tests", recursively,
+// using draft for schemas without `$schema`. It returns error,
+// if test files cannot be read or decoded. Missing directory
+// is treated as empty.
+func (s *Suite) Run(dir string, draft *jsonschema.Draft) ([]*Result, error) {
+	var results []*Result
+	root := path.Join("tests", dir)
+	if _, err := fs.Stat(s.FS, root); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	err := fs.WalkDir(s.FS, root, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(fpath) != ".json" {
+			return nil
+		}
+		file := strings.TrimPrefix(fpath, "tests/")
+		if s.Skip != nil && s.Skip(file) {
+			return nil
+		}
+		rr, err := s.RunFile(file, draft)
+		if err != nil {
+			return err
+		}
+		results = append(results, rr...)
+		return nil
+	})
+	return results, err
+}
+
+// RunFile runs tests in given file, which is path
+// relative to "tests" directory.
+func (s *Suite) RunFile(file string, draft *jsonschema.Draft) ([]*Result, error) {
+	b, err := fs.ReadFile(s.FS, path.Join("tests", file))
+	if err != nil {
+		return nil, err
+	}
+	var groups []group
+	if err := unmarshal(b, &groups); err != nil {
+		return nil, fmt.Errorf("suitetest: decoding %s: %w", file, err)
+	}
+
+	var results []*Result
+	url := "http://testsuites.com/schema.json"
+	for _, g := range groups {
+		c := jsonschema.NewCompiler()
+		c.DefaultDraft(draft)
+		if strings.Contains(file, "/optional/") {
+			c.AssertFormat()
+			c.AssertContent()
+		}
+		c.UseLoader(jsonschema.SchemeURLLoader{
+			"http": remotes{s.FS},
+		})
+		if s.Configure != nil {
+			s.Configure(c, file)
+		}
+		var sch *jsonschema.Schema
+		err := c.AddResource(url, g.Schema)
+		if err == nil {
+			sch, err = c.Compile(url)
+		}
+		for _, test := range g.Tests {
+			r := &Result{
+				File:   file,
+				Group:  g.Description,
+				Test:   test.Description,
+				Schema: g.Schema,
+				Data:   test.Data,
+				Valid:  test.Valid,
+			}
+			if err != nil {
+				r.Err, r.CompileFailed = err, true
+			} else {
+				r.Err = sch.Validate(test.Data)
+			}
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// Test runs tests in given directory of "tests" as subtests
+// of t, one per file, and reports failed tests.
+func (s *Suite) Test(t *testing.T, dir string, draft *jsonschema.Draft) {
+	t.Helper()
+	results, err := s.Run(dir, draft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	byFile := map[string][]*Result{}
+	for _, r := range results {
+		if _, ok := byFile[r.File]; !ok {
+			files = append(files, r.File)
+		}
+		byFile[r.File] = append(byFile[r.File], r)
+	}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			for _, r := range byFile[file] {
+				if !r.Passed() {
+					t.Errorf("%s", r)
+				}
+			}
+		})
+	}
+}
+
+func (r *Result) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s: %s", r.File, r.Group, r.Test)
+	if r.CompileFailed {
+		fmt.Fprintf(&sb, ": schema compilation failed: %v", r.Err)
+	} else {
+		fmt.Fprintf(&sb, ": valid: got %v, want %v", r.Err == nil, r.Valid)
+	}
+	schema, _ := json.Marshal(r.Schema)
+	data, _ := json.Marshal(r.Data)
+	fmt.Fprintf(&sb, "\nschema: %s\ndata: %s", schema, data)
+	if r.Err != nil && !r.CompileFailed {
+		fmt.Fprintf(&sb, "\nerror: %v", r.Err)
+	}
+	return sb.String()
+}
+
+// unmarshal decodes b into v, using json.Number for numbers.
+func unmarshal(b []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// --
+
+// remotes loads [RemotesURL] from "remotes" directory.
+type remotes struct {
+	fsys fs.FS
+}
+
+func (rl remotes) Load(url string) (any, error) {
+	rem, ok := strings.CutPrefix(url, RemotesURL)
+	if !ok {
+		return nil, errors.New("no internet")
+	}
+	f, err := rl.fsys.Open(path.Join("remotes", rem))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return jsonschema.UnmarshalJSON(f)
+}
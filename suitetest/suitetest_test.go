@@ -0,0 +1,121 @@
+package suitetest_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/suitetest"
+)
+
+var suiteFS = fstest.MapFS{
+	"tests/draft2020-12/ref.json": {Data: []byte(`[
+		{
+			"description": "remote ref",
+			"schema": {"$ref": "http://localhost:1234/int.json"},
+			"tests": [
+				{"description": "integer", "data": 1, "valid": true},
+				{"description": "string", "data": "x", "valid": false}
+			]
+		}
+	]`)},
+	"tests/draft2020-12/optional/format.json": {Data: []byte(`[
+		{
+			"description": "format asserted",
+			"schema": {"format": "not3"},
+			"tests": [
+				{"description": "two", "data": 2, "valid": true},
+				{"description": "three", "data": 3, "valid": false}
+			]
+		}
+	]`)},
+	"tests/draft2020-12/skipped.json": {Data: []byte(`invalid json`)},
+	"remotes/int.json":                {Data: []byte(`{"type": "integer"}`)},
+}
+
+func TestSuite(t *testing.T) {
+	s := &suitetest.Suite{
+		FS: suiteFS,
+		Configure: func(c *jsonschema.Compiler, file string) {
+			c.RegisterFormat(&jsonschema.Format{
+				Name: "not3",
+				Validate: func(v any) error {
+					// suite data uses json.Number
+					if n, ok := v.(json.Number); ok && strings.HasSuffix(string(n), "3") {
+						return jsonschema.LocalizableError("ends with 3")
+					}
+					return nil
+				},
+			})
+		},
+		Skip: func(file string) bool {
+			return file == "draft2020-12/skipped.json"
+		},
+	}
+	s.Test(t, "draft2020-12", jsonschema.Draft2020)
+
+	results, err := s.Run("draft2020-12", jsonschema.Draft2020)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+
+	// missing directory
+	results, err = s.Run("draft4", jsonschema.Draft4)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("got %v, %v", results, err)
+	}
+
+	// not skipped
+	s.Skip = nil
+	if _, err := s.Run("draft2020-12", jsonschema.Draft2020); err == nil {
+		t.Fatal("want decoding error")
+	}
+}
+
+func TestReport(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tests/draft7/type.json": {Data: []byte(`[
+			{
+				"description": "integer",
+				"schema": {"type": "integer"},
+				"tests": [
+					{"description": "integer", "data": 1, "valid": true},
+					{"description": "wrong expectation", "data": "x", "valid": true}
+				]
+			},
+			{
+				"description": "invalid schema",
+				"schema": {"type": 1},
+				"tests": [
+					{"description": "any", "data": 1, "valid": true}
+				]
+			}
+		]`)},
+	}
+	s := &suitetest.Suite{FS: fsys}
+	report, err := s.Report(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Implementation.Name != "jsonschema" || len(report.Implementation.Dialects) != len(suitetest.Dirs) {
+		t.Fatalf("got %+v", report.Implementation)
+	}
+	if len(report.Results) != 1 {
+		t.Fatalf("got %d dialects, want 1", len(report.Results))
+	}
+	dr := report.Results[0]
+	if dr.Dialect != jsonschema.Draft7.String() || dr.Total != 3 || dr.Passed != 1 || dr.Failed != 1 || dr.Errored != 1 {
+		t.Fatalf("got %+v", dr)
+	}
+	if len(dr.Failures) != 2 || dr.Failures[0].Test != "wrong expectation" || dr.Failures[1].Error == "" {
+		t.Fatalf("got failures %+v", dr.Failures)
+	}
+	if _, err := json.Marshal(report); err != nil {
+		t.Fatal(err)
+	}
+}
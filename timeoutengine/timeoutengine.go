@@ -0,0 +1,143 @@
+// Package timeoutengine provides [jsonschema.RegexpEngine] wrapper
+// which enforces timeout on matching.
+//
+// This is useful with engines which do not support timeouts natively.
+// Note that go does not allow to stop a running match, so the match
+// continues in background after timeout, but validation does not
+// wait for it. To bound the cpu burnt by such matches, once
+// [Options.MaxAbandoned] of them are running, further matches are
+// treated as timed out, without being started.
+//
+// By default, a timed out match fails the validation with
+// [jsonschema.RegexpMatchError] wrapping [ErrTimeout]. Reporting it
+// as not matched instead, fails open: a value matching a deny-list
+// pattern under `not` is accepted, and a property matching
+// `patternProperties` skips its schema. So use [Options.FailOpen]
+// only if such bypass is acceptable.
+package timeoutengine
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrTimeout is the error of timed out match.
+var ErrTimeout = errors.New("timeoutengine: match timed out")
+
+// Options are used to configure [New].
+type Options struct {
+	// Timeout is the maximum duration for single match.
+	// If zero, there is no timeout.
+	Timeout time.Duration
+
+	// FailOpen reports string as not matched on timeout,
+	// rather than failing the validation. see package doc.
+	FailOpen bool
+
+	// MaxAbandoned is maximum number of timed out matches, which
+	// may continue running in background. If zero, 16 is used.
+	MaxAbandoned int
+}
+
+// Wrap returns RegexpEngine which compiles using engine, and whose
+// regexps fail the validation, if matching does not complete
+// within timeout.
+//
+// If engine is nil, [jsonschema.GoRegexpEngine] is used.
+func Wrap(engine jsonschema.RegexpEngine, timeout time.Duration) jsonschema.RegexpEngine {
+	return New(engine, &Options{Timeout: timeout})
+}
+
+// New is same as [Wrap], but uses given options.
+// If opts is nil, there is no timeout.
+func New(engine jsonschema.RegexpEngine, opts *Options) jsonschema.RegexpEngine {
+	if engine == nil {
+		engine = jsonschema.GoRegexpEngine
+	}
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.MaxAbandoned <= 0 {
+		o.MaxAbandoned = 16
+	}
+	abandoned := new(atomic.Int32) // shared by all regexps
+	return func(s string) (jsonschema.Regexp, error) {
+		re, err := engine(s)
+		if err != nil {
+			return nil, err
+		}
+		if o.Timeout <= 0 {
+			return re, nil
+		}
+		if o.FailOpen {
+			return &failOpenRegexp{regexp{re, o, abandoned}}, nil
+		}
+		return &regexp{re, o, abandoned}, nil
+	}
+}
+
+type regexp struct {
+	jsonschema.Regexp
+	opts      Options
+	abandoned *atomic.Int32
+}
+
+// MatchString reports string as not matched on timeout.
+// It is used where the timeout cannot be reported.
+func (re *regexp) MatchString(s string) bool {
+	matched, _ := re.TryMatchString(s)
+	return matched
+}
+
+// TryMatchString implements [jsonschema.FallibleRegexp].
+func (re *regexp) TryMatchString(s string) (bool, error) {
+	if re.abandoned.Load() >= int32(re.opts.MaxAbandoned) {
+		return false, ErrTimeout
+	}
+	result := make(chan bool, 1) // buffered, so that goroutine does not leak after timeout
+	var state atomic.Int32       // running, done or abandoned
+	go func() {
+		result <- re.Regexp.MatchString(s)
+		if !state.CompareAndSwap(matchRunning, matchDone) {
+			re.abandoned.Add(-1)
+		}
+	}()
+	timer := time.NewTimer(re.opts.Timeout)
+	defer timer.Stop()
+	select {
+	case matched := <-result:
+		return matched, nil
+	case <-timer.C:
+		if !state.CompareAndSwap(matchRunning, matchAbandoned) {
+			// completed just now
+			return <-result, nil
+		}
+		re.abandoned.Add(1)
+		return false, ErrTimeout
+	}
+}
+
+// states of a match.
+const (
+	matchRunning int32 = iota
+	matchDone
+	matchAbandoned
+)
+
+// failOpenRegexp does not implement jsonschema.FallibleRegexp,
+// so that timeouts are reported as not matched.
+type failOpenRegexp struct {
+	re regexp
+}
+
+func (re *failOpenRegexp) MatchString(s string) bool {
+	return re.re.MatchString(s)
+}
+
+func (re *failOpenRegexp) String() string {
+	return re.re.String()
+}
@@ -0,0 +1,130 @@
+package timeoutengine_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/timeoutengine"
+)
+
+type slowRegexp struct {
+	jsonschema.Regexp
+	delay time.Duration
+}
+
+func (re slowRegexp) MatchString(s string) bool {
+	time.Sleep(re.delay)
+	return re.Regexp.MatchString(s)
+}
+
+func TestWrap(t *testing.T) {
+	slow := func(s string) (jsonschema.Regexp, error) {
+		re, err := jsonschema.GoRegexpEngine(s)
+		if err != nil {
+			return nil, err
+		}
+		return slowRegexp{re, 100 * time.Millisecond}, nil
+	}
+
+	re, err := timeoutengine.Wrap(nil, time.Second)("^a+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("aaa") || re.MatchString("b") {
+		t.Fatal("wrong match result")
+	}
+	if re.String() != "^a+$" {
+		t.Fatalf("String: got %q", re.String())
+	}
+
+	re, err = timeoutengine.Wrap(slow, time.Millisecond)("^a+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re.MatchString("aaa") {
+		t.Fatal("want no match on timeout")
+	}
+
+	if _, err := timeoutengine.Wrap(nil, time.Second)("^(a+$"); err == nil {
+		t.Fatal("want compile error")
+	}
+}
+
+// blockingRegexp blocks matching until release is closed.
+type blockingRegexp struct {
+	jsonschema.Regexp
+	release chan struct{}
+	calls   *atomic.Int32
+}
+
+func (re blockingRegexp) MatchString(s string) bool {
+	re.calls.Add(1)
+	<-re.release
+	return re.Regexp.MatchString(s)
+}
+
+func TestTimeoutUnderNot(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	blocking := func(s string) (jsonschema.Regexp, error) {
+		re, err := jsonschema.GoRegexpEngine(s)
+		if err != nil {
+			return nil, err
+		}
+		return blockingRegexp{re, release, new(atomic.Int32)}, nil
+	}
+	compile := func(opts *timeoutengine.Options) *jsonschema.Schema {
+		t.Helper()
+		c := jsonschema.NewCompiler()
+		c.UseRegexpEngine(timeoutengine.New(blocking, opts))
+		// deny-list pattern
+		sch, err := c.CompileValue(map[string]any{"not": map[string]any{"pattern": "^admin"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+
+	// fails closed by default
+	sch := compile(&timeoutengine.Options{Timeout: time.Millisecond})
+	err := sch.Validate("admin")
+	var merr *jsonschema.RegexpMatchError
+	if !errors.As(err, &merr) || !errors.Is(err, timeoutengine.ErrTimeout) {
+		t.Fatalf("want RegexpMatchError with ErrTimeout, got %v", err)
+	}
+
+	// fail open accepts the value, bypassing the deny-list
+	sch = compile(&timeoutengine.Options{Timeout: time.Millisecond, FailOpen: true})
+	if err := sch.Validate("admin"); err != nil {
+		t.Fatalf("want fail open, got %v", err)
+	}
+}
+
+func TestMaxAbandoned(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	engine := timeoutengine.New(func(s string) (jsonschema.Regexp, error) {
+		re, err := jsonschema.GoRegexpEngine(s)
+		if err != nil {
+			return nil, err
+		}
+		return blockingRegexp{re, release, &calls}, nil
+	}, &timeoutengine.Options{Timeout: time.Millisecond, MaxAbandoned: 1})
+	re, err := engine("^a+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fre := re.(jsonschema.FallibleRegexp)
+	for i := 0; i < 3; i++ {
+		if _, err := fre.TryMatchString("aaa"); err != timeoutengine.ErrTimeout {
+			t.Fatalf("want ErrTimeout, got %v", err)
+		}
+	}
+	close(release)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("matches started: got %d, want 1", n)
+	}
+}
@@ -0,0 +1,51 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		value any
+		want  jsonschema.Type
+	}{
+		{nil, jsonschema.TypeNull},
+		{true, jsonschema.TypeBoolean},
+		{1, jsonschema.TypeInteger},
+		{uint8(1), jsonschema.TypeInteger},
+		{1.0, jsonschema.TypeInteger},
+		{1.5, jsonschema.TypeNumber},
+		{json.Number("10"), jsonschema.TypeInteger},
+		{json.Number("1e2"), jsonschema.TypeInteger},
+		{json.Number("1.0"), jsonschema.TypeInteger},
+		{json.Number("1.5"), jsonschema.TypeNumber},
+		{json.Number("1e-2"), jsonschema.TypeNumber},
+		{"1", jsonschema.TypeString},
+		{[]any{}, jsonschema.TypeArray},
+		{map[string]any{}, jsonschema.TypeObject},
+	}
+	for _, test := range tests {
+		got, err := jsonschema.TypeOf(test.value)
+		if err != nil {
+			t.Errorf("TypeOf(%#v): %v", test.value, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TypeOf(%#v): got %v, want %v", test.value, got, test.want)
+		}
+		if got.IsNumber() != (test.want == jsonschema.TypeNumber || test.want == jsonschema.TypeInteger) {
+			t.Errorf("TypeOf(%#v).IsNumber(): got %v", test.value, got.IsNumber())
+		}
+	}
+
+	for _, v := range []any{struct{}{}, []string{}, map[string]string{}} {
+		_, err := jsonschema.TypeOf(v)
+		if !errors.As(err, new(*jsonschema.InvalidJSONValueError)) {
+			t.Errorf("TypeOf(%#v): want InvalidJSONValueError, got %v", v, err)
+		}
+	}
+}
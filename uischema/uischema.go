@@ -0,0 +1,378 @@
+// Package uischema exports compiled json schemas as UI models, so
+// that schemas defined in backend can drive forms in frontends, such
+// as [JSON Forms] and [react-jsonschema-form], without parsing
+// schemas again in javascript.
+//
+// The UI model of a schema is a tree of [Field], which carries field
+// order, labels, widget hints, enum labels and required flags.
+// Use [Field.JSONForms] and [Field.RJSF] to get ui schemas in the
+// form understood by these libraries.
+//
+// The schemas applied via `$ref` and `allOf` are merged into single
+// field. Other applicators are not considered, except `oneOf` of
+// `const` subschemas, which is treated as `enum` with labels from
+// their `title`.
+//
+// Register [Vocab] with the compiler, to get hints from non-standard
+// keywords, and from `format`, even if format assertions are not
+// enabled. Without it, [jsonschema.Schema.Format] is available only
+// if format assertions are enabled, see [jsonschema.Compiler.AssertFormat].
+//
+// [JSON Forms]: https://jsonforms.io
+// [react-jsonschema-form]: https://rjsf-team.github.io/react-jsonschema-form
+package uischema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
+)
+
+// Field is the UI model of a value, described by a schema.
+type Field struct {
+	// Name is the property name. It is empty for root
+	// and array items.
+	Name string
+
+	// Scope is the json-pointer of the schema of this field, as
+	// url fragment, such as "#/properties/address/properties/city".
+	// For fields within array items, it is relative to the items
+	// schema. This is the scope used by JSON Forms.
+	Scope string
+
+	// Type is the json type of value, ignoring "null". It is empty,
+	// if schema allows multiple types, or does not specify type.
+	Type string
+
+	// Label is the `title` of schema, or the name in title case
+	// if title is missing.
+	Label string
+
+	// Description is the `description` of schema.
+	Description string
+
+	// Required tells whether the property is required in its parent.
+	Required bool
+
+	// ReadOnly is the `readOnly` of schema.
+	ReadOnly bool
+
+	// Widget is the hint for input widget, using names of widgets in
+	// react-jsonschema-form, such as "email", "uri", "date", "date-time",
+	// "time", "password", "select", "checkbox" and "textarea". It is
+	// empty, if default widget for Type is to be used.
+	Widget string
+
+	// Default is the `default` of schema, nil if absent.
+	Default *any
+
+	// Options are the allowed values with their labels, if the
+	// schema has `enum`, or `oneOf` with `const` subschemas.
+	Options []Option
+
+	// Fields are the properties of object, in display order.
+	Fields []*Field
+
+	// Items is the field of array items, nil if not array.
+	Items *Field
+}
+
+// Option is an allowed value of [Field], with its label.
+type Option struct {
+	Value any
+	Label string
+}
+
+// Export returns UI model of sch.
+func Export(sch *jsonschema.Schema) *Field {
+	e := &exporter{inPath: map[*jsonschema.Schema]bool{}}
+	return e.field(sch, "", "#", false)
+}
+
+type exporter struct {
+	inPath map[*jsonschema.Schema]bool // to stop at recursive schemas
+}
+
+func (e *exporter) field(sch *jsonschema.Schema, name, scope string, required bool) *Field {
+	schemas := merged(sch, nil)
+	f := &Field{Name: name, Scope: scope, Required: required}
+	var h hints
+	for _, s := range schemas {
+		if ext := hintsOf(s); ext != nil {
+			h.merge(ext)
+		}
+		if f.Type == "" && s.Types != nil {
+			types := slices.DeleteFunc(s.Types.ToStrings(), func(t string) bool { return t == "null" })
+			if len(types) == 1 {
+				f.Type = types[0]
+			}
+		}
+		if f.Label == "" {
+			f.Label = s.Title
+		}
+		if f.Description == "" {
+			f.Description = s.Description
+		}
+		f.ReadOnly = f.ReadOnly || s.ReadOnly
+		if f.Default == nil {
+			f.Default = s.Default
+		}
+		if f.Options == nil {
+			f.Options = options(s, h.enumNames)
+		}
+	}
+	if f.Label == "" {
+		f.Label = titleCase(name)
+	}
+	f.Widget = widget(schemas, f, &h)
+
+	// stop at recursive schema
+	if slices.ContainsFunc(schemas, func(s *jsonschema.Schema) bool { return e.inPath[s] }) {
+		return f
+	}
+	for _, s := range schemas {
+		e.inPath[s] = true
+		defer delete(e.inPath, s)
+	}
+
+	// properties --
+	props := map[string]*jsonschema.Schema{}
+	var requiredProps []string
+	for _, s := range schemas {
+		for pname, psch := range s.Properties {
+			if _, ok := props[pname]; !ok {
+				props[pname] = psch
+			}
+		}
+		requiredProps = append(requiredProps, s.Required...)
+	}
+	if len(props) > 0 && f.Type == "" {
+		f.Type = "object"
+	}
+	for _, pname := range order(props, h.order) {
+		pscope := scope + jsonpointer.Append("", "properties", pname)
+		f.Fields = append(f.Fields, e.field(props[pname], pname, pscope, slices.Contains(requiredProps, pname)))
+	}
+
+	// items --
+	for _, s := range schemas {
+		items := s.Items2020
+		if items == nil {
+			items, _ = s.Items.(*jsonschema.Schema)
+		}
+		if items != nil {
+			if f.Type == "" {
+				f.Type = "array"
+			}
+			f.Items = e.field(items, "", "#", false)
+			break
+		}
+	}
+	return f
+}
+
+// merged returns sch, and the schemas applied to same
+// value via `$ref` and `allOf`.
+func merged(sch *jsonschema.Schema, schemas []*jsonschema.Schema) []*jsonschema.Schema {
+	if sch == nil || slices.Contains(schemas, sch) {
+		return schemas
+	}
+	schemas = append(schemas, sch)
+	schemas = merged(sch.Ref, schemas)
+	for _, s := range sch.AllOf {
+		schemas = merged(s, schemas)
+	}
+	return schemas
+}
+
+// order returns property names, in the order given by `x-order`,
+// followed by remaining names in alphabetical order.
+func order(props map[string]*jsonschema.Schema, xorder []string) []string {
+	var names []string
+	for _, pname := range xorder {
+		if _, ok := props[pname]; ok && !slices.Contains(names, pname) {
+			names = append(names, pname)
+		}
+	}
+	var rest []string
+	for pname := range props {
+		if !slices.Contains(names, pname) {
+			rest = append(rest, pname)
+		}
+	}
+	slices.Sort(rest)
+	return append(names, rest...)
+}
+
+func options(sch *jsonschema.Schema, enumNames []string) []Option {
+	var opts []Option
+	switch {
+	case sch.Enum != nil:
+		for i, v := range sch.Enum.Values {
+			opt := Option{Value: v, Label: fmt.Sprint(v)}
+			if i < len(enumNames) {
+				opt.Label = enumNames[i]
+			}
+			opts = append(opts, opt)
+		}
+	case len(sch.OneOf) > 0:
+		for _, s := range sch.OneOf {
+			if s.Const == nil {
+				return nil
+			}
+			opt := Option{Value: *s.Const, Label: s.Title}
+			if opt.Label == "" {
+				opt.Label = fmt.Sprint(*s.Const)
+			}
+			opts = append(opts, opt)
+		}
+	}
+	return opts
+}
+
+func widget(schemas []*jsonschema.Schema, f *Field, h *hints) string {
+	if h.widget != "" {
+		return h.widget
+	}
+	if f.Options != nil {
+		return "select"
+	}
+	switch f.Type {
+	case "boolean":
+		return "checkbox"
+	case "string":
+		for _, s := range schemas {
+			if s.WriteOnly {
+				return "password"
+			}
+		}
+		format := h.format
+		if format == "" {
+			for _, s := range schemas {
+				if s.Format != nil {
+					format = s.Format.Name
+					break
+				}
+			}
+		}
+		switch format {
+		case "email", "idn-email":
+			return "email"
+		case "uri", "iri", "uri-reference", "iri-reference":
+			return "uri"
+		case "date", "date-time", "time":
+			return format
+		}
+	}
+	return ""
+}
+
+// titleCase converts property name such as "firstName"
+// or "first_name" to "First Name".
+func titleCase(name string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			word[0] = unicode.ToUpper(word[0])
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	prev := ' '
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return strings.Join(words, " ")
+}
+
+// JSONForms returns ui schema of f for JSON Forms. Objects are
+// rendered as VerticalLayout at root, and as Group otherwise. The
+// controls of array items are given in `options.detail`.
+func (f *Field) JSONForms() map[string]any {
+	if f.Type == "object" && len(f.Fields) > 0 {
+		return map[string]any{"type": "VerticalLayout", "elements": f.jsonFormsElements()}
+	}
+	return f.jsonFormsControl()
+}
+
+func (f *Field) jsonFormsElements() []any {
+	var elements []any
+	for _, child := range f.Fields {
+		if child.Type == "object" && len(child.Fields) > 0 {
+			elements = append(elements, map[string]any{
+				"type":     "Group",
+				"label":    child.Label,
+				"elements": child.jsonFormsElements(),
+			})
+		} else {
+			elements = append(elements, child.jsonFormsControl())
+		}
+	}
+	return elements
+}
+
+func (f *Field) jsonFormsControl() map[string]any {
+	control := map[string]any{"type": "Control", "scope": f.Scope, "label": f.Label}
+	opts := map[string]any{}
+	if f.ReadOnly {
+		opts["readonly"] = true
+	}
+	if f.Widget == "textarea" {
+		opts["multi"] = true
+	}
+	if f.Items != nil && f.Items.Type == "object" && len(f.Items.Fields) > 0 {
+		opts["detail"] = f.Items.JSONForms()
+	}
+	if len(opts) > 0 {
+		control["options"] = opts
+	}
+	return control
+}
+
+// RJSF returns ui schema of f for react-jsonschema-form.
+func (f *Field) RJSF() map[string]any {
+	ui := map[string]any{}
+	if f.Label != "" {
+		ui["ui:title"] = f.Label
+	}
+	if f.Widget != "" {
+		ui["ui:widget"] = f.Widget
+	}
+	if f.ReadOnly {
+		ui["ui:readonly"] = true
+	}
+	if f.Options != nil {
+		var labels []any
+		for _, opt := range f.Options {
+			labels = append(labels, opt.Label)
+		}
+		ui["ui:enumNames"] = labels
+	}
+	if len(f.Fields) > 0 {
+		var order []any
+		for _, child := range f.Fields {
+			order = append(order, child.Name)
+			ui[child.Name] = child.RJSF()
+		}
+		ui["ui:order"] = order
+	}
+	if f.Items != nil {
+		ui["items"] = f.Items.RJSF()
+	}
+	return ui
+}
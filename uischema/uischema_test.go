@@ -0,0 +1,234 @@
+package uischema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/uischema"
+)
+
+const personSchema = `{
+	"type": "object",
+	"properties": {
+		"firstName": { "type": "string", "title": "Given Name" },
+		"email": { "type": "string", "format": "email" },
+		"password": { "type": "string", "writeOnly": true },
+		"bio": { "type": "string", "x-widget": "textarea" },
+		"size": { "enum": ["S", "M"], "x-enumNames": ["Small", "Medium"] },
+		"color": {
+			"oneOf": [
+				{ "const": "r", "title": "Red" },
+				{ "const": "g", "title": "Green" }
+			]
+		},
+		"active": { "type": "boolean", "default": true, "readOnly": true },
+		"address": { "$ref": "#/$defs/address" },
+		"friends": { "type": "array", "items": { "$ref": "#" } }
+	},
+	"required": ["firstName", "email"],
+	"x-order": ["firstName", "email"],
+	"$defs": {
+		"address": {
+			"type": "object",
+			"properties": {
+				"city": { "type": "string" },
+				"zip_code": { "type": ["string", "null"] }
+			}
+		}
+	}
+}`
+
+func compile(t *testing.T, schema string, vocab bool) *jsonschema.Schema {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if vocab {
+		c.RegisterVocabulary(uischema.Vocab())
+		c.AssertVocabs()
+	}
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func TestExport(t *testing.T) {
+	f := uischema.Export(compile(t, personSchema, true))
+	if f.Type != "object" {
+		t.Fatalf("root type: got %q", f.Type)
+	}
+	var names []string
+	for _, child := range f.Fields {
+		names = append(names, child.Name)
+	}
+	want := []string{"firstName", "email", "active", "address", "bio", "color", "friends", "password", "size"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("order:\n got %v\nwant %v", names, want)
+	}
+	field := func(name string) *uischema.Field {
+		for _, child := range f.Fields {
+			if child.Name == name {
+				return child
+			}
+		}
+		t.Fatalf("field %s not found", name)
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		label    string
+		widget   string
+		required bool
+	}{
+		{"firstName", "Given Name", "", true},
+		{"email", "Email", "email", true},
+		{"password", "Password", "password", false},
+		{"bio", "Bio", "textarea", false},
+		{"size", "Size", "select", false},
+		{"color", "Color", "select", false},
+		{"active", "Active", "checkbox", false},
+	}
+	for _, test := range tests {
+		fd := field(test.name)
+		if fd.Label != test.label || fd.Widget != test.widget || fd.Required != test.required {
+			t.Errorf("%s: got label=%q widget=%q required=%v, want %q %q %v", test.name, fd.Label, fd.Widget, fd.Required, test.label, test.widget, test.required)
+		}
+	}
+
+	wantOpts := []uischema.Option{{Value: "S", Label: "Small"}, {Value: "M", Label: "Medium"}}
+	if opts := field("size").Options; !reflect.DeepEqual(opts, wantOpts) {
+		t.Errorf("size options: got %v, want %v", opts, wantOpts)
+	}
+	wantOpts = []uischema.Option{{Value: "r", Label: "Red"}, {Value: "g", Label: "Green"}}
+	if opts := field("color").Options; !reflect.DeepEqual(opts, wantOpts) {
+		t.Errorf("color options: got %v, want %v", opts, wantOpts)
+	}
+	if fd := field("active"); !fd.ReadOnly || fd.Default == nil || *fd.Default != true {
+		t.Errorf("active: got readOnly=%v default=%v", fd.ReadOnly, fd.Default)
+	}
+
+	address := field("address")
+	if len(address.Fields) != 2 {
+		t.Fatalf("address: got %d fields", len(address.Fields))
+	}
+	zip := address.Fields[1]
+	if zip.Scope != "#/properties/address/properties/zip_code" || zip.Label != "Zip Code" || zip.Type != "string" {
+		t.Errorf("zip_code: got %+v", zip)
+	}
+
+	// recursive
+	friends := field("friends")
+	if friends.Items == nil || friends.Items.Type != "object" || friends.Items.Fields != nil {
+		t.Errorf("friends: got %+v", friends.Items)
+	}
+}
+
+func TestExportWithoutVocab(t *testing.T) {
+	f := uischema.Export(compile(t, personSchema, false))
+	if f.Fields[0].Name != "active" {
+		t.Errorf("want alphabetical order, got %s first", f.Fields[0].Name)
+	}
+	for _, child := range f.Fields {
+		if child.Name == "size" && child.Options[0].Label != "S" {
+			t.Errorf("size: got label %q, want value as label", child.Options[0].Label)
+		}
+	}
+}
+
+func TestJSONForms(t *testing.T) {
+	f := uischema.Export(compile(t, `{
+		"type": "object",
+		"properties": {
+			"name": { "type": "string", "readOnly": true },
+			"address": {
+				"type": "object",
+				"properties": { "city": { "type": "string" } }
+			},
+			"tags": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": { "key": { "type": "string" } }
+				}
+			}
+		}
+	}`, true))
+	want := `{
+		"type": "VerticalLayout",
+		"elements": [
+			{
+				"type": "Group",
+				"label": "Address",
+				"elements": [
+					{ "type": "Control", "scope": "#/properties/address/properties/city", "label": "City" }
+				]
+			},
+			{ "type": "Control", "scope": "#/properties/name", "label": "Name", "options": { "readonly": true } },
+			{
+				"type": "Control",
+				"scope": "#/properties/tags",
+				"label": "Tags",
+				"options": {
+					"detail": {
+						"type": "VerticalLayout",
+						"elements": [
+							{ "type": "Control", "scope": "#/properties/key", "label": "Key" }
+						]
+					}
+				}
+			}
+		]
+	}`
+	checkJSON(t, f.JSONForms(), want)
+}
+
+func TestRJSF(t *testing.T) {
+	f := uischema.Export(compile(t, `{
+		"type": "object",
+		"properties": {
+			"email": { "type": "string", "format": "email" },
+			"size": { "enum": ["S", "M"], "x-enumNames": ["Small", "Medium"] },
+			"tags": { "type": "array", "items": { "type": "string", "format": "date" } }
+		},
+		"x-order": ["size"]
+	}`, true))
+	want := `{
+		"ui:order": ["size", "email", "tags"],
+		"email": { "ui:title": "Email", "ui:widget": "email" },
+		"size": { "ui:title": "Size", "ui:widget": "select", "ui:enumNames": ["Small", "Medium"] },
+		"tags": {
+			"ui:title": "Tags",
+			"items": { "ui:widget": "date" }
+		}
+	}`
+	checkJSON(t, f.RJSF(), want)
+}
+
+func checkJSON(t *testing.T, got any, want string) {
+	t.Helper()
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g, w any
+	if err := json.Unmarshal(b, &g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s", b)
+	}
+}
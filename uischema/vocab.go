@@ -0,0 +1,114 @@
+package uischema
+
+import (
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Vocab returns vocabulary which introduces keywords used as UI hints:
+//   - `x-order` lists property names in display order
+//   - `x-enumNames` gives labels of `enum` values, in same order
+//   - `x-widget` overrides the widget
+//
+// For example:
+//
+//	{
+//		"type": "object",
+//		"properties": {
+//			"name": { "type": "string" },
+//			"bio": { "type": "string", "x-widget": "textarea" },
+//			"size": { "enum": ["S", "M"], "x-enumNames": ["Small", "Medium"] }
+//		},
+//		"x-order": ["name", "size"]
+//	}
+//
+// It also records `format` of schemas, so that widgets are
+// derived from it even if format assertions are not enabled.
+// The keywords do not affect validation.
+func Vocab() *jsonschema.Vocabulary {
+	url := "https://github.com/santhosh-tekuri/jsonschema/vocab/ui"
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"x-order": {
+				"type": "array",
+				"items": { "type": "string" },
+				"uniqueItems": true
+			},
+			"x-enumNames": {
+				"type": "array",
+				"items": { "type": "string" }
+			},
+			"x-widget": { "type": "string" }
+		}
+	}`))
+	if err != nil {
+		panic(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, doc); err != nil {
+		panic(err)
+	}
+	return &jsonschema.Vocabulary{
+		URL:     url,
+		Schema:  c.MustCompile(url),
+		Compile: compileHints,
+	}
+}
+
+type hints struct {
+	order     []string
+	enumNames []string
+	widget    string
+	format    string
+}
+
+func compileHints(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	strs := func(v any) []string {
+		var arr []string
+		items, _ := v.([]any)
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				arr = append(arr, s)
+			}
+		}
+		return arr
+	}
+	h := &hints{
+		order:     strs(obj["x-order"]),
+		enumNames: strs(obj["x-enumNames"]),
+	}
+	h.widget, _ = obj["x-widget"].(string)
+	h.format, _ = obj["format"].(string)
+	if h.order == nil && h.enumNames == nil && h.widget == "" && h.format == "" {
+		return nil, nil
+	}
+	return h, nil
+}
+
+func (*hints) Validate(ctx *jsonschema.ValidatorContext, v any) {}
+
+// merge sets hints from h1, which are not set in h.
+func (h *hints) merge(h1 *hints) {
+	if h.order == nil {
+		h.order = h1.order
+	}
+	if h.enumNames == nil {
+		h.enumNames = h1.enumNames
+	}
+	if h.widget == "" {
+		h.widget = h1.widget
+	}
+	if h.format == "" {
+		h.format = h1.format
+	}
+}
+
+func hintsOf(sch *jsonschema.Schema) *hints {
+	for _, ext := range sch.Extensions {
+		if h, ok := ext.(*hints); ok {
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package jsonschema
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Unevaluated validates v, and returns json-pointers of the
+// properties and items in v, which are not evaluated by any
+// schema applied to their parent value, as per semantics of
+// `unevaluatedProperties` and `unevaluatedItems`.
+//
+// This can be used to warn on unknown fields, without using
+// `unevaluatedProperties: false` which fails validation.
+//
+// If v is not valid, nil and the validation error are returned.
+func (sch *Schema) Unevaluated(v any) ([]string, error) {
+	ue, err := sch.validateWith(v, runOpts{trackEval: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// same location may be evaluated by multiple schemas.
+	// so intersect the results at each location.
+	results := map[string]*uneval{}
+	var locs []string
+	for _, r := range append([]unevalAt{{nil, ue}}, ue.descendants...) {
+		loc := jsonPtr(r.vloc)
+		if ue, ok := results[loc]; ok {
+			ue.merge(r.uneval)
+		} else {
+			results[loc] = r.uneval
+			locs = append(locs, loc)
+		}
+	}
+
+	var ptrs []string
+	for _, loc := range locs {
+		ue := results[loc]
+		for pname := range ue.props {
+			ptrs = append(ptrs, loc+"/"+escape(pname))
+		}
+		for i := range ue.items {
+			ptrs = append(ptrs, loc+"/"+strconv.Itoa(i))
+		}
+	}
+	slices.Sort(ptrs)
+	return ptrs, nil
+}
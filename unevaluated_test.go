@@ -0,0 +1,66 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestUnevaluated(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"properties": {
+			"name": { "type": "string" },
+			"address": {
+				"allOf": [
+					{ "properties": { "city": true } },
+					{ "properties": { "zip": true } }
+				]
+			},
+			"tags": { "prefixItems": [ true ] },
+			"meta": { "additionalProperties": true }
+		},
+		"anyOf": [
+			{ "properties": { "age": { "type": "integer" } } },
+			{ "properties": { "nick": { "type": "integer" } } }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", schema); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+		"name": "john",
+		"age": 30,
+		"nick": "jo",
+		"extra": 1,
+		"address": { "city": "x", "zip": "y", "street": "z" },
+		"tags": [ "a", "b" ],
+		"meta": { "x": { "y": 1 } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := sch.Unevaluated(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/address/street", "/extra", "/nick", "/tags/1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// invalid instance
+	if _, err := sch.Unevaluated(map[string]any{"name": 1}); err == nil {
+		t.Fatal("want validation error")
+	}
+}
@@ -0,0 +1,102 @@
+package jsonschema
+
+// Usage is statistics of keyword, draft and format usage
+// over a corpus of compiled schemas. This helps to plan
+// migrations, for example to find how many schemas still
+// use draft-04 `dependencies`.
+//
+// The zero value is ready to use. Schemas are added using
+// [Usage.Add].
+type Usage struct {
+	// Schemas is number of schemas added.
+	Schemas int
+
+	// Subschemas is number of schemas and their subschemas
+	// visited. A subschema shared by multiple schemas, such
+	// as those compiled with same [Compiler], is counted once.
+	Subschemas int
+
+	// Resources is number of schema resources visited,
+	// i.e. subschemas with `$id` plus root schemas.
+	Resources int
+
+	// Keywords maps keyword to number of subschemas using it.
+	Keywords map[string]int
+
+	// Drafts maps draft version to number of resources using it.
+	Drafts map[int]int
+
+	// Formats maps value of `format` to number of
+	// subschemas using it.
+	Formats map[string]int
+
+	// Deprecated maps construct to number of subschemas
+	// using it, where construct is deprecated or removed in
+	// draft 2020-12. The constructs are:
+	//   - "id": draft-04 `id`
+	//   - "definitions"
+	//   - "dependencies"
+	//   - "exclusiveMaximum:boolean", "exclusiveMinimum:boolean": draft-04 form
+	//   - "items:array": `items` with array value before draft 2020-12
+	//   - "additionalItems"
+	//   - "$recursiveRef", "$recursiveAnchor"
+	Deprecated map[string]int
+
+	seen map[*Schema]bool
+}
+
+// Add adds sch and all schemas reachable from it to u.
+// Subschemas which are never referenced, such as unused
+// `$defs`, are not compiled and hence not visited.
+func (u *Usage) Add(sch *Schema) {
+	if u.seen == nil {
+		u.seen = map[*Schema]bool{}
+		u.Keywords = map[string]int{}
+		u.Drafts = map[int]int{}
+		u.Formats = map[string]int{}
+		u.Deprecated = map[string]int{}
+	}
+	u.Schemas++
+	walkSchemas(sch, func(s *Schema) {
+		if u.seen[s] {
+			return
+		}
+		u.seen[s] = true
+		u.Subschemas++
+		if s.resource == s {
+			u.Resources++
+			u.Drafts[s.DraftVersion]++
+		}
+		obj, ok := s.doc.(map[string]any)
+		if !ok {
+			return
+		}
+		for kw := range obj {
+			u.Keywords[kw]++
+		}
+		if format, ok := obj["format"].(string); ok {
+			u.Formats[format]++
+		}
+		u.deprecated(s, obj)
+	})
+}
+
+func (u *Usage) deprecated(s *Schema, obj map[string]any) {
+	if _, ok := obj["id"].(string); ok && s.DraftVersion == 4 {
+		u.Deprecated["id"]++
+	}
+	for _, kw := range []string{"definitions", "dependencies", "additionalItems", "$recursiveRef", "$recursiveAnchor"} {
+		if _, ok := obj[kw]; ok {
+			u.Deprecated[kw]++
+		}
+	}
+	if s.ExclusiveMaximumBool != nil {
+		u.Deprecated["exclusiveMaximum:boolean"]++
+	}
+	if s.ExclusiveMinimumBool != nil {
+		u.Deprecated["exclusiveMinimum:boolean"]++
+	}
+	if _, ok := obj["items"].([]any); ok && s.DraftVersion < 2020 {
+		u.Deprecated["items:array"]++
+	}
+}
@@ -0,0 +1,67 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func TestUsage(t *testing.T) {
+	schemas := map[string]any{
+		"a.json": map[string]any{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"id":      "http://example.com/a.json",
+			"properties": map[string]any{
+				"n": map[string]any{"type": "number", "maximum": 5, "exclusiveMaximum": true},
+				"d": map[string]any{"type": "string", "format": "date"},
+			},
+			"dependencies": map[string]any{"n": []any{"d"}},
+			"items":        []any{map[string]any{"$ref": "b.json"}},
+		},
+		"b.json": map[string]any{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type":    "string",
+			"format":  "date",
+		},
+	}
+	c := jsonschema.NewCompiler()
+	for url, doc := range schemas {
+		if err := c.AddResource("http://example.com/"+url, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var u jsonschema.Usage
+	for _, url := range []string{"a.json", "b.json"} {
+		sch, err := c.Compile("http://example.com/" + url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u.Add(sch)
+	}
+
+	if u.Schemas != 2 {
+		t.Errorf("Schemas: got %d, want 2", u.Schemas)
+	}
+	if u.Subschemas != 5 {
+		t.Errorf("Subschemas: got %d, want 5", u.Subschemas)
+	}
+	if want := map[int]int{4: 1, 2020: 1}; !reflect.DeepEqual(u.Drafts, want) {
+		t.Errorf("Drafts: got %v, want %v", u.Drafts, want)
+	}
+	if want := map[string]int{"date": 2}; !reflect.DeepEqual(u.Formats, want) {
+		t.Errorf("Formats: got %v, want %v", u.Formats, want)
+	}
+	if got := u.Keywords["type"]; got != 3 {
+		t.Errorf("Keywords[type]: got %d, want 3", got)
+	}
+	want := map[string]int{
+		"id":                       1,
+		"dependencies":             1,
+		"items:array":              1,
+		"exclusiveMaximum:boolean": 1,
+	}
+	if !reflect.DeepEqual(u.Deprecated, want) {
+		t.Errorf("Deprecated: got %v, want %v", u.Deprecated, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/maphash"
+	"io"
 	"math/big"
 	gourl "net/url"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/santhosh-tekuri/jsonschema/v6/jsonpointer"
 	"github.com/santhosh-tekuri/jsonschema/v6/kind"
 	"golang.org/x/text/message"
 )
@@ -53,39 +55,12 @@ func (u url) join(ref string) (*urlFrag, error) {
 type jsonPointer string
 
 func escape(tok string) string {
-	tok = strings.ReplaceAll(tok, "~", "~0")
-	tok = strings.ReplaceAll(tok, "/", "~1")
-	return tok
+	return jsonpointer.Escape(tok)
 }
 
 func unescape(tok string) (string, bool) {
-	tilde := strings.IndexByte(tok, '~')
-	if tilde == -1 {
-		return tok, true
-	}
-	sb := new(strings.Builder)
-	for {
-		sb.WriteString(tok[:tilde])
-		tok = tok[tilde+1:]
-		if tok == "" {
-			return "", false
-		}
-		switch tok[0] {
-		case '0':
-			sb.WriteByte('~')
-		case '1':
-			sb.WriteByte('/')
-		default:
-			return "", false
-		}
-		tok = tok[1:]
-		tilde = strings.IndexByte(tok, '~')
-		if tilde == -1 {
-			sb.WriteString(tok)
-			break
-		}
-	}
-	return sb.String(), true
+	tok, err := jsonpointer.Unescape(tok)
+	return tok, err == nil
 }
 
 func (ptr jsonPointer) isEmpty() bool {
@@ -274,6 +249,20 @@ func isInteger(num any) bool {
 	return ok && rat.IsInt()
 }
 
+// isStrictInteger is same as isInteger, but numbers with
+// fraction or exponent, and go floats are not integers.
+func isStrictInteger(num any) bool {
+	switch num := num.(type) {
+	case float32, float64:
+		return false
+	case json.Number:
+		if strings.ContainsAny(string(num), ".eE") {
+			return false
+		}
+	}
+	return isInteger(num)
+}
+
 // quote returns single-quoted string.
 // used for embedding quoted strings in json.
 func quote(s string) string {
@@ -320,6 +309,10 @@ func equals(v1, v2 any) (bool, ErrorKind) {
 		v2, ok := v2.(string)
 		return ok && v1 == v2, nil
 	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if typeOf(v2) != numberType {
+			// string like "1" must not equal to number 1
+			return false, nil
+		}
 		num1, ok1 := new(big.Rat).SetString(fmt.Sprint(v1))
 		num2, ok2 := new(big.Rat).SetString(fmt.Sprint(v2))
 		return ok1 && ok2 && num1.Cmp(num2) == 0, nil
@@ -328,39 +321,78 @@ func equals(v1, v2 any) (bool, ErrorKind) {
 	}
 }
 
+// duplicates returns indexes of first pair of equal items.
+// Returns -1, -1, if there are no duplicates.
 func duplicates(arr []any) (int, int, ErrorKind) {
+	pairs, k := duplicatePairs(arr, 1)
+	if k != nil || len(pairs) == 0 {
+		return -1, -1, k
+	}
+	return pairs[0][0], pairs[0][1], nil
+}
+
+// duplicatePairs returns upto limit pairs of equal items, in order of
+// second item. Each item is paired with first item equal to it.
+// If limit is negative, all pairs are returned.
+func duplicatePairs(arr []any, limit int) ([][2]int, ErrorKind) {
+	var pairs [][2]int
+	add := func(j, i int) bool {
+		pairs = append(pairs, [2]int{j, i})
+		return limit < 0 || len(pairs) < limit
+	}
+
 	if len(arr) <= 20 {
 		for i := 1; i < len(arr); i++ {
 			for j := 0; j < i; j++ {
-				if ok, k := equals(arr[i], arr[j]); ok || k != nil {
-					return j, i, k
+				ok, k := equals(arr[i], arr[j])
+				if k != nil {
+					return nil, k
+				}
+				if ok {
+					if !add(j, i) {
+						return pairs, nil
+					}
+					break
 				}
 			}
 		}
-		return -1, -1, nil
+		return pairs, nil
 	}
 
+	// indexes has only first item of each set of equal items
 	m := make(map[uint64][]int)
 	h := new(maphash.Hash)
+outer:
 	for i, item := range arr {
 		h.Reset()
 		writeHash(item, h)
 		hash := h.Sum64()
-		indexes, ok := m[hash]
-		if ok {
-			for _, j := range indexes {
-				if ok, k := equals(item, arr[j]); ok || k != nil {
-					return j, i, k
+		indexes := m[hash]
+		for _, j := range indexes {
+			ok, k := equals(item, arr[j])
+			if k != nil {
+				return nil, k
+			}
+			if ok {
+				if !add(j, i) {
+					return pairs, nil
 				}
+				continue outer
 			}
 		}
-		indexes = append(indexes, i)
-		m[hash] = indexes
+		m[hash] = append(indexes, i)
 	}
-	return -1, -1, nil
+	return pairs, nil
 }
 
-func writeHash(v any, h *maphash.Hash) ErrorKind {
+// hashWriter is implemented by *maphash.Hash and *bufio.Writer.
+type hashWriter interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+}
+
+func writeHash(v any, h hashWriter) ErrorKind {
 	switch v := v.(type) {
 	case map[string]any:
 		_ = h.WriteByte(0)
@@ -371,12 +403,16 @@ func writeHash(v any, h *maphash.Hash) ErrorKind {
 		slices.Sort(props)
 		for _, prop := range props {
 			writeHash(prop, h)
-			writeHash(v[prop], h)
+			if k := writeHash(v[prop], h); k != nil {
+				return k
+			}
 		}
 	case []any:
 		_ = h.WriteByte(1)
 		for _, item := range v {
-			writeHash(item, h)
+			if k := writeHash(item, h); k != nil {
+				return k
+			}
 		}
 	case nil:
 		_ = h.WriteByte(2)
@@ -437,12 +473,66 @@ func (e *JSONPointerNotFoundError) Error() string {
 type SchemaValidationError struct {
 	URL string
 	Err error
+	up  urlPtr
 }
 
 func (e *SchemaValidationError) Error() string {
 	return fmt.Sprintf("%q is not valid against metaschema: %v", e.URL, e.Err)
 }
 
+// Unwrap returns the underlying error, which is
+// [*ValidationError] returned by the metaschema.
+func (e *SchemaValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Locations returns location in schema document of
+// each leaf error reported by the metaschema.
+//
+// This can be used to highlight invalid parts of the
+// schema document.
+func (e *SchemaValidationError) Locations() []SchemaErrorLocation {
+	verr, ok := e.Err.(*ValidationError)
+	if !ok {
+		return nil
+	}
+	var locs []SchemaErrorLocation
+	var collect func(verr *ValidationError)
+	collect = func(verr *ValidationError) {
+		if len(verr.Causes) == 0 {
+			ptr := e.up.ptr
+			for _, tok := range verr.InstanceLocation {
+				ptr = ptr.append(tok)
+			}
+			up := urlPtr{e.up.url, ptr}
+			locs = append(locs, SchemaErrorLocation{
+				URL:       up.String(),
+				Keyword:   verr.ErrorKind.KeywordPath(),
+				ErrorKind: verr.ErrorKind,
+			})
+		}
+		for _, cause := range verr.Causes {
+			collect(cause)
+		}
+	}
+	collect(verr)
+	return locs
+}
+
+// SchemaErrorLocation is location of an error in schema document.
+// see [SchemaValidationError.Locations].
+type SchemaErrorLocation struct {
+	// absolute url of invalid value in schema document,
+	// with json-pointer as fragment.
+	URL string
+
+	// keyword path in metaschema, which reported the error.
+	Keyword []string
+
+	// kind of error.
+	ErrorKind ErrorKind
+}
+
 // --
 
 // LocalizableError is an error whose message is localizable.
@@ -1,6 +1,7 @@
 package jsonschema
 
 import (
+	"encoding/json"
 	"hash/maphash"
 	"testing"
 )
@@ -72,6 +73,12 @@ func TestEquals(t *testing.T) {
 	}{
 		{1.0, 1, true},
 		{-1.0, -1, true},
+		{json.Number("9007199254740993"), json.Number("9007199254740992"), false},
+		{json.Number("9007199254740993"), int64(9007199254740993), true},
+		{json.Number("18446744073709551615"), uint64(18446744073709551615), true},
+		{json.Number("18446744073709551616"), uint64(18446744073709551615), false},
+		{json.Number("0.1"), json.Number("0.10000000000000001"), false},
+		{json.Number("1"), "1", false},
 	}
 	for _, test := range tests {
 		got, k := equals(test.v1, test.v2)
@@ -107,3 +114,30 @@ func TestHashEquals(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"e\u0301", 1},              // combining acute accent
+		{"\r\n", 1},                 // CR LF
+		{"\n\r", 2},                 // LF CR
+		{"\U0001F44D\U0001F3FD", 1}, // emoji modifier
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467", 1}, // zwj sequence
+		{"a\u200D\U0001F469", 2},                          // zwj not after pictographic
+		{"\U0001F1FA\U0001F1F8\U0001F1EE\U0001F1F3", 2},   // regional indicator pairs
+		{"\U0001F1FA\U0001F1F8\U0001F1EE", 2},             // unpaired regional indicator
+		{"\u1100\u1161\u11A8", 1},                         // hangul jamo L V T
+		{"\uD55C\uAD6D\uC5B4", 3},                         // hangul syllables
+		{"\u2764\uFE0F", 1},                               // variation selector
+		{"\u0928\u092E\u0938\u094D\u0924\u0947", 4},       // conjuncts are not joined
+	}
+	for _, test := range tests {
+		if got := graphemeCount(test.input); got != test.want {
+			t.Errorf("graphemeCount(%q): got %d, want %d", test.input, got, test.want)
+		}
+	}
+}
@@ -1,57 +1,123 @@
 package jsonschema
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"slices"
 	"strconv"
-	"unicode/utf8"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/santhosh-tekuri/jsonschema/v6/kind"
 	"golang.org/x/text/message"
 )
 
 func (sch *Schema) Validate(v any) error {
-	return sch.validate(v, nil, nil, nil, false, nil)
+	return sch.withFormatter(sch.validate(v, nil, nil, nil, false, nil, nil), nil)
 }
 
-func (sch *Schema) validate(v any, regexpEngine RegexpEngine, meta *Schema, resources map[jsonPointer]*resource, assertVocabs bool, vocabularies map[string]*Vocabulary) error {
+// ValidateWithOptions is same as [Schema.Validate], but guards
+// validation with limits in opts. If a limit is exceeded, validation
+// is aborted and [*LimitExceededError] is returned.
+func (sch *Schema) ValidateWithOptions(v any, opts *ValidateOptions) error {
+	err := sch.validate(v, nil, nil, nil, false, nil, newLimits(opts))
+	if opts != nil {
+		return sch.withFormatter(err, opts.Formatter)
+	}
+	return sch.withFormatter(err, nil)
+}
+
+// IsValid tells whether v is valid against sch. It is the cheapest
+// way to validate, as it stops as soon as v is known to be invalid,
+// without building errors. Use this for routing decisions, where the
+// reason of failure does not matter.
+func (sch *Schema) IsValid(v any) bool {
+	_, err := sch.doValidate(v, nil, nil, nil, false, nil, failFastLimits, runOpts{})
+	return err == nil
+}
+
+func (sch *Schema) validate(v any, regexpEngine RegexpEngine, meta *Schema, resources map[jsonPointer]*resource, assertVocabs bool, vocabularies map[string]*Vocabulary, limits *limits) error {
+	_, err := sch.doValidate(v, regexpEngine, meta, resources, assertVocabs, vocabularies, limits, runOpts{})
+	return err
+}
+
+// validateWith validates v, with per-run options opts.
+// It is used by introspection methods like [Schema.Unevaluated].
+func (sch *Schema) validateWith(v any, opts runOpts) (*uneval, error) {
+	return sch.doValidate(v, nil, nil, nil, false, nil, nil, opts)
+}
+
+// doValidate is same as validate, but also returns the
+// evaluation results, as requested by opts.
+func (sch *Schema) doValidate(v any, regexpEngine RegexpEngine, meta *Schema, resources map[jsonPointer]*resource, assertVocabs bool, vocabularies map[string]*Vocabulary, limits *limits, opts runOpts) (*uneval, error) {
+	if sch.warnFloat != nil {
+		warnFloats(v, "", sch.warnFloat)
+	}
 	vd := validator{
 		v:            v,
+		root:         v,
 		vloc:         make([]string, 0, 8),
 		sch:          sch,
 		scp:          &scope{sch, "", 0, nil},
-		uneval:       unevalFrom(v, sch, false),
+		uneval:       unevalFrom(v, sch, opts.trackEval),
 		errors:       nil,
-		boolResult:   false,
+		boolResult:   limits.failFast(),
 		regexpEngine: regexpEngine,
 		meta:         meta,
 		resources:    resources,
 		assertVocabs: assertVocabs,
 		vocabularies: vocabularies,
+		limits:       limits,
+		abort:        new(atomic.Pointer[RegexpMatchError]),
+		opts:         opts,
+	}
+	return sch.run(&vd)
+}
+
+// run validates using vd, and wraps the error
+// in an error of kind [kind.Schema].
+func (sch *Schema) run(vd *validator) (*uneval, error) {
+	limits := vd.limits
+	uneval, err := vd.validate()
+	if limits.exceeded() {
+		return nil, limits.err.Load()
 	}
-	if _, err := vd.validate(); err != nil {
+	if err := vd.abort.Load(); err != nil {
+		return nil, err
+	}
+	if err != nil {
 		verr := err.(*ValidationError)
 		var causes []*ValidationError
-		if _, ok := verr.ErrorKind.(*kind.Group); ok {
+		if limits != nil && limits.boolOnly {
+			return nil, err
+		} else if vd.boolResult {
+			// causes are not built
+		} else if _, ok := verr.ErrorKind.(*kind.Group); ok {
 			causes = verr.Causes
 		} else {
 			causes = []*ValidationError{verr}
 		}
-		return &ValidationError{
+		schErr := vd.scr.error()
+		*schErr = ValidationError{
 			SchemaURL:        sch.Location,
 			InstanceLocation: nil,
 			ErrorKind:        &kind.Schema{Location: sch.Location},
 			Causes:           causes,
+			Title:            sch.Title,
+			Description:      sch.Description,
 		}
+		return nil, schErr
 	}
 
-	return nil
+	return uneval, nil
 }
 
 type validator struct {
 	v            any
+	root         any // instance being validated
 	vloc         []string
 	sch          *Schema
 	scp          *scope
@@ -65,12 +131,49 @@ type validator struct {
 	resources    map[jsonPointer]*resource // resources which should be validated with their dialect
 	assertVocabs bool
 	vocabularies map[string]*Vocabulary
+
+	limits *limits // nil if no limits
+
+	// error which aborted validation, irrespective of
+	// keyword. shared by all validators of an instance
+	abort *atomic.Pointer[RegexpMatchError]
+
+	opts runOpts
+
+	scr *scratch // nil if not reusing memory, see Validator
+}
+
+// runOpts are options of a validation run, which record
+// evaluation details for introspection methods.
+type runOpts struct {
+	// track evaluated properties and items of all values,
+	// see Schema.Unevaluated
+	trackEval bool
+
+	// resolution of dynamic references are appended,
+	// if not nil. see Schema.TraceDynamicRefs
+	dynRefs *[]*DynamicRefResolution
+
+	// record schemas applied, see Schema.InstanceSchemas
+	mapSchemas bool
+
+	// matches of `patternProperties` are appended,
+	// if not nil. see Schema.TracePatternProperties
+	patMatches *[]*PatternPropertyMatch
+	// `additionalProperties` and `unevaluatedProperties`
+	// which are false, are ignored. see Schema.RemoveAdditional
+	relaxAdditional bool
 }
 
 func (vd *validator) validate() (*uneval, error) {
 	s := vd.sch
 	v := vd.v
 
+	// limits --
+	if vd.aborted() || !vd.limits.checkDepth(vd.vloc) {
+		return nil, &ValidationError{}
+	}
+
 	// boolean --
 	if s.Bool != nil {
 		if *s.Bool {
@@ -81,14 +184,20 @@ func (vd *validator) validate() (*uneval, error) {
 	}
 
 	// check cycle --
-	if scp := vd.scp.checkCycle(); scp != nil {
+	if scp := vd.scp.checkCycle(vd.limits.maxReentries()); scp != nil {
 		return nil, vd.error(&kind.RefCycle{
 			URL:              s.Location,
 			KeywordLocation1: vd.scp.kwLoc(),
 			KeywordLocation2: scp.kwLoc(),
+			InstanceLocation: jsonPtr(vd.vloc),
 		})
 	}
 
+	if vd.opts.mapSchemas {
+		// dropped along with other results, if validation fails
+		vd.uneval.applied = append(vd.uneval.applied, appliedSchema{slices.Clone(vd.vloc), s})
+	}
+
 	t := typeOf(v)
 	if t == invalidType {
 		return nil, vd.error(&kind.InvalidJsonValue{Value: v})
@@ -96,7 +205,14 @@ func (vd *validator) validate() (*uneval, error) {
 
 	// type --
 	if s.Types != nil && !s.Types.IsEmpty() {
-		matched := s.Types.contains(t) || (s.Types.contains(integerType) && t == numberType && isInteger(v))
+		matched := s.Types.contains(t)
+		if !matched && s.Types.contains(integerType) && t == numberType {
+			if s.strictIntegers {
+				matched = isStrictInteger(v)
+			} else {
+				matched = isInteger(v)
+			}
+		}
 		if !matched {
 			return nil, vd.error(&kind.Type{Got: t.String(), Want: s.Types.ToStrings()})
 		}
@@ -168,6 +284,11 @@ func (vd *validator) validate() (*uneval, error) {
 		vd.numValidate(v)
 	}
 
+	// $data --
+	if s.dataRefs != nil {
+		vd.dataValidate()
+	}
+
 	if len(vd.errors) == 0 || !vd.boolResult {
 		if s.DraftVersion >= 2019 {
 			vd.validateRefs()
@@ -175,7 +296,11 @@ func (vd *validator) validate() (*uneval, error) {
 		vd.condValidate()
 
 		for _, ext := range s.Extensions {
+			n := len(vd.errors)
 			ext.Validate(&ValidatorContext{vd}, v)
+			if s.instrumentation != nil {
+				s.instrumentation.ExtensionValidated(s, ext, len(vd.errors) > n)
+			}
 		}
 
 		if s.DraftVersion >= 2019 {
@@ -223,7 +348,10 @@ func (vd *validator) objValidate(obj map[string]any) {
 		return
 	}
 
+	// errors from iterating maps are sorted, for deterministic order
+
 	// dependencies --
+	start := len(vd.errors)
 	for pname, dep := range s.Dependencies {
 		if _, ok := obj[pname]; ok {
 			switch dep := dep.(type) {
@@ -237,44 +365,26 @@ func (vd *validator) objValidate(obj map[string]any) {
 		}
 	}
 
-	var additionalPros []string
-	for pname, pvalue := range obj {
-		if vd.boolResult && len(vd.errors) > 0 {
-			return
-		}
-		evaluated := false
+	vd.sortErrors(start)
 
-		// properties --
-		if sch, ok := s.Properties[pname]; ok {
-			evaluated = true
-			vd.addErr(vd.validateVal(sch, pvalue, pname))
-		}
-
-		// patternProperties --
-		for regex, sch := range s.PatternProperties {
-			if regex.MatchString(pname) {
-				evaluated = true
-				vd.addErr(vd.validateVal(sch, pvalue, pname))
+	var additionalPros []string
+	start = len(vd.errors)
+	if vd.limits.sortedProperties() {
+		for _, pname := range sortedKeys(obj) {
+			if !vd.validateProp(pname, obj[pname], &additionalPros) {
+				return
 			}
 		}
-
-		if !evaluated && s.AdditionalProperties != nil {
-			evaluated = true
-			switch additional := s.AdditionalProperties.(type) {
-			case bool:
-				if !additional {
-					additionalPros = append(additionalPros, pname)
-				}
-			case *Schema:
-				vd.addErr(vd.validateVal(additional, pvalue, pname))
+	} else {
+		for pname, pvalue := range obj {
+			if !vd.validateProp(pname, pvalue, &additionalPros) {
+				return
 			}
 		}
-
-		if evaluated {
-			delete(vd.uneval.props, pname)
-		}
 	}
+	vd.sortErrors(start)
 	if len(additionalPros) > 0 {
+		slices.Sort(additionalPros)
 		vd.addError(&kind.AdditionalProperties{Properties: additionalPros})
 	}
 
@@ -284,20 +394,30 @@ func (vd *validator) objValidate(obj map[string]any) {
 
 	// propertyNames --
 	if s.PropertyNames != nil {
-		for pname := range obj {
+		start := len(vd.errors)
+		for _, pname := range propNames(vd, obj) {
+			if s.stringPropertyNames && s.PropertyNames != vd.meta {
+				vd.validatePropName(pname)
+				continue
+			}
 			sch, meta, resources := s.PropertyNames, vd.meta, vd.resources
 			res := vd.metaResource(sch)
 			if res != nil {
 				meta = res.dialect.getSchema(vd.assertVocabs, vd.vocabularies)
 				sch = meta
 			}
-			if err := sch.validate(pname, vd.regexpEngine, meta, resources, vd.assertVocabs, vd.vocabularies); err != nil {
+			if err := sch.validate(pname, vd.regexpEngine, meta, resources, vd.assertVocabs, vd.vocabularies, vd.limits); err != nil {
+				if vd.aborted() {
+					return
+				}
 				verr := err.(*ValidationError)
 				verr.SchemaURL = s.PropertyNames.Location
 				verr.ErrorKind = &kind.PropertyNames{Property: pname}
+				verr.Title, verr.Description = s.PropertyNames.Title, s.PropertyNames.Description
 				vd.addErr(verr)
 			}
 		}
+		vd.sortErrors(start)
 	}
 
 	if s.DraftVersion == 6 {
@@ -305,13 +425,17 @@ func (vd *validator) objValidate(obj map[string]any) {
 	}
 
 	// dependentSchemas --
+	start = len(vd.errors)
 	for pname, sch := range s.DependentSchemas {
 		if _, ok := obj[pname]; ok {
 			vd.addErr(vd.validateSelf(sch, "", false))
 		}
 	}
 
+	vd.sortErrors(start)
+
 	// dependentRequired --
+	start = len(vd.errors)
 	for pname, reqd := range s.DependentRequired {
 		if _, ok := obj[pname]; ok {
 			if missing := vd.findMissing(obj, reqd); missing != nil {
@@ -319,6 +443,86 @@ func (vd *validator) objValidate(obj map[string]any) {
 			}
 		}
 	}
+	vd.sortErrors(start)
+}
+
+// validateProp validates property of object against `properties`,
+// `patternProperties` and `additionalProperties`. It returns false,
+// if validation of remaining properties can be skipped.
+func (vd *validator) validateProp(pname string, pvalue any, additionalPros *[]string) bool {
+	s := vd.sch
+	if (vd.boolResult && len(vd.errors) > 0) || vd.aborted() {
+		return false
+	}
+	evaluated := false
+
+	// properties --
+	if sch, ok := s.Properties[pname]; ok {
+		evaluated = true
+		vd.addErr(vd.validateVal(sch, pvalue, pname))
+	}
+
+	// patternProperties --
+	if vd.opts.patMatches == nil {
+		for regex, sch := range s.PatternProperties {
+			if vd.matchString(regex, pname) {
+				evaluated = true
+				vd.addErr(vd.validateVal(sch, pvalue, pname))
+			}
+		}
+	} else {
+		// in sorted order, for reproducible trace
+		for _, regex := range sortedPatterns(s.PatternProperties) {
+			if vd.matchString(regex, pname) {
+				evaluated = true
+				sch := s.PatternProperties[regex]
+				err := vd.validateVal(sch, pvalue, pname)
+				vd.tracePatternProperty(pname, regex, sch, err == nil)
+				vd.addErr(err)
+			}
+		}
+	}
+
+	if !evaluated && s.AdditionalProperties != nil {
+		evaluated = true
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional && !vd.opts.relaxAdditional {
+				*additionalPros = append(*additionalPros, pname)
+			}
+		case *Schema:
+			if !vd.opts.relaxAdditional || !isFalse(additional) {
+				vd.addErr(vd.validateVal(additional, pvalue, pname))
+			}
+		}
+	}
+
+	if evaluated {
+		delete(vd.uneval.props, pname)
+	}
+	return true
+}
+
+// propNames returns keys of m, in sorted order
+// if [ValidateOptions.SortedProperties] is set.
+func propNames[V any](vd *validator, m map[string]V) []string {
+	if vd.limits.sortedProperties() {
+		return sortedKeys(m)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
 }
 
 func (vd *validator) arrValidate(arr []any) {
@@ -340,11 +544,15 @@ func (vd *validator) arrValidate(arr []any) {
 
 	// uniqueItems --
 	if s.UniqueItems && len(arr) > 1 {
-		i, j, k := duplicates(arr)
+		limit := vd.limits.maxDuplicates()
+		if vd.boolResult {
+			limit = 1
+		}
+		pairs, k := duplicatePairs(arr, limit)
 		if k != nil {
 			vd.addError(k)
-		} else if i != -1 {
-			vd.addError(&kind.UniqueItems{Duplicates: [2]int{i, j}})
+		} else if len(pairs) > 0 {
+			vd.addError(&kind.UniqueItems{Duplicates: pairs[0], Pairs: pairs})
 		}
 	}
 
@@ -354,15 +562,11 @@ func (vd *validator) arrValidate(arr []any) {
 		// items --
 		switch items := s.Items.(type) {
 		case *Schema:
-			for i, item := range arr {
-				vd.addErr(vd.validateVal(items, item, strconv.Itoa(i)))
-			}
+			vd.itemsValidate(arr, 0, len(arr), func(int) *Schema { return items })
 			evaluated = len(arr)
 		case []*Schema:
 			min := minInt(len(arr), len(items))
-			for i, item := range arr[:min] {
-				vd.addErr(vd.validateVal(items[i], item, strconv.Itoa(i)))
-			}
+			vd.itemsValidate(arr, 0, min, func(i int) *Schema { return items[i] })
 			evaluated = min
 		}
 
@@ -374,50 +578,58 @@ func (vd *validator) arrValidate(arr []any) {
 					vd.addError(&kind.AdditionalItems{Count: len(arr) - evaluated})
 				}
 			case *Schema:
-				for i, item := range arr[evaluated:] {
-					vd.addErr(vd.validateVal(additional, item, strconv.Itoa(i)))
-				}
+				vd.itemsValidate(arr, evaluated, len(arr), func(int) *Schema { return additional })
 			}
 		}
 	} else {
 		evaluated := minInt(len(s.PrefixItems), len(arr))
 
 		// prefixItems --
-		for i, item := range arr[:evaluated] {
-			vd.addErr(vd.validateVal(s.PrefixItems[i], item, strconv.Itoa(i)))
-		}
+		vd.itemsValidate(arr, 0, evaluated, func(i int) *Schema { return s.PrefixItems[i] })
 
 		// items2020 --
 		if s.Items2020 != nil {
-			for i, item := range arr[evaluated:] {
-				vd.addErr(vd.validateVal(s.Items2020, item, strconv.Itoa(i)))
-			}
+			vd.itemsValidate(arr, evaluated, len(arr), func(int) *Schema { return s.Items2020 })
 		}
 	}
 
 	// contains --
 	if s.Contains != nil {
 		var errors []*ValidationError
-		var matched []int
+		var matched, failed []int
+
+		// when only validity matters, stop once minContains is
+		// satisfied, unless all matches are needed
+		enough := 1
+		if s.MinContains != nil {
+			enough = *s.MinContains
+		}
+		stopEarly := vd.boolResult && s.MaxContains == nil && len(vd.uneval.items) == 0
 
 		for i, item := range arr {
 			if err := vd.validateVal(s.Contains, item, strconv.Itoa(i)); err != nil {
-				errors = append(errors, err.(*ValidationError))
+				failed = append(failed, i)
+				if !vd.boolResult {
+					errors = append(errors, err.(*ValidationError))
+				}
 			} else {
 				matched = append(matched, i)
 				if s.DraftVersion >= 2020 {
 					delete(vd.uneval.items, i)
 				}
+				if stopEarly && len(matched) >= enough {
+					break
+				}
 			}
 		}
 
 		// minContains --
 		if s.MinContains != nil {
 			if len(matched) < *s.MinContains {
-				vd.addErrors(errors, &kind.MinContains{Got: matched, Want: *s.MinContains})
+				vd.addErrors(errors, &kind.MinContains{Got: matched, Want: *s.MinContains, Failed: failed})
 			}
 		} else if len(matched) == 0 {
-			vd.addErrors(errors, &kind.Contains{})
+			vd.addErrors(errors, &kind.Contains{Failed: failed})
 		}
 
 		// maxContains --
@@ -429,12 +641,35 @@ func (vd *validator) arrValidate(arr []any) {
 	}
 }
 
+// validatePropName validates pname against `propertyNames`, which
+// has only string assertions, without nested validation.
+func (vd *validator) validatePropName(pname string) {
+	s := vd.sch.PropertyNames
+	addError := func(cause ErrorKind) {
+		err := vd.error(&kind.PropertyNames{Property: pname, Cause: cause})
+		err.Title, err.Description = s.Title, s.Description
+		vd.addErr(err)
+	}
+	if s.MinLength != nil || s.MaxLength != nil {
+		n := s.lengthUnit.length(pname)
+		if s.MinLength != nil && n < *s.MinLength {
+			addError(&kind.MinLength{Got: n, Want: *s.MinLength})
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			addError(&kind.MaxLength{Got: n, Want: *s.MaxLength})
+		}
+	}
+	if s.Pattern != nil && !vd.matchString(s.Pattern, pname) {
+		addError(&kind.Pattern{Got: pname, Want: s.Pattern.String()})
+	}
+}
+
 func (vd *validator) strValidate(str string) {
 	s := vd.sch
 
 	strLen := -1
 	if s.MinLength != nil || s.MaxLength != nil {
-		strLen = utf8.RuneCount([]byte(str))
+		strLen = s.lengthUnit.length(str)
 	}
 
 	// minLength --
@@ -453,7 +688,7 @@ func (vd *validator) strValidate(str string) {
 
 	// pattern --
 	if s.Pattern != nil {
-		if !s.Pattern.MatchString(str) {
+		if !vd.matchString(s.Pattern, str) {
 			vd.addError(&kind.Pattern{Got: str, Want: s.Pattern.String()})
 		}
 	}
@@ -501,10 +736,14 @@ func (vd *validator) strValidate(str string) {
 			meta = res.dialect.getSchema(vd.assertVocabs, vd.vocabularies)
 			sch = meta
 		}
-		if err = sch.validate(*deserialized, vd.regexpEngine, meta, resources, vd.assertVocabs, vd.vocabularies); err != nil {
+		if err = sch.validate(*deserialized, vd.regexpEngine, meta, resources, vd.assertVocabs, vd.vocabularies, vd.limits); err != nil {
+			if vd.aborted() {
+				return
+			}
 			verr := err.(*ValidationError)
 			verr.SchemaURL = s.Location
 			verr.ErrorKind = &kind.ContentSchema{}
+			verr.Title, verr.Description = s.Title, s.Description
 			vd.addErr(verr)
 		}
 	}
@@ -581,7 +820,9 @@ func (vd *validator) condValidate() {
 		var errors []*ValidationError
 		for _, sch := range s.AnyOf {
 			if err := vd.validateSelf(sch, "", false); err != nil {
-				errors = append(errors, err.(*ValidationError))
+				if !vd.boolResult {
+					errors = append(errors, err.(*ValidationError))
+				}
 			} else {
 				matched = true
 				// for uneval, all schemas must be evaluated
@@ -596,7 +837,12 @@ func (vd *validator) condValidate() {
 	}
 
 	// oneOf
-	if len(s.OneOf) > 0 {
+	if i, ok := s.oneOfIndex.lookup(vd.v); ok {
+		// other subschemas cannot match
+		if err := vd.validateSelf(s.OneOf[i], "", false); err != nil {
+			vd.addErrors([]*ValidationError{err.(*ValidationError)}, &kind.OneOf{Subschemas: nil})
+		}
+	} else if len(s.OneOf) > 0 {
 		var matched = -1
 		var errors []*ValidationError
 		for i, sch := range s.OneOf {
@@ -635,96 +881,194 @@ func (vd *validator) unevalValidate() {
 
 	// unevaluatedProperties
 	if obj, ok := vd.v.(map[string]any); ok && s.UnevaluatedProperties != nil {
-		for pname := range vd.uneval.props {
-			if pvalue, ok := obj[pname]; ok {
-				vd.addErr(vd.validateVal(s.UnevaluatedProperties, pvalue, pname))
+		if !vd.opts.relaxAdditional || !isFalse(s.UnevaluatedProperties) {
+			start := len(vd.errors)
+			for _, pname := range propNames(vd, vd.uneval.props) {
+				if pvalue, ok := obj[pname]; ok {
+					vd.addErr(vd.validateVal(s.UnevaluatedProperties, pvalue, pname))
+				}
 			}
+			vd.sortErrors(start)
 		}
 		vd.uneval.props = nil
 	}
 
 	// unevaluatedItems
 	if arr, ok := vd.v.([]any); ok && s.UnevaluatedItems != nil {
+		start := len(vd.errors)
 		for i := range vd.uneval.items {
 			vd.addErr(vd.validateVal(s.UnevaluatedItems, arr[i], strconv.Itoa(i)))
 		}
+		vd.sortErrors(start)
 		vd.uneval.items = nil
 	}
 }
 
 // validation helpers --
 
-func (vd *validator) validateSelf(sch *Schema, refKw string, boolResult bool) error {
-	scp := vd.scp.child(sch, refKw, vd.scp.vid)
-	uneval := unevalFrom(vd.v, sch, !vd.uneval.isEmpty())
-	subvd := validator{
-		v:            vd.v,
-		vloc:         vd.vloc,
+// child returns validator of v at vloc, against sch. It
+// shares the state of validation run with vd.
+func (vd *validator) child(sch *Schema, v any, vloc []string, scp *scope, uneval *uneval, boolResult bool) *validator {
+	subvd := vd.scr.validator()
+	*subvd = validator{
+		v:            v,
+		root:         vd.root,
+		vloc:         vloc,
 		sch:          sch,
 		scp:          scp,
 		uneval:       uneval,
 		errors:       nil,
-		boolResult:   vd.boolResult || boolResult,
+		boolResult:   boolResult,
 		regexpEngine: vd.regexpEngine,
 		meta:         vd.meta,
 		resources:    vd.resources,
 		assertVocabs: vd.assertVocabs,
 		vocabularies: vd.vocabularies,
+		limits:       vd.limits,
+		abort:        vd.abort,
+		opts:         vd.opts,
+		scr:          vd.scr,
 	}
 	subvd.handleMeta()
+	return subvd
+}
+
+func (vd *validator) validateSelf(sch *Schema, refKw string, boolResult bool) error {
+	scp := vd.scr.scope(vd.scp, sch, refKw, vd.scp.vid)
+	uneval := vd.scr.uneval(vd.v, sch, !vd.uneval.isEmpty() || vd.opts.trackEval)
+	subvd := vd.child(sch, vd.v, vd.vloc, scp, uneval, vd.boolResult || boolResult)
 	uneval, err := subvd.validate()
 	if err == nil {
 		vd.uneval.merge(uneval)
+		vd.uneval.descendants = append(vd.uneval.descendants, uneval.descendants...)
+		vd.uneval.annots = append(vd.uneval.annots, uneval.annots...)
+		vd.uneval.applied = append(vd.uneval.applied, uneval.applied...)
 	}
 	return err
 }
 
+// itemsValidate validates arr[start:end], using schema returned
+// by sch for each index. Items are validated in parallel,
+// if enabled by ValidateOptions.Parallelism.
+func (vd *validator) itemsValidate(arr []any, start, end int, sch func(i int) *Schema) {
+	if end-start < minParallelItems || !vd.limits.acquireWorker() {
+		for i := start; i < end; i++ {
+			vd.addErr(vd.validateVal(sch(i), arr[i], strconv.Itoa(i)))
+		}
+		return
+	}
+
+	type result struct {
+		vloc   []string
+		uneval *uneval
+		err    error
+	}
+	results := make([]result, end-start)
+	pvd := *vd
+	pvd.scr = nil // scratch is not safe for concurrent use
+	var next atomic.Int64
+	next.Store(int64(start))
+	work := func() {
+		for {
+			i := int(next.Add(1)) - 1
+			if i >= end {
+				return
+			}
+			// clip, so that goroutines do not share backing array
+			vloc := append(slices.Clip(vd.vloc), strconv.Itoa(i))
+			uneval, err := pvd.subValidate(sch(i), arr[i], vloc)
+			results[i-start] = result{vloc, uneval, err}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for started := true; started; started = vd.limits.acquireWorker() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer vd.limits.releaseWorker()
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+
+	// report in order, as in sequential validation
+	for _, r := range results {
+		if r.err == nil {
+			vd.mergeUneval(r.vloc, r.uneval)
+		} else {
+			vd.addErr(r.err)
+		}
+	}
+}
+
+// arrays with less items are always validated sequentially,
+// as goroutine overhead outweighs the gain.
+const minParallelItems = 32
+
 func (vd *validator) validateVal(sch *Schema, v any, vtok string) error {
 	vloc := append(vd.vloc, vtok)
-	scp := vd.scp.child(sch, "", vd.scp.vid+1)
-	uneval := unevalFrom(v, sch, false)
-	subvd := validator{
-		v:            v,
-		vloc:         vloc,
-		sch:          sch,
-		scp:          scp,
-		uneval:       uneval,
-		errors:       nil,
-		boolResult:   vd.boolResult,
-		regexpEngine: vd.regexpEngine,
-		meta:         vd.meta,
-		resources:    vd.resources,
-		assertVocabs: vd.assertVocabs,
-		vocabularies: vd.vocabularies,
+	uneval, err := vd.subValidate(sch, v, vloc)
+	if err == nil {
+		vd.mergeUneval(vloc, uneval)
 	}
-	subvd.handleMeta()
-	_, err := subvd.validate()
 	return err
 }
 
+// subValidate validates v, at vloc, with sch.
+// It does not modify vd.
+func (vd *validator) subValidate(sch *Schema, v any, vloc []string) (*uneval, error) {
+	scp := vd.scr.scope(vd.scp, sch, "", vd.scp.vid+1)
+	uneval := vd.scr.uneval(v, sch, vd.opts.trackEval)
+	subvd := vd.child(sch, v, vloc, scp, uneval, vd.boolResult)
+	return subvd.validate()
+}
+
+// mergeUneval merges results of successful
+// validation of value at vloc.
+func (vd *validator) mergeUneval(vloc []string, uneval *uneval) {
+	vd.uneval.annots = append(vd.uneval.annots, uneval.annots...)
+	vd.uneval.applied = append(vd.uneval.applied, uneval.applied...)
+	if vd.opts.trackEval {
+		vd.uneval.addDescendant(vloc, uneval)
+	}
+}
+
 func (vd *validator) validateValue(sch *Schema, v any, vpath []string) error {
 	vloc := append(vd.vloc, vpath...)
-	scp := vd.scp.child(sch, "", vd.scp.vid+1)
-	uneval := unevalFrom(v, sch, false)
-	subvd := validator{
-		v:            v,
-		vloc:         vloc,
-		sch:          sch,
-		scp:          scp,
-		uneval:       uneval,
-		errors:       nil,
-		boolResult:   vd.boolResult,
-		regexpEngine: vd.regexpEngine,
-		meta:         vd.meta,
-		resources:    vd.resources,
-		assertVocabs: vd.assertVocabs,
-		vocabularies: vd.vocabularies,
+	uneval, err := vd.subValidate(sch, v, vloc)
+	if err == nil {
+		vd.mergeUneval(vloc, uneval)
 	}
-	subvd.handleMeta()
-	_, err := subvd.validate()
 	return err
 }
 
+func (vd *validator) matchString(re Regexp, s string) bool {
+	if !vd.limits.countPatternMatch(s, vd.vloc) {
+		return false
+	}
+	if fre, ok := re.(FallibleRegexp); ok {
+		matched, err := fre.TryMatchString(s)
+		if err != nil {
+			vd.abort.CompareAndSwap(nil, &RegexpMatchError{
+				Pattern:          re.String(),
+				InstanceLocation: append([]string{}, vd.vloc...),
+				Err:              err,
+			})
+			return false
+		}
+		return matched
+	}
+	return re.MatchString(s)
+}
+
+// aborted tells whether validation is aborted, because
+// a limit is exceeded or matching of a regexp failed.
+func (vd *validator) aborted() bool {
+	return vd.limits.exceeded() || vd.abort.Load() != nil
+}
+
 func (vd *validator) metaResource(sch *Schema) *resource {
 	if sch != vd.meta {
 		return nil
@@ -791,22 +1135,29 @@ func (vd *validator) resolveDynamicAnchor(name string, fallback *Schema) *Schema
 func (vd *validator) validateRefs() {
 	// $recursiveRef --
 	if sch := vd.sch.RecursiveRef; sch != nil {
+		reason := "initial target has no $recursiveAnchor"
 		if sch.RecursiveAnchor {
 			sch = vd.resolveRecursiveAnchor(sch)
+			reason = "outermost resource in dynamic scope with $recursiveAnchor"
 		}
+		vd.traceDynamicRef("$recursiveRef", "", vd.sch.RecursiveRef, sch, reason)
 		vd.addErr(vd.validateRef(sch, "$recursiveRef"))
 	}
 
 	// $dynamicRef --
 	if dref := vd.sch.DynamicRef; dref != nil {
 		sch := dref.Ref // initial target
+		reason := "$dynamicRef has no anchor"
 		if dref.Anchor != "" {
 			// $dynamicRef includes anchor
+			reason = "initial target has no matching $dynamicAnchor"
 			if sch.DynamicAnchor == dref.Anchor {
 				// initial target has matching $dynamicAnchor
 				sch = vd.resolveDynamicAnchor(dref.Anchor, sch)
+				reason = "outermost resource in dynamic scope with matching $dynamicAnchor"
 			}
 		}
+		vd.traceDynamicRef("$dynamicRef", dref.Anchor, dref.Ref, sch, reason)
 		vd.addErr(vd.validateRef(sch, "$dynamicRef"))
 	}
 }
@@ -821,12 +1172,17 @@ func (vd *validator) error(kind ErrorKind) *ValidationError {
 	if vd.boolResult {
 		return &ValidationError{}
 	}
-	return &ValidationError{
+	vd.limits.countError(vd.vloc)
+	err := vd.scr.error()
+	*err = ValidationError{
 		SchemaURL:        vd.sch.Location,
-		InstanceLocation: vd.instanceLocation(),
+		InstanceLocation: vd.scr.location(vd.vloc),
 		ErrorKind:        kind,
 		Causes:           nil,
+		Title:            vd.sch.Title,
+		Description:      vd.sch.Description,
 	}
+	return err
 }
 
 func (vd *validator) addErr(err error) {
@@ -845,6 +1201,49 @@ func (vd *validator) addErrors(errors []*ValidationError, kind ErrorKind) {
 	vd.errors = append(vd.errors, err)
 }
 
+// sortErrors sorts errors added since start, which are produced
+// by iterating over a map, so that the order of errors does not
+// depend on map iteration order. Errors are sorted by instance
+// location, and then by absolute keyword location.
+func (vd *validator) sortErrors(start int) {
+	if len(vd.errors)-start < 2 {
+		return
+	}
+	slices.SortStableFunc(vd.errors[start:], compareErrors)
+}
+
+func compareErrors(a, b *ValidationError) int {
+	if c := compareLocations(a.InstanceLocation, b.InstanceLocation); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.AbsoluteKeywordLocation(), b.AbsoluteKeywordLocation()); c != 0 {
+		return c
+	}
+	pa, aok := a.ErrorKind.(*kind.PropertyNames)
+	pb, bok := b.ErrorKind.(*kind.PropertyNames)
+	if aok && bok {
+		return strings.Compare(pa.Property, pb.Property)
+	}
+	return 0
+}
+
+// compareLocations compares instance locations token by token.
+// Array indexes are compared numerically.
+func compareLocations(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		x, xerr := strconv.Atoi(a[i])
+		y, yerr := strconv.Atoi(b[i])
+		if xerr == nil && yerr == nil {
+			return cmp.Compare(x, y)
+		}
+		return strings.Compare(a[i], b[i])
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
 func (vd *validator) findMissing(obj map[string]any, reqd []string) []string {
 	var missing []string
 	for _, pname := range reqd {
@@ -879,17 +1278,26 @@ func (sc *scope) child(sch *Schema, refKeyword string, vid int) *scope {
 	return &scope{sch, refKeyword, vid, sc}
 }
 
-func (sc *scope) checkCycle() *scope {
+// checkCycle returns the scope which entered same schema
+// for same value, if there are more than maxReentries such
+// scopes. returned scope is the one, farthest from sc.
+func (sc *scope) checkCycle(maxReentries int) *scope {
+	var found *scope
+	n := 0
 	scp := sc.parent
 	for scp != nil {
 		if scp.vid != sc.vid {
 			break
 		}
 		if scp.sch == sc.sch {
-			return scp
+			found = scp
+			n++
 		}
 		scp = scp.parent
 	}
+	if n > maxReentries {
+		return found
+	}
 	return nil
 }
 
@@ -913,10 +1321,42 @@ func (sc *scope) kwLoc() string {
 type uneval struct {
 	props map[string]struct{}
 	items map[int]struct{}
+
+	// results of values inside, when tracking evaluation.
+	descendants []unevalAt
+
+	// annotations added by extensions.
+	annots []*Annotation
+
+	// schemas applied, if mapping schemas.
+	applied []appliedSchema
+}
+
+type appliedSchema struct {
+	vloc []string
+	sch  *Schema
+}
+
+type unevalAt struct {
+	vloc   []string
+	uneval *uneval
+}
+
+// addDescendant adds result of value at vloc, along with its descendants.
+func (ue *uneval) addDescendant(vloc []string, other *uneval) {
+	ue.descendants = append(ue.descendants, unevalAt{slices.Clone(vloc), other})
+	ue.descendants = append(ue.descendants, other.descendants...)
+	other.descendants = nil
 }
 
 func unevalFrom(v any, sch *Schema, callerNeeds bool) *uneval {
 	uneval := &uneval{}
+	initUneval(uneval, v, sch, callerNeeds)
+	return uneval
+}
+
+// initUneval initializes zero uneval, for validating v with sch.
+func initUneval(uneval *uneval, v any, sch *Schema, callerNeeds bool) {
 	switch v := v.(type) {
 	case map[string]any:
 		if !sch.allPropsEvaluated && (callerNeeds || sch.UnevaluatedProperties != nil) {
@@ -933,7 +1373,6 @@ func unevalFrom(v any, sch *Schema, callerNeeds bool) *uneval {
 			}
 		}
 	}
-	return uneval
 }
 
 func (ue *uneval) merge(other *uneval) {
@@ -967,9 +1406,37 @@ type ValidationError struct {
 
 	// holds nested errors
 	Causes []*ValidationError
+
+	// title and description of the schema at SchemaURL.
+	// see [OutputOptions.Titles].
+	Title       string
+	Description string
+
+	formatter Formatter // used by Error, if not nil
 }
 
 type ErrorKind interface {
+	// KeywordPath returns path of the failed keyword relative
+	// to the schema, as unescaped tokens.
 	KeywordPath() []string
+
 	LocalizedString(*message.Printer) string
 }
+
+// KeywordPath returns path of the failed keyword relative to
+// [ValidationError.SchemaURL]. For errors of kind [kind.Reference],
+// it is the path of the reference keyword, and Causes are
+// relative to the referenced schema.
+func (e *ValidationError) KeywordPath() KeywordPath {
+	return KeywordPath(e.ErrorKind.KeywordPath())
+}
+
+// KeywordPath is path of keyword within schema, as tokens.
+// Tokens are not escaped.
+type KeywordPath []string
+
+// String returns json-pointer of the path, with tokens
+// escaped as per RFC 6901, for example "/properties/a~1b".
+func (p KeywordPath) String() string {
+	return jsonPtr(p)
+}
@@ -1,5 +1,11 @@
 package jsonschema
 
+import (
+	"slices"
+
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
 // CompilerContext provides helpers for
 // compiling a [Vocabulary].
 type CompilerContext struct {
@@ -14,6 +20,35 @@ func (ctx *CompilerContext) Enqueue(schPath []string) *Schema {
 	return ctx.c.enqueuePtr(ptr)
 }
 
+// EnqueueRef is like [CompilerContext.Enqueue], but the schema
+// is given by ref, which is resolved against [CompilerContext.BaseURL]
+// same as `$ref` keyword. The ref can be local or remote.
+func (ctx *CompilerContext) EnqueueRef(ref string) (*Schema, error) {
+	return ctx.c.enqueueRefStr(ctx.c.up.String(), ref)
+}
+
+// Draft returns draft of the schema being compiled.
+func (ctx *CompilerContext) Draft() *Draft {
+	return ctx.c.res.dialect.draft
+}
+
+// Dialect returns url of metaschema of the schema being compiled.
+func (ctx *CompilerContext) Dialect() string {
+	return ctx.c.res.dialect.metaURL()
+}
+
+// BaseURL returns base url of the schema being compiled,
+// against which relative references are resolved.
+func (ctx *CompilerContext) BaseURL() string {
+	return ctx.c.res.id.String()
+}
+
+// Location returns absolute url of the schema being compiled,
+// with json-pointer as fragment.
+func (ctx *CompilerContext) Location() string {
+	return ctx.c.up.String()
+}
+
 // Vocabulary defines a set of keywords, their syntax and
 // their semantics.
 type Vocabulary struct {
@@ -40,6 +75,11 @@ type Vocabulary struct {
 type SchemaExt interface {
 	// Validate validates v against and errors if any are reported
 	// to ctx.
+	//
+	// Validation does not use panics internally. Infinite loops,
+	// invalid json values and exceeded limits are reported as errors
+	// by [ValidatorContext.Validate]. So extensions are free to use
+	// recover, and they never see panics other than their own.
 	Validate(ctx *ValidatorContext, v any)
 }
 
@@ -61,6 +101,51 @@ func (ctx *ValidatorContext) Validate(sch *Schema, v any, vpath []string) error
 	}
 }
 
+// InstanceLocation returns location of the value being
+// validated, as reference tokens of json-pointer.
+func (ctx *ValidatorContext) InstanceLocation() []string {
+	return slices.Clone(ctx.vd.vloc)
+}
+
+// Scope returns schemas in the dynamic scope, starting from
+// outermost schema to the schema being validated.
+func (ctx *ValidatorContext) Scope() []*Schema {
+	var schemas []*Schema
+	for scp := ctx.vd.scp; scp != nil; scp = scp.parent {
+		schemas = append(schemas, scp.sch)
+	}
+	slices.Reverse(schemas)
+	return schemas
+}
+
+// Depth returns nesting depth of the value being validated,
+// i.e. number of tokens in [ValidatorContext.InstanceLocation].
+// Depth of the instance is 0.
+func (ctx *ValidatorContext) Depth() int {
+	return len(ctx.vd.vloc)
+}
+
+// ScopeDepth returns number of schemas in the dynamic scope,
+// i.e. length of [ValidatorContext.Scope], without allocating.
+// Extensions which validate recursively can use this to
+// bound the recursion.
+func (ctx *ValidatorContext) ScopeDepth() int {
+	n := 0
+	for scp := ctx.vd.scp; scp != nil; scp = scp.parent {
+		n++
+	}
+	return n
+}
+
+// ResolveDynamicAnchor returns the schema with `$dynamicAnchor` name,
+// in outermost schema resource of the dynamic scope, same as
+// `$dynamicRef` resolution. Returns nil, if no such schema is found.
+//
+// Use [ValidatorContext.Validate] to validate against the schema returned.
+func (ctx *ValidatorContext) ResolveDynamicAnchor(name string) *Schema {
+	return ctx.vd.resolveDynamicAnchor(name, nil)
+}
+
 // EvaluatedProp marks given property of current object as evaluated.
 func (ctx *ValidatorContext) EvaluatedProp(pname string) {
 	delete(ctx.vd.uneval.props, pname)
@@ -71,14 +156,58 @@ func (ctx *ValidatorContext) EvaluatedItem(index int) {
 	delete(ctx.vd.uneval.items, index)
 }
 
+// EvaluatedItems marks items with index in range [start, end)
+// of current array as evaluated.
+func (ctx *ValidatorContext) EvaluatedItems(start, end int) {
+	for i := range ctx.vd.uneval.items {
+		if i >= start && i < end {
+			delete(ctx.vd.uneval.items, i)
+		}
+	}
+}
+
+// AddAnnotation attaches value of given keyword to current value.
+// Annotations of failed schemas are dropped, and those of successful
+// ones are available via [Schema.Annotations].
+func (ctx *ValidatorContext) AddAnnotation(keyword string, value any) {
+	ctx.vd.uneval.annots = append(ctx.vd.uneval.annots, &Annotation{
+		InstanceLocation: slices.Clone(ctx.vd.vloc),
+		SchemaURL:        ctx.vd.sch.Location,
+		Keyword:          keyword,
+		Value:            value,
+	})
+}
+
 // AddError reports validation-error of given kind.
 func (ctx *ValidatorContext) AddError(k ErrorKind) {
 	ctx.vd.addError(k)
 }
 
-// AddErrors reports validation-errors of given kind.
-func (ctx *ValidatorContext) AddErrors(errors []*ValidationError, k ErrorKind) {
-	ctx.vd.addErrors(errors, k)
+// AddErrors reports validation-error of given kind, with non-nil
+// causes as its causes. Nothing is reported if all causes are nil.
+// This is typically used by applicators, to group errors of their
+// subschemas, like built-in `allOf`:
+//
+//	ctx.AddErrors(&kind.AllOf{}, ctx.Validate(s1, v, nil), ctx.Validate(s2, v, nil))
+//
+// NOTE that causes must be of type *ValidationError.
+func (ctx *ValidatorContext) AddErrors(k ErrorKind, causes ...error) {
+	var errors []*ValidationError
+	for _, err := range causes {
+		if err != nil {
+			errors = append(errors, err.(*ValidationError))
+		}
+	}
+	if len(errors) > 0 {
+		ctx.vd.addErrors(errors, k)
+	}
+}
+
+// AddErrorf reports validation-error of [kind.Custom], for keyword
+// at given path relative to the schema. The message is localized
+// same as built-in kinds, so format is the key in message catalog.
+func (ctx *ValidatorContext) AddErrorf(keywordPath []string, format string, args ...any) {
+	ctx.vd.addError(&kind.Custom{Keyword: keywordPath, Format: format, Args: args})
 }
 
 // AddErr reports the given err. This is typically used to report
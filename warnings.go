@@ -0,0 +1,349 @@
+package jsonschema
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Warning is a non-fatal issue found during compilation.
+type Warning struct {
+	// SchemaURL is the absolute url of the keyword,
+	// with json-pointer as fragment.
+	SchemaURL string
+
+	// Keyword is the keyword which caused the warning.
+	Keyword string
+
+	// Kind is the kind of warning.
+	Kind WarningKind
+}
+
+func (w *Warning) String() string {
+	return fmt.Sprintf("at %q: %s", w.SchemaURL, w.Kind)
+}
+
+// WarningKind gives details of a [Warning].
+type WarningKind interface {
+	fmt.Stringer
+}
+
+// --
+
+// UnknownKeyword is the WarningKind reported when keyword
+// is not recognized by the dialect of schema.
+// see [Compiler.WarnUnknownKeywords].
+type UnknownKeyword struct {
+	Keyword string
+
+	// Suggestion is a known keyword with similar name.
+	// empty if there is no such keyword.
+	Suggestion string
+}
+
+func (k *UnknownKeyword) String() string {
+	if k.Suggestion != "" {
+		return fmt.Sprintf("unknown keyword %s, did you mean %s", quote(k.Keyword), quote(k.Suggestion))
+	}
+	return fmt.Sprintf("unknown keyword %s", quote(k.Keyword))
+}
+
+// --
+
+// keywordSchemas returns schemas, whose properties
+// are the keywords known in dialect of schema.
+func (c *objCompiler) keywordSchemas() []*Schema {
+	return c.c.keywordSchemas(&c.res.dialect)
+}
+
+// keywordSchemas returns schemas, whose properties
+// are the keywords known in dialect d.
+func (c *Compiler) keywordSchemas(d *dialect) []*Schema {
+	schemas := []*Schema{d.draft.sch}
+	var vocabs []string
+	if d.draft.version >= 2019 && d.vocabs == nil {
+		for name := range d.draft.allVocabs {
+			vocabs = append(vocabs, name)
+		}
+	}
+	vocabs = append(vocabs, d.activeVocabs(c.roots.assertVocabs, c.roots.vocabularies)...)
+	for _, vocab := range vocabs {
+		if sch := d.draft.allVocabs[vocab]; sch != nil {
+			schemas = append(schemas, sch)
+		} else if v := c.roots.vocabularies[vocab]; v != nil && v.Schema != nil {
+			schemas = append(schemas, v.Schema)
+		}
+	}
+	return schemas
+}
+
+func (c *objCompiler) warnUnknownKeywords() {
+	schemas := c.keywordSchemas()
+	known := func(kw string) bool {
+		for _, sch := range schemas {
+			if _, ok := sch.Properties[kw]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var unknown []string
+	for kw := range c.obj {
+		if c.c.warnDraftKeywords && draftKeyword(c.res.dialect.draft, kw) != nil {
+			// reported as DraftKeyword
+			continue
+		}
+		if kw == "nullable" && c.c.nullable {
+			// reported as Nullable
+			continue
+		}
+		if !known(kw) {
+			unknown = append(unknown, kw)
+		}
+	}
+	slices.Sort(unknown)
+	for _, kw := range unknown {
+		suggestion, minDist := "", 3
+		for _, sch := range schemas {
+			for pname := range sch.Properties {
+				if d := editDistance(kw, pname); d < minDist || (d == minDist && pname < suggestion) {
+					suggestion, minDist = pname, d
+				}
+			}
+		}
+		c.c.warn(c.up.format(kw), kw, &UnknownKeyword{Keyword: kw, Suggestion: suggestion})
+	}
+}
+
+// editDistance returns levenshtein distance between s and t.
+func editDistance(s, t string) int {
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
+
+// --
+
+// DraftKeyword is the WarningKind reported when keyword has
+// no effect in the draft of schema, but has effect in other drafts.
+// see [Compiler.WarnDraftKeywords].
+type DraftKeyword struct {
+	Keyword string
+
+	// Draft of the schema.
+	Draft *Draft
+
+	// Drafts in which the keyword has effect.
+	Drafts []*Draft
+}
+
+func (k *DraftKeyword) String() string {
+	var drafts []string
+	for _, d := range k.Drafts {
+		drafts = append(drafts, strconv.Itoa(d.version))
+	}
+	return fmt.Sprintf("keyword %s has no effect in draft %d, it has effect only in drafts %s", quote(k.Keyword), k.Draft.version, strings.Join(drafts, ", "))
+}
+
+var (
+	drafts4to7       = []*Draft{Draft4, Draft6, Draft7}
+	drafts4to2019    = []*Draft{Draft4, Draft6, Draft7, Draft2019}
+	drafts6to2020    = []*Draft{Draft6, Draft7, Draft2019, Draft2020}
+	drafts7to2020    = []*Draft{Draft7, Draft2019, Draft2020}
+	drafts2019to2020 = []*Draft{Draft2019, Draft2020}
+	draftKeywordMap  = map[string][]*Draft{
+		"id":                    {Draft4},
+		"definitions":           drafts4to7,
+		"dependencies":          drafts4to7,
+		"additionalItems":       drafts4to2019,
+		"$id":                   drafts6to2020,
+		"const":                 drafts6to2020,
+		"contains":              drafts6to2020,
+		"propertyNames":         drafts6to2020,
+		"examples":              drafts6to2020,
+		"if":                    drafts7to2020,
+		"then":                  drafts7to2020,
+		"else":                  drafts7to2020,
+		"$comment":              drafts7to2020,
+		"readOnly":              drafts7to2020,
+		"writeOnly":             drafts7to2020,
+		"contentEncoding":       drafts7to2020,
+		"contentMediaType":      drafts7to2020,
+		"$anchor":               drafts2019to2020,
+		"$defs":                 drafts2019to2020,
+		"$vocabulary":           drafts2019to2020,
+		"dependentRequired":     drafts2019to2020,
+		"dependentSchemas":      drafts2019to2020,
+		"unevaluatedProperties": drafts2019to2020,
+		"unevaluatedItems":      drafts2019to2020,
+		"minContains":           drafts2019to2020,
+		"maxContains":           drafts2019to2020,
+		"contentSchema":         drafts2019to2020,
+		"deprecated":            drafts2019to2020,
+		"$recursiveRef":         {Draft2019},
+		"$recursiveAnchor":      {Draft2019},
+		"prefixItems":           {Draft2020},
+		"$dynamicRef":           {Draft2020},
+		"$dynamicAnchor":        {Draft2020},
+	}
+)
+
+// draftKeyword returns drafts in which keyword kw has effect,
+// if it has no effect in draft d. Otherwise returns nil.
+func draftKeyword(d *Draft, kw string) []*Draft {
+	drafts := draftKeywordMap[kw]
+	if drafts == nil || slices.Contains(drafts, d) {
+		return nil
+	}
+	return drafts
+}
+
+func (c *objCompiler) warnDraftKeywords() {
+	d := c.res.dialect.draft
+	var kws []string
+	for kw := range c.obj {
+		kws = append(kws, kw)
+	}
+	slices.Sort(kws)
+	for _, kw := range kws {
+		if drafts := draftKeyword(d, kw); drafts != nil {
+			c.c.warn(c.up.format(kw), kw, &DraftKeyword{Keyword: kw, Draft: d, Drafts: drafts})
+		}
+	}
+}
+
+// --
+
+// UnknownFormat is the WarningKind reported in strict mode,
+// when format is neither builtin nor registered.
+type UnknownFormat struct {
+	Format string
+}
+
+func (k *UnknownFormat) String() string {
+	return fmt.Sprintf("unknown format %s", quote(k.Format))
+}
+
+// AdditionalProperties is the WarningKind reported in strict mode,
+// when schema has `properties` but neither `additionalProperties`
+// nor `unevaluatedProperties`, thus allowing any other property.
+type AdditionalProperties struct{}
+
+func (k *AdditionalProperties) String() string {
+	return "additionalProperties not specified, allows any additional property"
+}
+
+// TypeUnion is the WarningKind reported in strict mode,
+// when `type` has multiple types, other than null.
+type TypeUnion struct {
+	Types []string
+}
+
+func (k *TypeUnion) String() string {
+	return fmt.Sprintf("type union %s is ambiguous", strings.Join(k.Types, ", "))
+}
+
+func (c *objCompiler) warnStrict() {
+	if f := c.strVal("format"); f != nil && *f != "regex" {
+		if c.c.formats[*f] == nil && formats[*f] == nil {
+			c.c.warn(c.up.format("format"), "format", &UnknownFormat{Format: *f})
+		}
+	}
+	if _, ok := c.obj["properties"]; ok {
+		_, ap := c.obj["additionalProperties"]
+		_, up := c.obj["unevaluatedProperties"]
+		if !ap && !up {
+			c.c.warn(c.up.format("properties"), "properties", &AdditionalProperties{})
+		}
+	}
+	if arr, ok := c.obj["type"].([]any); ok {
+		var types []string
+		for _, t := range arr {
+			if s, ok := t.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		if len(types) > 1 {
+			c.c.warn(c.up.format("type"), "type", &TypeUnion{Types: types})
+		}
+	}
+}
+
+// --
+
+// DeprecatedKeyword is the WarningKind reported when keyword
+// is deprecated by the metaschema. see [Compiler.CompileWithWarnings].
+type DeprecatedKeyword struct {
+	Keyword string
+}
+
+func (k *DeprecatedKeyword) String() string {
+	return fmt.Sprintf("keyword %s is deprecated", quote(k.Keyword))
+}
+
+// RefSibling is the WarningKind reported when keyword is ignored,
+// because it is sibling of `$ref` in draft before 2019-09.
+// see [Compiler.CompileWithWarnings].
+type RefSibling struct {
+	Keyword string
+}
+
+func (k *RefSibling) String() string {
+	return fmt.Sprintf("keyword %s is ignored, as sibling of $ref", quote(k.Keyword))
+}
+
+// UnreachableDef is the WarningKind reported when entry of `$defs`
+// or `definitions` is not referenced from the schema compiled.
+// see [Compiler.CompileWithWarnings].
+type UnreachableDef struct {
+	// Keyword is either "$defs" or "definitions".
+	Keyword string
+
+	// Name is the property name of entry.
+	Name string
+}
+
+func (k *UnreachableDef) String() string {
+	return fmt.Sprintf("%s/%s is not referenced", k.Keyword, k.Name)
+}
+
+// OverlappingPatterns is the WarningKind reported when two regexes
+// of `patternProperties` match same property name, in which case the
+// property is validated against both subschemas.
+// see [Compiler.CompileWithWarnings].
+type OverlappingPatterns struct {
+	Patterns [2]string
+
+	// Example is a property name, matched by both patterns.
+	Example string
+}
+
+func (k *OverlappingPatterns) String() string {
+	return fmt.Sprintf("patterns %q and %q overlap, both match %q", k.Patterns[0], k.Patterns[1], k.Example)
+}
+
+// --
+
+// Nullable is the WarningKind reported when `nullable: true` is
+// interpreted as allowing null. see [Compiler.InterpretNullable].
+type Nullable struct{}
+
+func (k *Nullable) String() string {
+	return "nullable is not a json-schema keyword, interpreted as adding null to type"
+}